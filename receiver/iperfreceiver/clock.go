@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import "time"
+
+// clock is the source of time for scrape timestamps, test durations and the waits between
+// retries and server checks, tests replace it with a fake
+type clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) ticker
+	NewTimer(d time.Duration) ticker
+}
+
+// ticker delivers ticks on C until stopped, a timer delivers a single one
+type ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// realClock reads the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) NewTimer(d time.Duration) ticker {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.timer.C
+}
+
+func (t realTimer) Stop() {
+	t.timer.Stop()
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+// fakeClock only moves when advanced, firing the tickers and timers that are due
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) ticker {
+	return c.newTicker(d, false)
+}
+
+// NewTimer is a ticker that fires once
+func (c *fakeClock) NewTimer(d time.Duration) ticker {
+	return c.newTicker(d, true)
+}
+
+func (c *fakeClock) newTicker(d time.Duration, once bool) *fakeTicker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d), once: once}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward, like time.Ticker a ticker drops ticks its reader is not ready for
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		for !t.stopped && !t.next.After(c.now) {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+			t.stopped = t.once
+		}
+	}
+}
+
+// tickerCount returns the number of tickers and timers created so far
+func (c *fakeClock) tickerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.tickers)
+}
+
+type fakeTicker struct {
+	clock   *fakeClock
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	once    bool
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
+
+func TestScrapeOnClock(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+		Targets: []TargetConfig{{
+			Host:     "192.0.2.60",
+			Port:     5201,
+			Protocol: "tcp",
+			Schedule: &ScheduleConfig{Start: "01:00", End: "05:00", Timezone: "UTC"},
+		}},
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	clk := &fakeClock{now: time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)}
+	scraper.clock = clk
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	// The schedule and the timestamps follow the scraper clock
+	for i := 0; i < 2; i++ {
+		metrics, err := scraper.scrape(context.Background())
+		require.NoError(t, err)
+		var skipped bool
+		for j := 0; j < metrics.ResourceMetrics().Len(); j++ {
+			ms := metrics.ResourceMetrics().At(j).ScopeMetrics().At(0).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				if ms.At(k).Name() != "iperf.test.skipped" {
+					continue
+				}
+				skipped = true
+				assert.Equal(t, pcommon.NewTimestampFromTime(clk.Now()), ms.At(k).Gauge().DataPoints().At(0).Timestamp())
+			}
+		}
+		assert.True(t, skipped)
+		clk.Advance(time.Hour)
+	}
+}
@@ -6,8 +6,17 @@ package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collect
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	iperf "github.com/BGrewell/go-iperf"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
 	"go.uber.org/multierr"
 
@@ -21,8 +30,73 @@ var (
 	errInvalidDuration = errors.New("duration must be positive")
 	errInvalidStreams  = errors.New("streams must be positive")
 	errNoTargets       = errors.New("at least one target must be configured")
+	errReverseAndBidir = errors.New("reverse and bidirectional cannot both be enabled")
+	errInvalidCport    = errors.New("client_port must be between 0 and 65535")
+	errPartialAuth     = errors.New("auth_username, auth_password and auth_public_key_path must be set together")
+	errPartialSrvAuth  = errors.New("auth_private_key_path and auth_authorized_users_path must be set together")
+	errInvalidRuntime  = errors.New("max_runtime must not be shorter than duration")
+	errInvalidMaxTests = errors.New("max_concurrent_tests must be positive")
+	errInvalidRetries  = errors.New("retries cannot be negative")
+	errInvalidBackoff  = errors.New("retry_backoff cannot be negative")
+	errFQRateBurst     = errors.New("fq_rate cannot include a burst size")
+	errFQRateSupport   = errors.New("fq_rate is not supported by the linked go-iperf version")
+	errInvalidTos      = errors.New("tos must be between 0 and 255")
+	errPortAndRange    = errors.New("port and port_range cannot both be set")
+	errInvalidConnect  = errors.New("connect_timeout must be positive and shorter than duration")
+	errTestLength      = errors.New("only one of duration, bytes and blocks can be set")
+	errInvalidInterval = errors.New("min_interval cannot be negative")
+	errInvalidAttrConv = errors.New("attribute_convention must be 'iperf' or 'semconv'")
+	errInvalidPps      = errors.New("pps cannot be negative")
+	errPpsProtocol     = errors.New("pps requires the udp protocol")
+	errPpsLength       = errors.New("pps requires a positive length")
+	errPpsBandwidth    = errors.New("pps and bandwidth cannot both be set")
+	errDebugEndpoint   = errors.New("debug_endpoint must be host:port")
+	errInvalidPrecheck = errors.New("precheck_timeout cannot be negative")
+	errDebugOutputSize = errors.New("debug_output_max_bytes must be positive")
+	errInvalidSamples  = fmt.Errorf("samples must be between 1 and %d", maxSamples)
+	errInvalidMSS      = fmt.Errorf("mss cannot be larger than %d", maxMSS)
+	errWindowAndAuto   = errors.New("window and auto_window cannot both be set")
+	errAutoWindowProto = errors.New("auto_window requires the tcp protocol")
+	errAutoWindowBDP   = errors.New("auto_window requires expected_bandwidth and a positive expected_rtt")
 )
 
+// maxSamples bounds the tests run back to back per target and scrape, each one lasts the whole test
+const maxSamples = 10
+
+// maxMSS is the largest segment size iperf3 accepts
+const maxMSS = 9216
+
+// defaultUDPWindow is the socket buffer of UDP tests without window. UDP has no buffer autotuning
+// and the kernel default drops datagrams at high bitrates.
+const defaultUDPWindow = "1M"
+
+// Bounds of the window computed by auto_window
+const (
+	minAutoWindow = 64 * 1024
+	maxAutoWindow = 256 * 1024 * 1024
+)
+
+// availableCongestionControl returns the TCP congestion control algorithms the kernel offers,
+// replaced in tests
+var availableCongestionControl = func() ([]string, error) {
+	data, err := os.ReadFile("/proc/sys/net/ipv4/tcp_available_congestion_control")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// fqRateSetter is implemented by go-iperf clients that can pass --fq-rate to iperf3
+type fqRateSetter interface {
+	SetFQRate(rate string)
+}
+
+// fqRateSupported reports whether the linked go-iperf client can set --fq-rate, replaced in tests
+var fqRateSupported = func() bool {
+	_, ok := any((*iperf.Client)(nil)).(fqRateSetter)
+	return ok
+}
+
 // Config defines the configuration for the iperf receiver
 type Config struct {
 	scraperhelper.ControllerConfig `mapstructure:",squash"`
@@ -36,6 +110,32 @@ type Config struct {
 
 	// ServerPort defines the port to listen on when running as server
 	ServerPort int `mapstructure:"server_port"`
+
+	// MaxConcurrentTests limits how many client tests run at the same time
+	MaxConcurrentTests int `mapstructure:"max_concurrent_tests"`
+
+	// EmitIntervalMetrics records a data point per reporting interval in addition to the end summary
+	EmitIntervalMetrics bool `mapstructure:"emit_interval_metrics"`
+
+	// AuthPrivateKeyPath is the RSA private key used to decrypt client credentials in server mode
+	AuthPrivateKeyPath string `mapstructure:"auth_private_key_path"`
+
+	// AuthAuthorizedUsersPath is the file of authorized users and password hashes in server mode
+	AuthAuthorizedUsersPath string `mapstructure:"auth_authorized_users_path"`
+
+	// AttributeConvention names the server resource attributes iperf.target.* with "iperf" or
+	// server.* following the OpenTelemetry semantic conventions with "semconv"
+	AttributeConvention string `mapstructure:"attribute_convention"`
+
+	// DebugEndpoint is the address serving the last report of each target over HTTP, disabled when empty
+	DebugEndpoint string `mapstructure:"debug_endpoint"`
+
+	// DebugOutput captures the raw iperf3 output of client tests, "log" writes it to the collector log,
+	// any other value is a file it is appended to. Disabled when empty
+	DebugOutput string `mapstructure:"debug_output"`
+
+	// DebugOutputMaxBytes bounds the raw output captured per test, longer output is truncated
+	DebugOutputMaxBytes int `mapstructure:"debug_output_max_bytes"`
 }
 
 // TargetConfig defines the configuration for an individual iperf target
@@ -46,9 +146,40 @@ type TargetConfig struct {
 	// Port is the port number of the iperf3 server
 	Port int `mapstructure:"port"`
 
+	// PortRange is a range of server ports such as "5201-5210", tests rotate through it instead of using Port
+	PortRange string `mapstructure:"port_range"`
+
 	// Duration is the test duration in seconds
 	Duration time.Duration `mapstructure:"duration"`
 
+	// Bytes ends the test after transferring this many bytes instead of after Duration, e.g. "100M"
+	Bytes string `mapstructure:"bytes"`
+
+	// Blocks ends the test after transferring this many blocks instead of after Duration, e.g. "10K"
+	Blocks string `mapstructure:"blocks"`
+
+	// ConnectTimeout bounds how long the client waits to connect to the server (0 uses the OS default)
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+
+	// PrecheckTimeout enables a TCP connect to the server before the test, an unreachable server
+	// is recorded as an error without running the test (0 disables the pre-check)
+	PrecheckTimeout time.Duration `mapstructure:"precheck_timeout"`
+
+	// MaxRuntime is the time after which a running test is aborted
+	MaxRuntime time.Duration `mapstructure:"max_runtime"`
+
+	// Retries is the number of times a failed test is retried before recording an error
+	Retries int `mapstructure:"retries"`
+
+	// RetryBackoff is the initial wait between retries, doubled after each attempt
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+
+	// Samples is the number of tests run back to back per scrape, their bandwidth is aggregated
+	Samples int `mapstructure:"samples"`
+
+	// Aggregation combines the bandwidth of the samples, "median" or "mean"
+	Aggregation string `mapstructure:"aggregation"`
+
 	// Streams is the number of parallel client streams to run
 	Streams int `mapstructure:"streams"`
 
@@ -58,15 +189,44 @@ type TargetConfig struct {
 	// Reverse runs the test in reverse mode (server sends, client receives)
 	Reverse bool `mapstructure:"reverse"`
 
-	// Bandwidth target for UDP tests (bits per second)
+	// Bidirectional runs the test in both directions simultaneously
+	Bidirectional bool `mapstructure:"bidirectional"`
+
+	// Bandwidth is the target bitrate in bits per second, optionally followed by
+	// a burst size in packets, e.g. "10M" or "10M/100"
 	Bandwidth string `mapstructure:"bandwidth"`
 
-	// Window size (socket buffer size)
+	// FQRate is the fair-queue socket pacing rate in bits per second, e.g. "100M"
+	FQRate string `mapstructure:"fq_rate"`
+
+	// AddressFamily pins a dual-stack host to one family, "ip4", "ip6" or "auto"
+	AddressFamily string `mapstructure:"address_family"`
+
+	// Tos is the IP type of service byte set on test packets (DSCP is the upper six bits)
+	Tos int `mapstructure:"tos"`
+
+	// Length is the UDP datagram length in bytes, e.g. "1460" ("0" uses the iperf3 default)
+	Length string `mapstructure:"length"`
+
+	// Pps is the UDP packet rate in packets per second, it sets the bitrate to pps * length * 8.
+	// Requires length, replaces bandwidth
+	Pps int `mapstructure:"pps"`
+
+	// Window size (socket buffer size), "0" leaves it to the kernel
 	Window string `mapstructure:"window"`
 
 	// MSS - Maximum Segment Size
 	MSS int `mapstructure:"mss"`
 
+	// AutoWindow sets the TCP window from the bandwidth-delay product of ExpectedBandwidth and ExpectedRTT
+	AutoWindow bool `mapstructure:"auto_window"`
+
+	// ExpectedBandwidth is the estimated bitrate of the path, e.g. "1G", used by AutoWindow
+	ExpectedBandwidth string `mapstructure:"expected_bandwidth"`
+
+	// ExpectedRTT is the estimated round trip time of the path, used by AutoWindow
+	ExpectedRTT time.Duration `mapstructure:"expected_rtt"`
+
 	// NoDelay disables Nagle's Algorithm
 	NoDelay bool `mapstructure:"no_delay"`
 
@@ -78,6 +238,30 @@ type TargetConfig struct {
 
 	// Congestion algorithm (e.g., cubic, reno)
 	Congestion string `mapstructure:"congestion"`
+
+	// BindAddress is the local IP address the client binds to
+	BindAddress string `mapstructure:"bind_address"`
+
+	// ClientPort pins the client's source port (0 lets the OS choose)
+	ClientPort int `mapstructure:"client_port"`
+
+	// AuthUsername is the username used to authenticate with the iperf3 server
+	AuthUsername string `mapstructure:"auth_username"`
+
+	// AuthPassword is the password used to authenticate with the iperf3 server
+	AuthPassword configopaque.String `mapstructure:"auth_password"`
+
+	// AuthPublicKeyPath is the RSA public key used to encrypt the credentials
+	AuthPublicKeyPath string `mapstructure:"auth_public_key_path"`
+
+	// Tags are added as resource attributes to the metrics of the target
+	Tags map[string]string `mapstructure:"tags"`
+
+	// Schedule restricts the tests to a time window, tests run at every scrape when unset
+	Schedule *ScheduleConfig `mapstructure:"schedule"`
+
+	// MinInterval is the minimum time between the starts of two tests to the same host
+	MinInterval time.Duration `mapstructure:"min_interval"`
 }
 
 // Validate validates the receiver configuration
@@ -89,6 +273,20 @@ func (cfg *Config) Validate() error {
 		err = multierr.Append(err, fmt.Errorf("invalid mode: %s, must be 'client' or 'server'", cfg.Mode))
 	}
 
+	if cfg.AttributeConvention != "" && cfg.AttributeConvention != "iperf" && cfg.AttributeConvention != "semconv" {
+		err = multierr.Append(err, errInvalidAttrConv)
+	}
+
+	if cfg.DebugEndpoint != "" {
+		if _, _, splitErr := net.SplitHostPort(cfg.DebugEndpoint); splitErr != nil {
+			err = multierr.Append(err, errDebugEndpoint)
+		}
+	}
+
+	if cfg.DebugOutput != "" && cfg.DebugOutputMaxBytes <= 0 {
+		err = multierr.Append(err, errDebugOutputSize)
+	}
+
 	// Default to client mode if not specified
 	if cfg.Mode == "" {
 		cfg.Mode = "client"
@@ -99,6 +297,10 @@ func (cfg *Config) Validate() error {
 		if cfg.ServerPort < 1 || cfg.ServerPort > 65535 {
 			err = multierr.Append(err, errInvalidPort)
 		}
+
+		if (cfg.AuthPrivateKeyPath == "") != (cfg.AuthAuthorizedUsersPath == "") {
+			err = multierr.Append(err, errPartialSrvAuth)
+		}
 	}
 
 	// Validate targets for client mode
@@ -107,6 +309,12 @@ func (cfg *Config) Validate() error {
 			err = multierr.Append(err, errNoTargets)
 		}
 
+		if cfg.MaxConcurrentTests < 0 {
+			err = multierr.Append(err, errInvalidMaxTests)
+		} else if cfg.MaxConcurrentTests == 0 {
+			cfg.MaxConcurrentTests = 1 // Default to serialized tests
+		}
+
 		for i, target := range cfg.Targets {
 			if targetErr := target.Validate(); targetErr != nil {
 				err = multierr.Append(err, fmt.Errorf("target[%d]: %w", i, targetErr))
@@ -125,12 +333,91 @@ func (cfg *TargetConfig) Validate() error {
 		err = multierr.Append(err, errInvalidHost)
 	}
 
-	if cfg.Port < 1 || cfg.Port > 65535 {
+	if cfg.PortRange != "" {
+		if _, _, rangeErr := parsePortRange(cfg.PortRange); rangeErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid port_range: %w", rangeErr))
+		}
+		if cfg.Port != 0 {
+			err = multierr.Append(err, errPortAndRange)
+		}
+	} else if cfg.Port < 1 || cfg.Port > 65535 {
 		err = multierr.Append(err, errInvalidPort)
 	}
 
-	if cfg.Duration <= 0 {
-		cfg.Duration = 10 * time.Second // Default duration
+	// Validate the test length, a test ends after a duration, a number of bytes or a number of blocks
+	lengths := 0
+	if cfg.Duration > 0 {
+		lengths++
+	}
+	if cfg.Bytes != "" {
+		lengths++
+		if _, parseErr := parseByteSize(cfg.Bytes); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid bytes: %w", parseErr))
+		}
+	}
+	if cfg.Blocks != "" {
+		lengths++
+		if _, parseErr := parseByteSize(cfg.Blocks); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid blocks: %w", parseErr))
+		}
+	}
+	if lengths > 1 {
+		err = multierr.Append(err, errTestLength)
+	}
+
+	if cfg.termination() == "time" {
+		if cfg.Duration <= 0 {
+			cfg.Duration = 10 * time.Second // Default duration
+		}
+
+		if cfg.ConnectTimeout < 0 || cfg.ConnectTimeout >= cfg.Duration {
+			err = multierr.Append(err, errInvalidConnect)
+		}
+
+		if cfg.MaxRuntime == 0 {
+			cfg.MaxRuntime = cfg.Duration + 30*time.Second // Leave room for setup and teardown
+		} else if cfg.MaxRuntime < cfg.Duration {
+			err = multierr.Append(err, errInvalidRuntime)
+		}
+	} else {
+		// The runtime of a fixed-volume test depends on the bandwidth
+		if cfg.ConnectTimeout < 0 {
+			err = multierr.Append(err, errInvalidConnect)
+		}
+
+		if cfg.MaxRuntime == 0 {
+			cfg.MaxRuntime = 5 * time.Minute // Default limit for fixed-volume tests
+		} else if cfg.MaxRuntime < 0 {
+			err = multierr.Append(err, errInvalidRuntime)
+		}
+	}
+
+	if cfg.PrecheckTimeout < 0 {
+		err = multierr.Append(err, errInvalidPrecheck)
+	}
+
+	if cfg.Retries < 0 {
+		err = multierr.Append(err, errInvalidRetries)
+	}
+
+	if cfg.RetryBackoff < 0 {
+		err = multierr.Append(err, errInvalidBackoff)
+	} else if cfg.RetryBackoff == 0 && cfg.Retries > 0 {
+		cfg.RetryBackoff = time.Second // Default backoff
+	}
+
+	if cfg.Samples == 0 {
+		cfg.Samples = 1 // Default to a single test
+	} else if cfg.Samples < 0 || cfg.Samples > maxSamples {
+		err = multierr.Append(err, errInvalidSamples)
+	}
+
+	switch cfg.Aggregation {
+	case "":
+		cfg.Aggregation = "median" // Default to the aggregation least sensitive to outliers
+	case "median", "mean":
+	default:
+		err = multierr.Append(err, fmt.Errorf("invalid aggregation: %s, must be median or mean", cfg.Aggregation))
 	}
 
 	if cfg.Streams < 0 {
@@ -146,15 +433,303 @@ func (cfg *TargetConfig) Validate() error {
 		err = multierr.Append(err, fmt.Errorf("invalid protocol: %s, must be tcp, udp, or sctp", cfg.Protocol))
 	}
 
+	// Validate address family
+	switch cfg.AddressFamily {
+	case "":
+		cfg.AddressFamily = "auto" // Default to whatever the resolver returns first
+	case "auto", "ip4", "ip6":
+	default:
+		err = multierr.Append(err, fmt.Errorf("invalid address_family: %s, must be ip4, ip6, or auto", cfg.AddressFamily))
+	}
+
+	if cfg.Reverse && cfg.Bidirectional {
+		err = multierr.Append(err, errReverseAndBidir)
+	}
+
+	// Validate bitrate and burst syntax
+	if cfg.Bandwidth != "" {
+		if _, _, parseErr := parseBitrate(cfg.Bandwidth); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid bandwidth: %w", parseErr))
+		}
+	}
+
+	// Validate fair-queue pacing, which needs a go-iperf client that passes it on to iperf3
+	if cfg.FQRate != "" {
+		if _, burst, parseErr := parseBitrate(cfg.FQRate); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid fq_rate: %w", parseErr))
+		} else if burst != 0 {
+			err = multierr.Append(err, errFQRateBurst)
+		}
+		if !fqRateSupported() {
+			err = multierr.Append(err, errFQRateSupport)
+		}
+	}
+
+	if cfg.Tos < 0 || cfg.Tos > 255 {
+		err = multierr.Append(err, errInvalidTos)
+	}
+
+	// Validate UDP datagram length
+	if cfg.Length != "" {
+		if _, parseErr := parseByteSize(cfg.Length); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid length: %w", parseErr))
+		}
+	}
+
+	// A packet rate is turned into a bitrate with the datagram length
+	if cfg.Pps < 0 {
+		err = multierr.Append(err, errInvalidPps)
+	} else if cfg.Pps > 0 {
+		if cfg.Protocol != "udp" {
+			err = multierr.Append(err, errPpsProtocol)
+		}
+		if length, parseErr := parseByteSize(cfg.Length); cfg.Length == "" || (parseErr == nil && length <= 0) {
+			err = multierr.Append(err, errPpsLength)
+		}
+		if cfg.Bandwidth != "" {
+			err = multierr.Append(err, errPpsBandwidth)
+		}
+	}
+
+	// Validate the congestion control algorithm, only Linux lists the available ones
+	if cfg.Congestion != "" && runtime.GOOS == "linux" {
+		if congestionErr := validateCongestion(cfg.Congestion); congestionErr != nil {
+			err = multierr.Append(err, congestionErr)
+		}
+	}
+
+	// Validate bind address
+	if cfg.BindAddress != "" && net.ParseIP(cfg.BindAddress) == nil {
+		err = multierr.Append(err, fmt.Errorf("invalid bind_address: %s, must be an IP address", cfg.BindAddress))
+	}
+
+	if cfg.ClientPort < 0 || cfg.ClientPort > 65535 {
+		err = multierr.Append(err, errInvalidCport)
+	}
+
+	if cfg.MinInterval < 0 {
+		err = multierr.Append(err, errInvalidInterval)
+	}
+
+	if cfg.Schedule != nil {
+		if scheduleErr := cfg.Schedule.Validate(); scheduleErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid schedule: %w", scheduleErr))
+		}
+	}
+
+	// Tags must not replace the built-in attributes
+	for key := range cfg.Tags {
+		if key == "" || strings.HasPrefix(key, "iperf.") {
+			err = multierr.Append(err, fmt.Errorf("invalid tag %q, tags cannot be empty or start with iperf.", key))
+		}
+	}
+
+	// Validate authentication, all client credentials or none
+	authSet := 0
+	for _, v := range []string{cfg.AuthUsername, string(cfg.AuthPassword), cfg.AuthPublicKeyPath} {
+		if v != "" {
+			authSet++
+		}
+	}
+	if authSet != 0 && authSet != 3 {
+		err = multierr.Append(err, errPartialAuth)
+	}
+
 	// Validate omit seconds
 	if cfg.OmitSec < 0 {
 		err = multierr.Append(err, fmt.Errorf("omit seconds cannot be negative"))
 	}
 
-	// Validate MSS
+	// Validate MSS, without it the kernel uses the path MTU
 	if cfg.MSS < 0 {
 		err = multierr.Append(err, fmt.Errorf("MSS cannot be negative"))
+	} else if cfg.MSS > maxMSS {
+		err = multierr.Append(err, errInvalidMSS)
+	}
+
+	// Validate the window options, the window tests run with is computed from them by window()
+	if windowErr := cfg.validateWindow(); windowErr != nil {
+		err = multierr.Append(err, windowErr)
 	}
 
 	return err
-}
\ No newline at end of file
+}
+
+// validateWindow checks the window options, auto_window needs a TCP test with the expected
+// bandwidth and round trip time of the path and no fixed window
+func (cfg *TargetConfig) validateWindow() error {
+	var err error
+	if cfg.Window != "" {
+		if _, parseErr := parseByteSize(cfg.Window); parseErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid window: %w", parseErr))
+		}
+	}
+
+	if !cfg.AutoWindow {
+		return err
+	}
+
+	if cfg.Window != "" {
+		err = multierr.Append(err, errWindowAndAuto)
+	}
+	if cfg.Protocol != "tcp" {
+		err = multierr.Append(err, errAutoWindowProto)
+	}
+	if cfg.ExpectedBandwidth == "" || cfg.ExpectedRTT <= 0 {
+		return multierr.Append(err, errAutoWindowBDP)
+	}
+	bandwidth, burst, parseErr := parseBitrate(cfg.ExpectedBandwidth)
+	if parseErr != nil || burst != 0 || bandwidth == 0 {
+		return multierr.Append(err, fmt.Errorf("invalid expected_bandwidth: %q, must be a positive bitrate", cfg.ExpectedBandwidth))
+	}
+	return err
+}
+
+// window returns the window a test runs with when none is configured: the bandwidth-delay product
+// with auto_window, defaultUDPWindow for UDP tests. TCP tests are left to the kernel's buffer
+// autotuning, which a fixed window disables, "" leaves the window unset.
+func (cfg *TargetConfig) window() string {
+	switch {
+	case cfg.Window != "":
+		return cfg.Window
+	case cfg.AutoWindow:
+		bandwidth, _, err := parseBitrate(cfg.ExpectedBandwidth)
+		if err != nil {
+			return ""
+		}
+		return strconv.FormatInt(autoWindow(bandwidth, cfg.ExpectedRTT, cfg.Streams)/1024, 10) + "K"
+	case cfg.Protocol == "udp":
+		return defaultUDPWindow
+	default:
+		return ""
+	}
+}
+
+// autoWindow returns the per-stream window in bytes for a path of the given bitrate and round trip
+// time. The streams share the bandwidth-delay product, which is doubled so that the window still
+// covers the path when queueing raises the RTT under load. The window is rounded up to whole KiB
+// and kept between minAutoWindow and maxAutoWindow.
+func autoWindow(bandwidth int64, rtt time.Duration, streams int) int64 {
+	bdp := float64(bandwidth) / 8 * rtt.Seconds()
+	window := int64(math.Ceil(2*bdp/float64(max(streams, 1))/1024)) * 1024
+	return min(max(window, minAutoWindow), maxAutoWindow)
+}
+
+// validateCongestion checks that the kernel offers the congestion control algorithm
+func validateCongestion(algorithm string) error {
+	available, err := availableCongestionControl()
+	if err != nil {
+		// Without the list the algorithm is checked when the test runs
+		return nil
+	}
+	if slices.Contains(available, algorithm) {
+		return nil
+	}
+	return fmt.Errorf("invalid congestion: %s, must be one of %s", algorithm, strings.Join(available, ", "))
+}
+
+// termination returns how the test length is bounded: "time", "bytes" or "blocks"
+func (cfg *TargetConfig) termination() string {
+	switch {
+	case cfg.Bytes != "":
+		return "bytes"
+	case cfg.Blocks != "":
+		return "blocks"
+	default:
+		return "time"
+	}
+}
+
+// bitrate returns the target bitrate of the test, computed from pps and length when a packet rate is set
+func (cfg *TargetConfig) bitrate() string {
+	if cfg.Pps <= 0 {
+		return cfg.Bandwidth
+	}
+	length, err := parseByteSize(cfg.Length)
+	if err != nil || length <= 0 {
+		return cfg.Bandwidth
+	}
+	return strconv.FormatInt(int64(cfg.Pps)*length*8, 10)
+}
+
+// parseByteSize parses an iperf3 style byte size such as "1460", "64K" or "1M"
+func parseByteSize(size string) (int64, error) {
+	multiplier := int64(1)
+	value := strings.TrimSpace(size)
+	if value == "" {
+		return 0, fmt.Errorf("byte size cannot be empty")
+	}
+
+	switch value[len(value)-1] {
+	case 'k', 'K':
+		multiplier = 1024
+	case 'm', 'M':
+		multiplier = 1024 * 1024
+	case 'g', 'G':
+		multiplier = 1024 * 1024 * 1024
+	}
+	if multiplier > 1 {
+		value = value[:len(value)-1]
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a valid byte size", size)
+	}
+	return n * multiplier, nil
+}
+
+// parsePortRange parses a port range such as "5201-5210", returning its inclusive bounds
+func parsePortRange(portRange string) (int, int, error) {
+	lowStr, highStr, ok := strings.Cut(strings.TrimSpace(portRange), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%q must be of the form low-high", portRange)
+	}
+
+	low, lowErr := strconv.Atoi(strings.TrimSpace(lowStr))
+	high, highErr := strconv.Atoi(strings.TrimSpace(highStr))
+	if lowErr != nil || highErr != nil || low < 1 || high > 65535 {
+		return 0, 0, fmt.Errorf("%q must contain ports between 1 and 65535", portRange)
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("%q has a lower bound greater than its upper bound", portRange)
+	}
+	return low, high, nil
+}
+
+// parseBitrate parses an iperf3 style bitrate such as "100K", "10M" or "10M/100",
+// returning the rate in bits per second and the burst size in packets
+func parseBitrate(bitrate string) (int64, int, error) {
+	rate, burst, hasBurst := strings.Cut(strings.TrimSpace(bitrate), "/")
+	if rate == "" {
+		return 0, 0, fmt.Errorf("%q is not a valid bitrate", bitrate)
+	}
+
+	// Rates use decimal multipliers, unlike byte sizes
+	multiplier := int64(1)
+	switch rate[len(rate)-1] {
+	case 'k', 'K':
+		multiplier = 1000
+	case 'm', 'M':
+		multiplier = 1000 * 1000
+	case 'g', 'G':
+		multiplier = 1000 * 1000 * 1000
+	}
+	if multiplier > 1 {
+		rate = rate[:len(rate)-1]
+	}
+
+	n, err := strconv.ParseInt(rate, 10, 64)
+	if err != nil || n < 0 {
+		return 0, 0, fmt.Errorf("%q is not a valid bitrate", bitrate)
+	}
+
+	packets := 0
+	if hasBurst {
+		packets, err = strconv.Atoi(burst)
+		if err != nil || packets <= 0 {
+			return 0, 0, fmt.Errorf("%q has an invalid burst size, must be a positive number of packets", bitrate)
+		}
+	}
+	return n * multiplier, packets, nil
+}
@@ -4,6 +4,8 @@
 package iperfreceiver
 
 import (
+	"os"
+	"runtime"
 	"testing"
 	"time"
 
@@ -41,6 +43,25 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "valid server config with authentication",
+			cfg: &Config{
+				Mode:                    "server",
+				ServerPort:              5201,
+				AuthPrivateKeyPath:      "/etc/iperf3/private.pem",
+				AuthAuthorizedUsersPath: "/etc/iperf3/credentials.csv",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "server with partial authentication",
+			cfg: &Config{
+				Mode:               "server",
+				ServerPort:         5201,
+				AuthPrivateKeyPath: "/etc/iperf3/private.pem",
+			},
+			expectedErr: "auth_private_key_path and auth_authorized_users_path must be set together",
+		},
 		{
 			name: "invalid mode",
 			cfg: &Config{
@@ -48,6 +69,33 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expectedErr: "invalid mode: invalid",
 		},
+		{
+			name: "invalid attribute convention",
+			cfg: &Config{
+				Mode:                "server",
+				ServerPort:          5201,
+				AttributeConvention: "otel",
+			},
+			expectedErr: "attribute_convention must be 'iperf' or 'semconv'",
+		},
+		{
+			name: "invalid debug endpoint",
+			cfg: &Config{
+				Mode:          "server",
+				ServerPort:    5201,
+				DebugEndpoint: "localhost",
+			},
+			expectedErr: "debug_endpoint must be host:port",
+		},
+		{
+			name: "debug output without size",
+			cfg: &Config{
+				Mode:        "server",
+				ServerPort:  5201,
+				DebugOutput: "log",
+			},
+			expectedErr: "debug_output_max_bytes must be positive",
+		},
 		{
 			name: "client mode without targets",
 			cfg: &Config{
@@ -56,6 +104,20 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expectedErr: "at least one target must be configured",
 		},
+		{
+			name: "negative max concurrent tests",
+			cfg: &Config{
+				Mode:               "client",
+				MaxConcurrentTests: -1,
+				Targets: []TargetConfig{
+					{
+						Host: "localhost",
+						Port: 5201,
+					},
+				},
+			},
+			expectedErr: "max_concurrent_tests must be positive",
+		},
 		{
 			name: "server mode with invalid port",
 			cfg: &Config{
@@ -211,6 +273,346 @@ func TestTargetConfigValidate(t *testing.T) {
 			},
 			expectedErr: "port must be between 1 and 65535",
 		},
+		{
+			name: "valid bidirectional config",
+			cfg: &TargetConfig{
+				Host:          "localhost",
+				Port:          5201,
+				Bidirectional: true,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "reverse and bidirectional",
+			cfg: &TargetConfig{
+				Host:          "localhost",
+				Port:          5201,
+				Reverse:       true,
+				Bidirectional: true,
+			},
+			expectedErr: "reverse and bidirectional cannot both be enabled",
+		},
+		{
+			name: "valid bind address and client port",
+			cfg: &TargetConfig{
+				Host:        "localhost",
+				Port:        5201,
+				BindAddress: "192.168.1.10",
+				ClientPort:  5300,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "invalid bind address",
+			cfg: &TargetConfig{
+				Host:        "localhost",
+				Port:        5201,
+				BindAddress: "eth0",
+			},
+			expectedErr: "invalid bind_address: eth0",
+		},
+		{
+			name: "invalid client port",
+			cfg: &TargetConfig{
+				Host:       "localhost",
+				Port:       5201,
+				ClientPort: 70000,
+			},
+			expectedErr: "client_port must be between 0 and 65535",
+		},
+		{
+			name: "valid authentication",
+			cfg: &TargetConfig{
+				Host:              "localhost",
+				Port:              5201,
+				AuthUsername:      "user",
+				AuthPassword:      "secret",
+				AuthPublicKeyPath: "/etc/iperf3/public.pem",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "partial authentication",
+			cfg: &TargetConfig{
+				Host:         "localhost",
+				Port:         5201,
+				AuthUsername: "user",
+			},
+			expectedErr: "auth_username, auth_password and auth_public_key_path must be set together",
+		},
+		{
+			name: "valid UDP length",
+			cfg: &TargetConfig{
+				Host:     "localhost",
+				Port:     5201,
+				Protocol: "udp",
+				Length:   "1460",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "invalid UDP length",
+			cfg: &TargetConfig{
+				Host:     "localhost",
+				Port:     5201,
+				Protocol: "udp",
+				Length:   "large",
+			},
+			expectedErr: "invalid length",
+		},
+		{
+			name: "valid UDP packet rate",
+			cfg: &TargetConfig{
+				Host:     "localhost",
+				Port:     5201,
+				Protocol: "udp",
+				Length:   "160",
+				Pps:      50,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "packet rate without length",
+			cfg: &TargetConfig{
+				Host:     "localhost",
+				Port:     5201,
+				Protocol: "udp",
+				Pps:      50,
+			},
+			expectedErr: "pps requires a positive length",
+		},
+		{
+			name: "packet rate with bandwidth over tcp",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				Port:      5201,
+				Length:    "160",
+				Pps:       50,
+				Bandwidth: "64K",
+			},
+			expectedErr: "pps requires the udp protocol; pps and bandwidth cannot both be set",
+		},
+		{
+			name: "max runtime shorter than duration",
+			cfg: &TargetConfig{
+				Host:       "localhost",
+				Port:       5201,
+				Duration:   30 * time.Second,
+				MaxRuntime: 10 * time.Second,
+			},
+			expectedErr: "max_runtime must not be shorter than duration",
+		},
+		{
+			name: "valid retries",
+			cfg: &TargetConfig{
+				Host:    "localhost",
+				Port:    5201,
+				Retries: 3,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "negative retries",
+			cfg: &TargetConfig{
+				Host:    "localhost",
+				Port:    5201,
+				Retries: -1,
+			},
+			expectedErr: "retries cannot be negative",
+		},
+		{
+			name: "negative retry backoff",
+			cfg: &TargetConfig{
+				Host:         "localhost",
+				Port:         5201,
+				Retries:      1,
+				RetryBackoff: -time.Second,
+			},
+			expectedErr: "retry_backoff cannot be negative",
+		},
+		{
+			name: "valid samples",
+			cfg: &TargetConfig{
+				Host:        "localhost",
+				Port:        5201,
+				Samples:     5,
+				Aggregation: "mean",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "too many samples",
+			cfg: &TargetConfig{
+				Host:    "localhost",
+				Port:    5201,
+				Samples: 11,
+			},
+			expectedErr: "samples must be between 1 and 10",
+		},
+		{
+			name: "negative samples",
+			cfg: &TargetConfig{
+				Host:    "localhost",
+				Port:    5201,
+				Samples: -1,
+			},
+			expectedErr: "samples must be between 1 and 10",
+		},
+		{
+			name: "invalid aggregation",
+			cfg: &TargetConfig{
+				Host:        "localhost",
+				Port:        5201,
+				Aggregation: "max",
+			},
+			expectedErr: "invalid aggregation: max, must be median or mean",
+		},
+		{
+			name: "valid TCP bitrate with burst",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				Port:      5201,
+				Protocol:  "tcp",
+				Bandwidth: "10M/100",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "invalid bandwidth",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				Port:      5201,
+				Bandwidth: "fast",
+			},
+			expectedErr: "invalid bandwidth",
+		},
+		{
+			name: "invalid burst size",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				Port:      5201,
+				Bandwidth: "10M/0",
+			},
+			expectedErr: "invalid burst size",
+		},
+		{
+			name: "invalid fq_rate",
+			cfg: &TargetConfig{
+				Host:   "localhost",
+				Port:   5201,
+				FQRate: "fast",
+			},
+			expectedErr: "invalid fq_rate",
+		},
+		{
+			name: "fq_rate with burst",
+			cfg: &TargetConfig{
+				Host:   "localhost",
+				Port:   5201,
+				FQRate: "100M/10",
+			},
+			expectedErr: "fq_rate cannot include a burst size",
+		},
+		{
+			name: "valid UDP with tos",
+			cfg: &TargetConfig{
+				Host:     "localhost",
+				Port:     5201,
+				Protocol: "udp",
+				Tos:      184,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "tos out of range",
+			cfg: &TargetConfig{
+				Host: "localhost",
+				Port: 5201,
+				Tos:  256,
+			},
+			expectedErr: "tos must be between 0 and 255",
+		},
+		{
+			name: "valid ip6 address family",
+			cfg: &TargetConfig{
+				Host:          "localhost",
+				Port:          5201,
+				AddressFamily: "ip6",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "invalid address family",
+			cfg: &TargetConfig{
+				Host:          "localhost",
+				Port:          5201,
+				AddressFamily: "ipx",
+			},
+			expectedErr: "invalid address_family: ipx",
+		},
+		{
+			name: "valid port range",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				PortRange: "5201-5210",
+			},
+			expectedErr: "",
+		},
+		{
+			name: "port range bounds reversed",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				PortRange: "5210-5201",
+			},
+			expectedErr: "invalid port_range",
+		},
+		{
+			name: "port and port range",
+			cfg: &TargetConfig{
+				Host:      "localhost",
+				Port:      5201,
+				PortRange: "5201-5210",
+			},
+			expectedErr: "port and port_range cannot both be set",
+		},
+		{
+			name: "valid connect timeout",
+			cfg: &TargetConfig{
+				Host:           "localhost",
+				Port:           5201,
+				Duration:       10 * time.Second,
+				ConnectTimeout: 3 * time.Second,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "connect timeout not shorter than duration",
+			cfg: &TargetConfig{
+				Host:           "localhost",
+				Port:           5201,
+				Duration:       5 * time.Second,
+				ConnectTimeout: 5 * time.Second,
+			},
+			expectedErr: "connect_timeout must be positive and shorter than duration",
+		},
+		{
+			name: "negative connect timeout",
+			cfg: &TargetConfig{
+				Host:           "localhost",
+				Port:           5201,
+				ConnectTimeout: -time.Second,
+			},
+			expectedErr: "connect_timeout must be positive and shorter than duration",
+		},
+		{
+			name: "negative precheck timeout",
+			cfg: &TargetConfig{
+				Host:            "localhost",
+				Port:            5201,
+				PrecheckTimeout: -time.Second,
+			},
+			expectedErr: "precheck_timeout cannot be negative",
+		},
 		{
 			name: "negative streams",
 			cfg: &TargetConfig{
@@ -220,6 +622,33 @@ func TestTargetConfigValidate(t *testing.T) {
 			},
 			expectedErr: "streams must be positive",
 		},
+		{
+			name: "negative min interval",
+			cfg: &TargetConfig{
+				Host:        "localhost",
+				Port:        5201,
+				MinInterval: -time.Minute,
+			},
+			expectedErr: "min_interval cannot be negative",
+		},
+		{
+			name: "valid tags",
+			cfg: &TargetConfig{
+				Host: "localhost",
+				Port: 5201,
+				Tags: map[string]string{"site": "fra1", "role": "edge"},
+			},
+			expectedErr: "",
+		},
+		{
+			name: "tag colliding with built-in attribute",
+			cfg: &TargetConfig{
+				Host: "localhost",
+				Port: 5201,
+				Tags: map[string]string{"iperf.target.host": "other"},
+			},
+			expectedErr: `invalid tag "iperf.target.host", tags cannot be empty or start with iperf.`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -237,10 +666,313 @@ func TestTargetConfigValidate(t *testing.T) {
 				if tt.cfg.Protocol == "" {
 					assert.Equal(t, "tcp", tt.cfg.Protocol)
 				}
+				assert.GreaterOrEqual(t, tt.cfg.MaxRuntime, tt.cfg.Duration)
+				if tt.cfg.Retries > 0 {
+					assert.Positive(t, tt.cfg.RetryBackoff)
+				}
+				assert.Positive(t, tt.cfg.Samples)
+				assert.Contains(t, []string{"median", "mean"}, tt.cfg.Aggregation)
 			} else {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedErr)
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestTargetConfigWindowDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *TargetConfig
+		wantWindow  string
+		expectedErr string
+	}{
+		{
+			name:       "tcp keeps kernel autotuning",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201},
+			wantWindow: "",
+		},
+		{
+			name:       "udp default",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201, Protocol: "udp"},
+			wantWindow: "1M",
+		},
+		{
+			name:       "udp kernel default",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201, Protocol: "udp", Window: "0"},
+			wantWindow: "0",
+		},
+		{
+			name:       "auto window",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201, AutoWindow: true, ExpectedBandwidth: "1G", ExpectedRTT: 20 * time.Millisecond},
+			wantWindow: "4883K",
+		},
+		{
+			name:       "auto window shared by streams",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201, Streams: 4, AutoWindow: true, ExpectedBandwidth: "1G", ExpectedRTT: 20 * time.Millisecond},
+			wantWindow: "1221K",
+		},
+		{
+			name:       "auto window minimum",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201, AutoWindow: true, ExpectedBandwidth: "10M", ExpectedRTT: time.Millisecond},
+			wantWindow: "64K",
+		},
+		{
+			name:       "auto window maximum",
+			cfg:        &TargetConfig{Host: "localhost", Port: 5201, AutoWindow: true, ExpectedBandwidth: "100G", ExpectedRTT: time.Second},
+			wantWindow: "262144K",
+		},
+		{
+			name:        "auto window and window",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, Window: "4M", AutoWindow: true, ExpectedBandwidth: "1G", ExpectedRTT: 20 * time.Millisecond},
+			expectedErr: "window and auto_window cannot both be set",
+		},
+		{
+			name:        "auto window udp",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, Protocol: "udp", AutoWindow: true, ExpectedBandwidth: "1G", ExpectedRTT: 20 * time.Millisecond},
+			expectedErr: "auto_window requires the tcp protocol",
+		},
+		{
+			name:        "auto window without rtt",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, AutoWindow: true, ExpectedBandwidth: "1G"},
+			expectedErr: "auto_window requires expected_bandwidth and a positive expected_rtt",
+		},
+		{
+			name:        "auto window invalid bandwidth",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, AutoWindow: true, ExpectedBandwidth: "1G/10", ExpectedRTT: 20 * time.Millisecond},
+			expectedErr: "invalid expected_bandwidth: \"1G/10\", must be a positive bitrate",
+		},
+		{
+			name:        "invalid window",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, Window: "big"},
+			expectedErr: "invalid window: \"big\" is not a valid byte size",
+		},
+		{
+			name:        "mss too large",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, MSS: 9217},
+			expectedErr: "mss cannot be larger than 9216",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWindow, tt.cfg.window())
+
+			// Validation leaves the configured window alone, validating again gives the same result
+			require.NoError(t, tt.cfg.Validate())
+			assert.Equal(t, tt.wantWindow, tt.cfg.window())
+		})
+	}
+}
+
+func TestTargetConfigTermination(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *TargetConfig
+		wantTermination string
+		wantMaxRuntime  time.Duration
+		expectedErr     string
+	}{
+		{
+			name:            "default duration",
+			cfg:             &TargetConfig{Host: "localhost", Port: 5201},
+			wantTermination: "time",
+			wantMaxRuntime:  40 * time.Second,
+		},
+		{
+			name:            "bytes",
+			cfg:             &TargetConfig{Host: "localhost", Port: 5201, Bytes: "100M"},
+			wantTermination: "bytes",
+			wantMaxRuntime:  5 * time.Minute,
+		},
+		{
+			name:            "blocks with connect timeout",
+			cfg:             &TargetConfig{Host: "localhost", Port: 5201, Blocks: "10K", ConnectTimeout: 3 * time.Second, MaxRuntime: time.Minute},
+			wantTermination: "blocks",
+			wantMaxRuntime:  time.Minute,
+		},
+		{
+			name:        "duration and bytes",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, Duration: 10 * time.Second, Bytes: "100M"},
+			expectedErr: "only one of duration, bytes and blocks can be set",
+		},
+		{
+			name:        "bytes and blocks",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, Bytes: "100M", Blocks: "10K"},
+			expectedErr: "only one of duration, bytes and blocks can be set",
+		},
+		{
+			name:        "invalid bytes",
+			cfg:         &TargetConfig{Host: "localhost", Port: 5201, Bytes: "lots"},
+			expectedErr: "invalid bytes: \"lots\" is not a valid byte size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTermination, tt.cfg.termination())
+			assert.Equal(t, tt.wantMaxRuntime, tt.cfg.MaxRuntime)
+		})
+	}
+}
+
+func TestValidateCongestion(t *testing.T) {
+	original := availableCongestionControl
+	t.Cleanup(func() { availableCongestionControl = original })
+	availableCongestionControl = func() ([]string, error) {
+		return []string{"reno", "cubic", "bbr"}, nil
+	}
+
+	assert.NoError(t, validateCongestion("cubic"))
+	assert.EqualError(t, validateCongestion("cubik"), "invalid congestion: cubik, must be one of reno, cubic, bbr")
+
+	// Targets are validated against the host only on Linux
+	cfg := &TargetConfig{Host: "localhost", Port: 5201, Congestion: "cubik"}
+	if runtime.GOOS == "linux" {
+		assert.ErrorContains(t, cfg.Validate(), "invalid congestion: cubik")
+	} else {
+		assert.NoError(t, cfg.Validate())
+	}
+
+	// The check is skipped when the host does not list its algorithms
+	availableCongestionControl = func() ([]string, error) {
+		return nil, os.ErrNotExist
+	}
+	assert.NoError(t, validateCongestion("cubik"))
+}
+
+func TestValidateFQRateSupport(t *testing.T) {
+	original := fqRateSupported
+	t.Cleanup(func() { fqRateSupported = original })
+
+	cfg := &TargetConfig{Host: "localhost", Port: 5201, FQRate: "100M"}
+
+	fqRateSupported = func() bool { return true }
+	assert.NoError(t, cfg.Validate())
+
+	// Fail at startup rather than silently testing without pacing
+	fqRateSupported = func() bool { return false }
+	assert.ErrorIs(t, cfg.Validate(), errFQRateSupport)
+
+	// Targets without fq_rate do not depend on it
+	cfg.FQRate = ""
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "0", expected: 0},
+		{input: "1460", expected: 1460},
+		{input: "64K", expected: 64 * 1024},
+		{input: "1m", expected: 1024 * 1024},
+		{input: "2G", expected: 2 * 1024 * 1024 * 1024},
+		{input: "", wantErr: true},
+		{input: "K", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "1.5M", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := parseByteSize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
+
+func TestParseBitrate(t *testing.T) {
+	tests := []struct {
+		input   string
+		rate    int64
+		burst   int
+		wantErr bool
+	}{
+		{input: "0", rate: 0},
+		{input: "100K", rate: 100 * 1000},
+		{input: "10M", rate: 10 * 1000 * 1000},
+		{input: "1G/10", rate: 1000 * 1000 * 1000, burst: 10},
+		{input: "10M/100", rate: 10 * 1000 * 1000, burst: 100},
+		{input: "", wantErr: true},
+		{input: "/100", wantErr: true},
+		{input: "10M/", wantErr: true},
+		{input: "10M/-1", wantErr: true},
+		{input: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rate, burst, err := parseBitrate(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rate, rate)
+			assert.Equal(t, tt.burst, burst)
+		})
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		low     int
+		high    int
+		wantErr bool
+	}{
+		{input: "5201-5210", low: 5201, high: 5210},
+		{input: "5201-5201", low: 5201, high: 5201},
+		{input: " 5201 - 5202 ", low: 5201, high: 5202},
+		{input: "5201", wantErr: true},
+		{input: "5210-5201", wantErr: true},
+		{input: "0-10", wantErr: true},
+		{input: "65535-65536", wantErr: true},
+		{input: "a-b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			low, high, err := parsePortRange(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.low, low)
+			assert.Equal(t, tt.high, high)
+		})
+	}
+}
+
+func TestTargetBitrate(t *testing.T) {
+	// 50 packets of 160 bytes per second, like a G.711 voice call with 20ms frames
+	target := TargetConfig{Protocol: "udp", Length: "160", Pps: 50}
+	assert.Equal(t, "64000", target.bitrate())
+
+	target = TargetConfig{Protocol: "udp", Length: "1K", Pps: 1000}
+	assert.Equal(t, "8192000", target.bitrate())
+
+	target = TargetConfig{Protocol: "udp", Bandwidth: "10M/100"}
+	assert.Equal(t, "10M/100", target.bitrate())
+}
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tcpEstablished is the state of an established connection in /proc/net/tcp
+const tcpEstablished = "01"
+
+// procNetTCP lists the kernel's TCP sockets, replaced in tests
+var procNetTCP = []string{"/proc/net/tcp", "/proc/net/tcp6"}
+
+// countConnections counts the established TCP connections to the local port. iperf3 does not
+// report its clients, so the kernel's socket table is read instead (Linux only). Every test
+// holds a control connection and one connection per TCP stream.
+func countConnections(port int) (int, error) {
+	count := 0
+	found := false
+	for _, path := range procNetTCP {
+		file, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // IPv6 disabled, or not Linux
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read TCP sockets: %w", err)
+		}
+		n, err := countEstablished(file, port)
+		file.Close()
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		count += n
+		found = true
+	}
+	if !found {
+		return 0, errors.New("TCP sockets are not available on this platform")
+	}
+	return count, nil
+}
+
+// countEstablished counts the established connections to the local port in a /proc/net/tcp table
+func countEstablished(r io.Reader, port int) (int, error) {
+	count := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // Skip the header
+	for scanner.Scan() {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpEstablished {
+			continue
+		}
+		i := strings.LastIndexByte(fields[1], ':')
+		if i < 0 {
+			return 0, fmt.Errorf("invalid local address %q", fields[1])
+		}
+		localPort, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil {
+			return 0, fmt.Errorf("invalid local address %q", fields[1])
+		}
+		if int(localPort) == port {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procNetTCPHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+func TestCountEstablished(t *testing.T) {
+	table := procNetTCPHeader +
+		// Listening socket of the server
+		"   0: 00000000:1451 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+		// Control and data connection of a client
+		"   1: 0100007F:1451 3200A8C0:D431 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1\n" +
+		"   2: 0100007F:1451 3200A8C0:D432 01 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 20 4 30 10 -1\n" +
+		// Closing connection and a connection to another port
+		"   3: 0100007F:1451 3200A8C0:D430 06 00000000:00000000 03:00000D2F 00000000     0        0 0 3 0000000000000000\n" +
+		"   4: 0100007F:0016 3200A8C0:E001 01 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 20 4 30 10 -1\n"
+	count, err := countEstablished(strings.NewReader(table), 5201)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = countEstablished(strings.NewReader(procNetTCPHeader+"   0: 0100007F 3200A8C0:D431 01\n"), 5201)
+	assert.EqualError(t, err, `invalid local address "0100007F"`)
+}
+
+func TestCountConnections(t *testing.T) {
+	dir := t.TempDir()
+	tcp := filepath.Join(dir, "tcp")
+	tcp6 := filepath.Join(dir, "tcp6")
+	require.NoError(t, os.WriteFile(tcp, []byte(procNetTCPHeader+
+		"   0: 0100007F:1451 3200A8C0:D431 01 00000000:00000000 00:00000000 00000000     0        0 1002 1\n"), 0o600))
+	require.NoError(t, os.WriteFile(tcp6, []byte(procNetTCPHeader+
+		"   0: 0000000000000000FFFF00000100007F:1451 0000000000000000FFFF00003300A8C0:D431 01 00000000:00000000 00:00000000 00000000     0        0 1005 1\n"), 0o600))
+
+	original := procNetTCP
+	t.Cleanup(func() { procNetTCP = original })
+
+	procNetTCP = []string{tcp, tcp6}
+	count, err := countConnections(5201)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Without IPv6 only the IPv4 table exists
+	procNetTCP = []string{tcp, filepath.Join(dir, "missing")}
+	count, err = countConnections(5201)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	procNetTCP = []string{filepath.Join(dir, "missing")}
+	_, err = countConnections(5201)
+	assert.EqualError(t, err, "TCP sockets are not available on this platform")
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	iperf "github.com/BGrewell/go-iperf"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+// lastReport summarizes the last test run to a target for the debug endpoint
+type lastReport struct {
+	Host              string    `json:"host"`
+	Port              int       `json:"port"`
+	Protocol          string    `json:"protocol"`
+	Timestamp         time.Time `json:"timestamp"`
+	SentBandwidth     float64   `json:"sent_bits_per_second"`
+	ReceivedBandwidth float64   `json:"received_bits_per_second"`
+	LostPercent       float64   `json:"lost_percent"`
+	Retransmits       int       `json:"retransmits"`
+	Error             string    `json:"error,omitempty"`
+}
+
+// reportKey identifies a target on the debug endpoint, tests to a port range share one entry
+func reportKey(target TargetConfig) string {
+	address := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+	if target.PortRange != "" {
+		address = net.JoinHostPort(target.Host, target.PortRange)
+	}
+	return target.Protocol + "://" + address
+}
+
+// storeReport keeps the outcome of a test as the last one of its target, must be called with s.mu held
+func (s *scraper) storeReport(target TargetConfig, timestamp pcommon.Timestamp, report *iperf.Report, err error) {
+	if s.reports == nil {
+		s.reports = make(map[string]lastReport)
+	}
+	last := lastReport{
+		Host:      target.Host,
+		Port:      target.Port,
+		Protocol:  target.Protocol,
+		Timestamp: timestamp.AsTime(),
+	}
+	if err != nil {
+		last.Error = err.Error()
+	} else if report != nil && report.End != nil {
+		if sum := report.End.SumSent; sum != nil {
+			last.SentBandwidth = sum.BitsPerSecond
+			last.Retransmits = sum.Retransmits
+		}
+		if sum := report.End.SumReceived; sum != nil {
+			last.ReceivedBandwidth = sum.BitsPerSecond
+			last.LostPercent = sum.LostPercent
+		}
+	}
+	s.reports[reportKey(target)] = last
+}
+
+// lastReports returns a copy of the last report of every tested target
+func (s *scraper) lastReports() map[string]lastReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reports := make(map[string]lastReport, len(s.reports))
+	for key, report := range s.reports {
+		reports[key] = report
+	}
+	return reports
+}
+
+// startDebugServer serves the last reports on the configured debug endpoint
+func (s *scraper) startDebugServer() error {
+	listener, err := net.Listen("tcp", s.cfg.DebugEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.DebugEndpoint, err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reports", s.handleReports)
+	s.debugListener = listener
+	s.debugServer = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func(server *http.Server, listener net.Listener) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Debug server failed", zap.Error(err))
+		}
+	}(s.debugServer, s.debugListener)
+	return nil
+}
+
+func (s *scraper) handleReports(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.lastReports())
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	iperf "github.com/BGrewell/go-iperf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+func TestReportKey(t *testing.T) {
+	assert.Equal(t, "tcp://localhost:5201", reportKey(TargetConfig{Host: "localhost", Port: 5201, Protocol: "tcp"}))
+	assert.Equal(t, "udp://[::1]:5201", reportKey(TargetConfig{Host: "::1", Port: 5201, Protocol: "udp"}))
+	assert.Equal(t, "tcp://localhost:5201-5210", reportKey(TargetConfig{Host: "localhost", Port: 5203, PortRange: "5201-5210", Protocol: "tcp"}))
+}
+
+func TestStoreReport(t *testing.T) {
+	s := newScraper(&Config{}, receivertest.NewNopSettings())
+	target := TargetConfig{Host: "localhost", Port: 5201, Protocol: "udp"}
+	timestamp := pcommon.NewTimestampFromTime(time.Unix(1700000000, 0))
+
+	s.storeReport(target, timestamp, &iperf.Report{
+		End: &iperf.End{
+			SumSent:     &iperf.Sum{BitsPerSecond: 100e6},
+			SumReceived: &iperf.Sum{BitsPerSecond: 95e6, LostPercent: 5},
+		},
+	}, nil)
+	assert.Equal(t, map[string]lastReport{
+		"udp://localhost:5201": {
+			Host:              "localhost",
+			Port:              5201,
+			Protocol:          "udp",
+			Timestamp:         timestamp.AsTime(),
+			SentBandwidth:     100e6,
+			ReceivedBandwidth: 95e6,
+			LostPercent:       5,
+		},
+	}, s.lastReports())
+
+	// A failed test replaces the numbers of the last successful one
+	s.storeReport(target, timestamp, nil, errors.New("connection refused"))
+	assert.Equal(t, lastReport{
+		Host:      "localhost",
+		Port:      5201,
+		Protocol:  "udp",
+		Timestamp: timestamp.AsTime(),
+		Error:     "connection refused",
+	}, s.lastReports()["udp://localhost:5201"])
+}
+
+func TestDebugEndpoint(t *testing.T) {
+	cfg := &Config{Mode: "client", DebugEndpoint: "localhost:0", MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig()}
+	s := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, s.start(context.Background(), componenttest.NewNopHost()))
+
+	target := TargetConfig{Host: "localhost", Port: 5201, Protocol: "tcp"}
+	s.recordTestError(target, pcommon.NewTimestampFromTime(time.Now()), "Failed to run iperf test", "connection_refused", errors.New("connection refused"))
+
+	resp, err := http.Get("http://" + s.debugListener.Addr().String() + "/reports")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var reports map[string]lastReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reports))
+	require.Contains(t, reports, "tcp://localhost:5201")
+	assert.Equal(t, "connection refused", reports["tcp://localhost:5201"].Error)
+
+	require.NoError(t, s.shutdown(context.Background()))
+	_, err = http.Get("http://" + s.debugListener.Addr().String() + "/reports")
+	assert.Error(t, err)
+}
+
+func TestReportsConcurrentAccess(t *testing.T) {
+	s := newScraper(&Config{}, receivertest.NewNopSettings())
+	s.mb = metadata.NewMetricsBuilder(metadata.DefaultMetricsBuilderConfig(), receivertest.NewNopSettings())
+	targets := []TargetConfig{
+		{Host: "192.0.2.1", Port: 5201, Protocol: "tcp"},
+		{Host: "192.0.2.2", Port: 5201, Protocol: "udp"},
+	}
+
+	// Concurrent tests store their reports while the endpoint is read, run with -race to detect unsynchronized access
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				timestamp := pcommon.NewTimestampFromTime(time.Now())
+				if i%2 == 0 {
+					s.recordTestError(target, timestamp, "Failed to run iperf test", "test_failed", errors.New("test failed"))
+					continue
+				}
+				s.mu.Lock()
+				s.storeReport(target, timestamp, &iperf.Report{
+					End: &iperf.End{SumSent: &iperf.Sum{BitsPerSecond: float64(i)}},
+				}, nil)
+				s.mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rec := httptest.NewRecorder()
+				s.handleReports(rec, httptest.NewRequest(http.MethodGet, "/reports", nil))
+				var reports map[string]lastReport
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&reports))
+			}
+		}()
+	}
+	wg.Wait()
+
+	reports := s.lastReports()
+	require.Len(t, reports, 2)
+	for _, target := range targets {
+		assert.Equal(t, 99.0, reports[reportKey(target)].SentBandwidth)
+		assert.Empty(t, reports[reportKey(target)].Error)
+	}
+}
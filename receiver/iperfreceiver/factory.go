@@ -10,10 +10,12 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/scraper"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
 )
 
@@ -25,9 +27,15 @@ func NewFactory() receiver.Factory {
 		metadata.Type,
 		createDefaultConfig,
 		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
 	)
 }
 
+// This is the map of already created iperf receivers for particular configurations.
+// The metrics and logs pipelines must share one receiver per configuration, otherwise
+// every iperf test would run twice and the two runs would compete for bandwidth.
+var receivers = sharedcomponent.NewSharedComponents()
+
 // createDefaultConfig creates the default configuration for the receiver
 func createDefaultConfig() component.Config {
 	cfg := scraperhelper.NewDefaultControllerConfig()
@@ -38,6 +46,9 @@ func createDefaultConfig() component.Config {
 		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
 		Mode:                 "client",
 		ServerPort:           5201, // Default iperf3 port
+		MaxConcurrentTests:   1,    // Concurrent throughput tests interfere with each other
+		AttributeConvention:  "iperf",
+		DebugOutputMaxBytes:  64 * 1024,
 		Targets:              []TargetConfig{},
 	}
 }
@@ -54,20 +65,83 @@ func createMetricsReceiver(
 		return nil, errConfigNotIperf
 	}
 
-	iperfScraper := newScraper(cfg, params)
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		return newIperfReceiver(cfg, params)
+	})
+	r.Unwrap().(*iperfReceiver).metricsConsumer = consumer
+	return r, nil
+}
+
+// createLogsReceiver creates a logs receiver that emits one log record per test run
+func createLogsReceiver(
+	_ context.Context,
+	params receiver.Settings,
+	rConf component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	cfg, ok := rConf.(*Config)
+	if !ok {
+		return nil, errConfigNotIperf
+	}
+
+	r := receivers.GetOrAdd(cfg, func() component.Component {
+		return newIperfReceiver(cfg, params)
+	})
+	r.Unwrap().(*iperfReceiver).scraper.logsConsumer = consumer
+	return r, nil
+}
+
+// iperfReceiver drives a single scraper for both the metrics and logs pipelines
+type iperfReceiver struct {
+	cfg             *Config
+	params          receiver.Settings
+	scraper         *scraper
+	metricsConsumer consumer.Metrics
+	controller      receiver.Metrics
+}
+
+func newIperfReceiver(cfg *Config, params receiver.Settings) *iperfReceiver {
+	return &iperfReceiver{
+		cfg:     cfg,
+		params:  params,
+		scraper: newScraper(cfg, params),
+	}
+}
+
+func (r *iperfReceiver) Start(ctx context.Context, host component.Host) error {
 	s, err := scraper.NewMetrics(
-		iperfScraper.scrape,
-		scraper.WithStart(iperfScraper.start),
-		scraper.WithShutdown(iperfScraper.shutdown),
+		r.scraper.scrape,
+		scraper.WithStart(r.scraper.start),
+		scraper.WithShutdown(r.scraper.shutdown),
 	)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	// Without a metrics pipeline the scraped metrics are dropped, tests still run for the logs
+	metricsConsumer := r.metricsConsumer
+	if metricsConsumer == nil {
+		metricsConsumer, err = consumer.NewMetrics(func(context.Context, pmetric.Metrics) error { return nil })
+		if err != nil {
+			return err
+		}
 	}
 
-	return scraperhelper.NewMetricsController(
-		&cfg.ControllerConfig,
-		params,
-		consumer,
+	r.controller, err = scraperhelper.NewMetricsController(
+		&r.cfg.ControllerConfig,
+		r.params,
+		metricsConsumer,
 		scraperhelper.AddScraper(metadata.Type, s),
 	)
-}
\ No newline at end of file
+	if err != nil {
+		return err
+	}
+	return r.controller.Start(ctx, host)
+}
+
+func (r *iperfReceiver) Shutdown(ctx context.Context) error {
+	if r.controller == nil {
+		return nil
+	}
+	return r.controller.Shutdown(ctx)
+}
@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/receiver/receivertest"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
 )
 
@@ -34,6 +35,11 @@ func TestCreateDefaultConfig(t *testing.T) {
 	
 	assert.Equal(t, "client", iperfCfg.Mode)
 	assert.Equal(t, 5201, iperfCfg.ServerPort)
+	assert.Equal(t, 1, iperfCfg.MaxConcurrentTests)
+	assert.Equal(t, "iperf", iperfCfg.AttributeConvention)
+	assert.Empty(t, iperfCfg.DebugEndpoint)
+	assert.Empty(t, iperfCfg.DebugOutput)
+	assert.Equal(t, 64*1024, iperfCfg.DebugOutputMaxBytes)
 	assert.Equal(t, 60*time.Second, iperfCfg.ControllerConfig.CollectionInterval)
 	assert.Empty(t, iperfCfg.Targets)
 	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
@@ -102,4 +108,40 @@ func TestCreateMetricsReceiver(t *testing.T) {
 func TestFactoryMetricsReceiverCapabilities(t *testing.T) {
 	factory := NewFactory()
 	assert.Equal(t, metadata.MetricsStability, factory.MetricsStability())
-}
\ No newline at end of file
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	receiver, err := factory.CreateLogs(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, receiver)
+
+	_, err = factory.CreateLogs(context.Background(), receivertest.NewNopSettings(), &struct{}{}, consumertest.NewNop())
+	assert.ErrorIs(t, err, errConfigNotIperf)
+}
+
+func TestMetricsAndLogsReceiversShareScraper(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	params := receivertest.NewNopSettings()
+
+	metricsReceiver, err := factory.CreateMetrics(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	logsReceiver, err := factory.CreateLogs(context.Background(), params, cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// Both pipelines must be driven by the same receiver so tests only run once
+	shared := metricsReceiver.(*sharedcomponent.SharedComponent).Unwrap()
+	assert.Same(t, shared, logsReceiver.(*sharedcomponent.SharedComponent).Unwrap())
+	r := shared.(*iperfReceiver)
+	assert.NotNil(t, r.metricsConsumer)
+	assert.NotNil(t, r.scraper.logsConsumer)
+
+	host := componenttest.NewNopHost()
+	require.NoError(t, metricsReceiver.Start(context.Background(), host))
+	require.NoError(t, logsReceiver.Start(context.Background(), host))
+	require.NoError(t, logsReceiver.Shutdown(context.Background()))
+	require.NoError(t, metricsReceiver.Shutdown(context.Background()))
+}
@@ -4,8 +4,10 @@ go 1.22.0
 
 require (
 	github.com/BGrewell/go-iperf v0.0.0-20240831193934-6a2b45559210
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent v0.116.0
 	github.com/stretchr/testify v1.10.0
 	go.opentelemetry.io/collector/component v0.116.0
+	go.opentelemetry.io/collector/config/configopaque v1.23.0
 	go.opentelemetry.io/collector/confmap/confmaptest v1.23.0
 	go.opentelemetry.io/collector/consumer v1.23.0
 	go.opentelemetry.io/collector/consumer/consumertest v0.116.0
@@ -16,4 +18,6 @@ require (
 	go.uber.org/goleak v1.3.0
 	go.uber.org/multierr v1.11.0
 	go.uber.org/zap v1.27.0
-)
\ No newline at end of file
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent => ../../internal/sharedcomponent
@@ -28,16 +28,32 @@ func (ms *MetricConfig) Unmarshal(parser *confmap.Conf) error {
 
 // MetricsConfig provides config for iperf metrics.
 type MetricsConfig struct {
-	IperfBandwidth      MetricConfig `mapstructure:"iperf.bandwidth"`
-	IperfCPUUtilization MetricConfig `mapstructure:"iperf.cpu.utilization"`
-	IperfCwnd           MetricConfig `mapstructure:"iperf.cwnd"`
-	IperfJitter         MetricConfig `mapstructure:"iperf.jitter"`
-	IperfPacketLoss     MetricConfig `mapstructure:"iperf.packet_loss"`
-	IperfRetransmits    MetricConfig `mapstructure:"iperf.retransmits"`
-	IperfRtt            MetricConfig `mapstructure:"iperf.rtt"`
-	IperfTestDuration   MetricConfig `mapstructure:"iperf.test.duration"`
-	IperfTestError      MetricConfig `mapstructure:"iperf.test.error"`
-	IperfTransfer       MetricConfig `mapstructure:"iperf.transfer"`
+	IperfBandwidth               MetricConfig `mapstructure:"iperf.bandwidth"`
+	IperfBandwidthStddev         MetricConfig `mapstructure:"iperf.bandwidth.stddev"`
+	IperfBuildInfo               MetricConfig `mapstructure:"iperf.build.info"`
+	IperfCPUModeUtilization      MetricConfig `mapstructure:"iperf.cpu.mode_utilization"`
+	IperfCPUUtilization          MetricConfig `mapstructure:"iperf.cpu.utilization"`
+	IperfIntervalBandwidth       MetricConfig `mapstructure:"iperf.interval.bandwidth"`
+	IperfIntervalJitter          MetricConfig `mapstructure:"iperf.interval.jitter"`
+	IperfIntervalPacketLoss      MetricConfig `mapstructure:"iperf.interval.packet_loss"`
+	IperfIntervalRetransmits     MetricConfig `mapstructure:"iperf.interval.retransmits"`
+	IperfIntervalTCPSndCwnd      MetricConfig `mapstructure:"iperf.interval.tcp.snd_cwnd"`
+	IperfJitter                  MetricConfig `mapstructure:"iperf.jitter"`
+	IperfPacketLoss              MetricConfig `mapstructure:"iperf.packet_loss"`
+	IperfRetransmits             MetricConfig `mapstructure:"iperf.retransmits"`
+	IperfRtt                     MetricConfig `mapstructure:"iperf.rtt"`
+	IperfRttVariance             MetricConfig `mapstructure:"iperf.rtt.variance"`
+	IperfServerActiveConnections MetricConfig `mapstructure:"iperf.server.active_connections"`
+	IperfServerRestart           MetricConfig `mapstructure:"iperf.server.restart"`
+	IperfTCPSndCwnd              MetricConfig `mapstructure:"iperf.tcp.snd_cwnd"`
+	IperfTestAttempts            MetricConfig `mapstructure:"iperf.test.attempts"`
+	IperfTestDuration            MetricConfig `mapstructure:"iperf.test.duration"`
+	IperfTestError               MetricConfig `mapstructure:"iperf.test.error"`
+	IperfTestRequestedDuration   MetricConfig `mapstructure:"iperf.test.requested_duration"`
+	IperfTestSkipped             MetricConfig `mapstructure:"iperf.test.skipped"`
+	IperfTestTruncated           MetricConfig `mapstructure:"iperf.test.truncated"`
+	IperfTransfer                MetricConfig `mapstructure:"iperf.transfer"`
+	IperfWindow                  MetricConfig `mapstructure:"iperf.window"`
 }
 
 func DefaultMetricsConfig() MetricsConfig {
@@ -45,12 +61,33 @@ func DefaultMetricsConfig() MetricsConfig {
 		IperfBandwidth: MetricConfig{
 			Enabled: true,
 		},
-		IperfCPUUtilization: MetricConfig{
+		IperfBandwidthStddev: MetricConfig{
+			Enabled: true,
+		},
+		IperfBuildInfo: MetricConfig{
+			Enabled: true,
+		},
+		IperfCPUModeUtilization: MetricConfig{
 			Enabled: false,
 		},
-		IperfCwnd: MetricConfig{
+		IperfCPUUtilization: MetricConfig{
 			Enabled: false,
 		},
+		IperfIntervalBandwidth: MetricConfig{
+			Enabled: true,
+		},
+		IperfIntervalJitter: MetricConfig{
+			Enabled: true,
+		},
+		IperfIntervalPacketLoss: MetricConfig{
+			Enabled: true,
+		},
+		IperfIntervalRetransmits: MetricConfig{
+			Enabled: true,
+		},
+		IperfIntervalTCPSndCwnd: MetricConfig{
+			Enabled: true,
+		},
 		IperfJitter: MetricConfig{
 			Enabled: true,
 		},
@@ -61,7 +98,22 @@ func DefaultMetricsConfig() MetricsConfig {
 			Enabled: true,
 		},
 		IperfRtt: MetricConfig{
-			Enabled: false,
+			Enabled: true,
+		},
+		IperfRttVariance: MetricConfig{
+			Enabled: true,
+		},
+		IperfServerActiveConnections: MetricConfig{
+			Enabled: true,
+		},
+		IperfServerRestart: MetricConfig{
+			Enabled: true,
+		},
+		IperfTCPSndCwnd: MetricConfig{
+			Enabled: true,
+		},
+		IperfTestAttempts: MetricConfig{
+			Enabled: true,
 		},
 		IperfTestDuration: MetricConfig{
 			Enabled: true,
@@ -69,9 +121,21 @@ func DefaultMetricsConfig() MetricsConfig {
 		IperfTestError: MetricConfig{
 			Enabled: true,
 		},
+		IperfTestRequestedDuration: MetricConfig{
+			Enabled: true,
+		},
+		IperfTestSkipped: MetricConfig{
+			Enabled: true,
+		},
+		IperfTestTruncated: MetricConfig{
+			Enabled: true,
+		},
 		IperfTransfer: MetricConfig{
 			Enabled: true,
 		},
+		IperfWindow: MetricConfig{
+			Enabled: true,
+		},
 	}
 }
 
@@ -103,18 +167,62 @@ func (rac *ResourceAttributeConfig) Unmarshal(parser *confmap.Conf) error {
 
 // ResourceAttributesConfig provides config for iperf resource attributes.
 type ResourceAttributesConfig struct {
-	IperfTargetHost ResourceAttributeConfig `mapstructure:"iperf.target.host"`
-	IperfTargetPort ResourceAttributeConfig `mapstructure:"iperf.target.port"`
+	IperfTargetAddressFamily ResourceAttributeConfig `mapstructure:"iperf.target.address_family"`
+	IperfTargetHost          ResourceAttributeConfig `mapstructure:"iperf.target.host"`
+	IperfTargetPort          ResourceAttributeConfig `mapstructure:"iperf.target.port"`
+	IperfTestAggregation     ResourceAttributeConfig `mapstructure:"iperf.test.aggregation"`
+	IperfTestBitrate         ResourceAttributeConfig `mapstructure:"iperf.test.bitrate"`
+	IperfTestFqRate          ResourceAttributeConfig `mapstructure:"iperf.test.fq_rate"`
+	IperfTestPps             ResourceAttributeConfig `mapstructure:"iperf.test.pps"`
+	IperfTestSamples         ResourceAttributeConfig `mapstructure:"iperf.test.samples"`
+	IperfTestTermination     ResourceAttributeConfig `mapstructure:"iperf.test.termination"`
+	IperfTestTos             ResourceAttributeConfig `mapstructure:"iperf.test.tos"`
+	IperfVersion             ResourceAttributeConfig `mapstructure:"iperf.version"`
+	ServerAddress            ResourceAttributeConfig `mapstructure:"server.address"`
+	ServerPort               ResourceAttributeConfig `mapstructure:"server.port"`
 }
 
 func DefaultResourceAttributesConfig() ResourceAttributesConfig {
 	return ResourceAttributesConfig{
+		IperfTargetAddressFamily: ResourceAttributeConfig{
+			Enabled: true,
+		},
 		IperfTargetHost: ResourceAttributeConfig{
 			Enabled: true,
 		},
 		IperfTargetPort: ResourceAttributeConfig{
 			Enabled: true,
 		},
+		IperfTestAggregation: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfTestBitrate: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfTestFqRate: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfTestPps: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfTestSamples: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfTestTermination: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfTestTos: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		IperfVersion: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		ServerAddress: ResourceAttributeConfig{
+			Enabled: true,
+		},
+		ServerPort: ResourceAttributeConfig{
+			Enabled: true,
+		},
 	}
 }
 
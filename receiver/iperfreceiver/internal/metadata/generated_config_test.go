@@ -27,20 +27,47 @@ func TestMetricsBuilderConfig(t *testing.T) {
 			name: "all_set",
 			want: MetricsBuilderConfig{
 				Metrics: MetricsConfig{
-					IperfBandwidth:      MetricConfig{Enabled: true},
-					IperfCPUUtilization: MetricConfig{Enabled: true},
-					IperfCwnd:           MetricConfig{Enabled: true},
-					IperfJitter:         MetricConfig{Enabled: true},
-					IperfPacketLoss:     MetricConfig{Enabled: true},
-					IperfRetransmits:    MetricConfig{Enabled: true},
-					IperfRtt:            MetricConfig{Enabled: true},
-					IperfTestDuration:   MetricConfig{Enabled: true},
-					IperfTestError:      MetricConfig{Enabled: true},
-					IperfTransfer:       MetricConfig{Enabled: true},
+					IperfBandwidth:               MetricConfig{Enabled: true},
+					IperfBandwidthStddev:         MetricConfig{Enabled: true},
+					IperfBuildInfo:               MetricConfig{Enabled: true},
+					IperfCPUModeUtilization:      MetricConfig{Enabled: true},
+					IperfCPUUtilization:          MetricConfig{Enabled: true},
+					IperfIntervalBandwidth:       MetricConfig{Enabled: true},
+					IperfIntervalJitter:          MetricConfig{Enabled: true},
+					IperfIntervalPacketLoss:      MetricConfig{Enabled: true},
+					IperfIntervalRetransmits:     MetricConfig{Enabled: true},
+					IperfIntervalTCPSndCwnd:      MetricConfig{Enabled: true},
+					IperfJitter:                  MetricConfig{Enabled: true},
+					IperfPacketLoss:              MetricConfig{Enabled: true},
+					IperfRetransmits:             MetricConfig{Enabled: true},
+					IperfRtt:                     MetricConfig{Enabled: true},
+					IperfRttVariance:             MetricConfig{Enabled: true},
+					IperfServerActiveConnections: MetricConfig{Enabled: true},
+					IperfServerRestart:           MetricConfig{Enabled: true},
+					IperfTCPSndCwnd:              MetricConfig{Enabled: true},
+					IperfTestAttempts:            MetricConfig{Enabled: true},
+					IperfTestDuration:            MetricConfig{Enabled: true},
+					IperfTestError:               MetricConfig{Enabled: true},
+					IperfTestRequestedDuration:   MetricConfig{Enabled: true},
+					IperfTestSkipped:             MetricConfig{Enabled: true},
+					IperfTestTruncated:           MetricConfig{Enabled: true},
+					IperfTransfer:                MetricConfig{Enabled: true},
+					IperfWindow:                  MetricConfig{Enabled: true},
 				},
 				ResourceAttributes: ResourceAttributesConfig{
-					IperfTargetHost: ResourceAttributeConfig{Enabled: true},
-					IperfTargetPort: ResourceAttributeConfig{Enabled: true},
+					IperfTargetAddressFamily: ResourceAttributeConfig{Enabled: true},
+					IperfTargetHost:          ResourceAttributeConfig{Enabled: true},
+					IperfTargetPort:          ResourceAttributeConfig{Enabled: true},
+					IperfTestAggregation:     ResourceAttributeConfig{Enabled: true},
+					IperfTestBitrate:         ResourceAttributeConfig{Enabled: true},
+					IperfTestFqRate:          ResourceAttributeConfig{Enabled: true},
+					IperfTestPps:             ResourceAttributeConfig{Enabled: true},
+					IperfTestSamples:         ResourceAttributeConfig{Enabled: true},
+					IperfTestTermination:     ResourceAttributeConfig{Enabled: true},
+					IperfTestTos:             ResourceAttributeConfig{Enabled: true},
+					IperfVersion:             ResourceAttributeConfig{Enabled: true},
+					ServerAddress:            ResourceAttributeConfig{Enabled: true},
+					ServerPort:               ResourceAttributeConfig{Enabled: true},
 				},
 			},
 		},
@@ -48,20 +75,47 @@ func TestMetricsBuilderConfig(t *testing.T) {
 			name: "none_set",
 			want: MetricsBuilderConfig{
 				Metrics: MetricsConfig{
-					IperfBandwidth:      MetricConfig{Enabled: false},
-					IperfCPUUtilization: MetricConfig{Enabled: false},
-					IperfCwnd:           MetricConfig{Enabled: false},
-					IperfJitter:         MetricConfig{Enabled: false},
-					IperfPacketLoss:     MetricConfig{Enabled: false},
-					IperfRetransmits:    MetricConfig{Enabled: false},
-					IperfRtt:            MetricConfig{Enabled: false},
-					IperfTestDuration:   MetricConfig{Enabled: false},
-					IperfTestError:      MetricConfig{Enabled: false},
-					IperfTransfer:       MetricConfig{Enabled: false},
+					IperfBandwidth:               MetricConfig{Enabled: false},
+					IperfBandwidthStddev:         MetricConfig{Enabled: false},
+					IperfBuildInfo:               MetricConfig{Enabled: false},
+					IperfCPUModeUtilization:      MetricConfig{Enabled: false},
+					IperfCPUUtilization:          MetricConfig{Enabled: false},
+					IperfIntervalBandwidth:       MetricConfig{Enabled: false},
+					IperfIntervalJitter:          MetricConfig{Enabled: false},
+					IperfIntervalPacketLoss:      MetricConfig{Enabled: false},
+					IperfIntervalRetransmits:     MetricConfig{Enabled: false},
+					IperfIntervalTCPSndCwnd:      MetricConfig{Enabled: false},
+					IperfJitter:                  MetricConfig{Enabled: false},
+					IperfPacketLoss:              MetricConfig{Enabled: false},
+					IperfRetransmits:             MetricConfig{Enabled: false},
+					IperfRtt:                     MetricConfig{Enabled: false},
+					IperfRttVariance:             MetricConfig{Enabled: false},
+					IperfServerActiveConnections: MetricConfig{Enabled: false},
+					IperfServerRestart:           MetricConfig{Enabled: false},
+					IperfTCPSndCwnd:              MetricConfig{Enabled: false},
+					IperfTestAttempts:            MetricConfig{Enabled: false},
+					IperfTestDuration:            MetricConfig{Enabled: false},
+					IperfTestError:               MetricConfig{Enabled: false},
+					IperfTestRequestedDuration:   MetricConfig{Enabled: false},
+					IperfTestSkipped:             MetricConfig{Enabled: false},
+					IperfTestTruncated:           MetricConfig{Enabled: false},
+					IperfTransfer:                MetricConfig{Enabled: false},
+					IperfWindow:                  MetricConfig{Enabled: false},
 				},
 				ResourceAttributes: ResourceAttributesConfig{
-					IperfTargetHost: ResourceAttributeConfig{Enabled: false},
-					IperfTargetPort: ResourceAttributeConfig{Enabled: false},
+					IperfTargetAddressFamily: ResourceAttributeConfig{Enabled: false},
+					IperfTargetHost:          ResourceAttributeConfig{Enabled: false},
+					IperfTargetPort:          ResourceAttributeConfig{Enabled: false},
+					IperfTestAggregation:     ResourceAttributeConfig{Enabled: false},
+					IperfTestBitrate:         ResourceAttributeConfig{Enabled: false},
+					IperfTestFqRate:          ResourceAttributeConfig{Enabled: false},
+					IperfTestPps:             ResourceAttributeConfig{Enabled: false},
+					IperfTestSamples:         ResourceAttributeConfig{Enabled: false},
+					IperfTestTermination:     ResourceAttributeConfig{Enabled: false},
+					IperfTestTos:             ResourceAttributeConfig{Enabled: false},
+					IperfVersion:             ResourceAttributeConfig{Enabled: false},
+					ServerAddress:            ResourceAttributeConfig{Enabled: false},
+					ServerPort:               ResourceAttributeConfig{Enabled: false},
 				},
 			},
 		},
@@ -97,15 +151,37 @@ func TestResourceAttributesConfig(t *testing.T) {
 		{
 			name: "all_set",
 			want: ResourceAttributesConfig{
-				IperfTargetHost: ResourceAttributeConfig{Enabled: true},
-				IperfTargetPort: ResourceAttributeConfig{Enabled: true},
+				IperfTargetAddressFamily: ResourceAttributeConfig{Enabled: true},
+				IperfTargetHost:          ResourceAttributeConfig{Enabled: true},
+				IperfTargetPort:          ResourceAttributeConfig{Enabled: true},
+				IperfTestAggregation:     ResourceAttributeConfig{Enabled: true},
+				IperfTestBitrate:         ResourceAttributeConfig{Enabled: true},
+				IperfTestFqRate:          ResourceAttributeConfig{Enabled: true},
+				IperfTestPps:             ResourceAttributeConfig{Enabled: true},
+				IperfTestSamples:         ResourceAttributeConfig{Enabled: true},
+				IperfTestTermination:     ResourceAttributeConfig{Enabled: true},
+				IperfTestTos:             ResourceAttributeConfig{Enabled: true},
+				IperfVersion:             ResourceAttributeConfig{Enabled: true},
+				ServerAddress:            ResourceAttributeConfig{Enabled: true},
+				ServerPort:               ResourceAttributeConfig{Enabled: true},
 			},
 		},
 		{
 			name: "none_set",
 			want: ResourceAttributesConfig{
-				IperfTargetHost: ResourceAttributeConfig{Enabled: false},
-				IperfTargetPort: ResourceAttributeConfig{Enabled: false},
+				IperfTargetAddressFamily: ResourceAttributeConfig{Enabled: false},
+				IperfTargetHost:          ResourceAttributeConfig{Enabled: false},
+				IperfTargetPort:          ResourceAttributeConfig{Enabled: false},
+				IperfTestAggregation:     ResourceAttributeConfig{Enabled: false},
+				IperfTestBitrate:         ResourceAttributeConfig{Enabled: false},
+				IperfTestFqRate:          ResourceAttributeConfig{Enabled: false},
+				IperfTestPps:             ResourceAttributeConfig{Enabled: false},
+				IperfTestSamples:         ResourceAttributeConfig{Enabled: false},
+				IperfTestTermination:     ResourceAttributeConfig{Enabled: false},
+				IperfTestTos:             ResourceAttributeConfig{Enabled: false},
+				IperfVersion:             ResourceAttributeConfig{Enabled: false},
+				ServerAddress:            ResourceAttributeConfig{Enabled: false},
+				ServerPort:               ResourceAttributeConfig{Enabled: false},
 			},
 		},
 	}
@@ -16,11 +16,32 @@ var MetricsInfo = metricsInfo{
 	IperfBandwidth: metricInfo{
 		Name: "iperf.bandwidth",
 	},
+	IperfBandwidthStddev: metricInfo{
+		Name: "iperf.bandwidth.stddev",
+	},
+	IperfBuildInfo: metricInfo{
+		Name: "iperf.build.info",
+	},
+	IperfCPUModeUtilization: metricInfo{
+		Name: "iperf.cpu.mode_utilization",
+	},
 	IperfCPUUtilization: metricInfo{
 		Name: "iperf.cpu.utilization",
 	},
-	IperfCwnd: metricInfo{
-		Name: "iperf.cwnd",
+	IperfIntervalBandwidth: metricInfo{
+		Name: "iperf.interval.bandwidth",
+	},
+	IperfIntervalJitter: metricInfo{
+		Name: "iperf.interval.jitter",
+	},
+	IperfIntervalPacketLoss: metricInfo{
+		Name: "iperf.interval.packet_loss",
+	},
+	IperfIntervalRetransmits: metricInfo{
+		Name: "iperf.interval.retransmits",
+	},
+	IperfIntervalTCPSndCwnd: metricInfo{
+		Name: "iperf.interval.tcp.snd_cwnd",
 	},
 	IperfJitter: metricInfo{
 		Name: "iperf.jitter",
@@ -34,71 +55,896 @@ var MetricsInfo = metricsInfo{
 	IperfRtt: metricInfo{
 		Name: "iperf.rtt",
 	},
+	IperfRttVariance: metricInfo{
+		Name: "iperf.rtt.variance",
+	},
+	IperfServerActiveConnections: metricInfo{
+		Name: "iperf.server.active_connections",
+	},
+	IperfServerRestart: metricInfo{
+		Name: "iperf.server.restart",
+	},
+	IperfTCPSndCwnd: metricInfo{
+		Name: "iperf.tcp.snd_cwnd",
+	},
+	IperfTestAttempts: metricInfo{
+		Name: "iperf.test.attempts",
+	},
 	IperfTestDuration: metricInfo{
 		Name: "iperf.test.duration",
 	},
 	IperfTestError: metricInfo{
 		Name: "iperf.test.error",
 	},
+	IperfTestRequestedDuration: metricInfo{
+		Name: "iperf.test.requested_duration",
+	},
+	IperfTestSkipped: metricInfo{
+		Name: "iperf.test.skipped",
+	},
+	IperfTestTruncated: metricInfo{
+		Name: "iperf.test.truncated",
+	},
 	IperfTransfer: metricInfo{
 		Name: "iperf.transfer",
 	},
+	IperfWindow: metricInfo{
+		Name: "iperf.window",
+	},
 }
 
 type metricsInfo struct {
-	IperfBandwidth      metricInfo
-	IperfCPUUtilization metricInfo
-	IperfCwnd           metricInfo
-	IperfJitter         metricInfo
-	IperfPacketLoss     metricInfo
-	IperfRetransmits    metricInfo
-	IperfRtt            metricInfo
-	IperfTestDuration   metricInfo
-	IperfTestError      metricInfo
-	IperfTransfer       metricInfo
+	IperfBandwidth               metricInfo
+	IperfBandwidthStddev         metricInfo
+	IperfBuildInfo               metricInfo
+	IperfCPUModeUtilization      metricInfo
+	IperfCPUUtilization          metricInfo
+	IperfIntervalBandwidth       metricInfo
+	IperfIntervalJitter          metricInfo
+	IperfIntervalPacketLoss      metricInfo
+	IperfIntervalRetransmits     metricInfo
+	IperfIntervalTCPSndCwnd      metricInfo
+	IperfJitter                  metricInfo
+	IperfPacketLoss              metricInfo
+	IperfRetransmits             metricInfo
+	IperfRtt                     metricInfo
+	IperfRttVariance             metricInfo
+	IperfServerActiveConnections metricInfo
+	IperfServerRestart           metricInfo
+	IperfTCPSndCwnd              metricInfo
+	IperfTestAttempts            metricInfo
+	IperfTestDuration            metricInfo
+	IperfTestError               metricInfo
+	IperfTestRequestedDuration   metricInfo
+	IperfTestSkipped             metricInfo
+	IperfTestTruncated           metricInfo
+	IperfTransfer                metricInfo
+	IperfWindow                  metricInfo
 }
 
 type metricInfo struct {
 	Name string
 }
 
-type metricIperfBandwidth struct {
+type metricIperfBandwidth struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.bandwidth metric with initial data.
+func (m *metricIperfBandwidth) init() {
+	m.data.SetName("iperf.bandwidth")
+	m.data.SetDescription("Network bandwidth measured during the test")
+	m.data.SetUnit("bit/s")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfBandwidth) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfTestStreamsAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutInt("iperf.test.streams", iperfTestStreamsAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfBandwidth) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfBandwidth) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfBandwidth(cfg MetricConfig) metricIperfBandwidth {
+	m := metricIperfBandwidth{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfBandwidthStddev struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.bandwidth.stddev metric with initial data.
+func (m *metricIperfBandwidthStddev) init() {
+	m.data.SetName("iperf.bandwidth.stddev")
+	m.data.SetDescription("Standard deviation of the bandwidth across the samples of a test, recorded when several samples are run")
+	m.data.SetUnit("bit/s")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfBandwidthStddev) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfBandwidthStddev) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfBandwidthStddev) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfBandwidthStddev(cfg MetricConfig) metricIperfBandwidthStddev {
+	m := metricIperfBandwidthStddev{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfBuildInfo struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.build.info metric with initial data.
+func (m *metricIperfBuildInfo) init() {
+	m.data.SetName("iperf.build.info")
+	m.data.SetDescription("Always 1, identifies the iperf3 version through the iperf.version resource attribute")
+	m.data.SetUnit("1")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfBuildInfo) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfBuildInfo) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfBuildInfo) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfBuildInfo(cfg MetricConfig) metricIperfBuildInfo {
+	m := metricIperfBuildInfo{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfCPUModeUtilization struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.cpu.mode_utilization metric with initial data.
+func (m *metricIperfCPUModeUtilization) init() {
+	m.data.SetName("iperf.cpu.mode_utilization")
+	m.data.SetDescription("CPU utilization during the test split into user and system time")
+	m.data.SetUnit("%")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfCPUModeUtilization) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfCPUModeAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutStr("iperf.cpu.mode", iperfCPUModeAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfCPUModeUtilization) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfCPUModeUtilization) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfCPUModeUtilization(cfg MetricConfig) metricIperfCPUModeUtilization {
+	m := metricIperfCPUModeUtilization{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfCPUUtilization struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.cpu.utilization metric with initial data.
+func (m *metricIperfCPUUtilization) init() {
+	m.data.SetName("iperf.cpu.utilization")
+	m.data.SetDescription("CPU utilization during the test")
+	m.data.SetUnit("%")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfCPUUtilization) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfCPUUtilization) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfCPUUtilization) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfCPUUtilization(cfg MetricConfig) metricIperfCPUUtilization {
+	m := metricIperfCPUUtilization{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfIntervalBandwidth struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.interval.bandwidth metric with initial data.
+func (m *metricIperfIntervalBandwidth) init() {
+	m.data.SetName("iperf.interval.bandwidth")
+	m.data.SetDescription("Network bandwidth measured during a single reporting interval")
+	m.data.SetUnit("bit/s")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfIntervalBandwidth) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutInt("iperf.interval.index", iperfIntervalIndexAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfIntervalBandwidth) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfIntervalBandwidth) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfIntervalBandwidth(cfg MetricConfig) metricIperfIntervalBandwidth {
+	m := metricIperfIntervalBandwidth{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfIntervalJitter struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.interval.jitter metric with initial data.
+func (m *metricIperfIntervalJitter) init() {
+	m.data.SetName("iperf.interval.jitter")
+	m.data.SetDescription("Jitter measured during a single reporting interval (UDP only)")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfIntervalJitter) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutInt("iperf.interval.index", iperfIntervalIndexAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfIntervalJitter) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfIntervalJitter) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfIntervalJitter(cfg MetricConfig) metricIperfIntervalJitter {
+	m := metricIperfIntervalJitter{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfIntervalPacketLoss struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.interval.packet_loss metric with initial data.
+func (m *metricIperfIntervalPacketLoss) init() {
+	m.data.SetName("iperf.interval.packet_loss")
+	m.data.SetDescription("Percentage of packets lost during a single reporting interval (UDP only)")
+	m.data.SetUnit("%")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfIntervalPacketLoss) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutInt("iperf.interval.index", iperfIntervalIndexAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfIntervalPacketLoss) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfIntervalPacketLoss) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfIntervalPacketLoss(cfg MetricConfig) metricIperfIntervalPacketLoss {
+	m := metricIperfIntervalPacketLoss{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfIntervalRetransmits struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.interval.retransmits metric with initial data.
+func (m *metricIperfIntervalRetransmits) init() {
+	m.data.SetName("iperf.interval.retransmits")
+	m.data.SetDescription("Number of retransmissions during a single reporting interval (TCP and SCTP only)")
+	m.data.SetUnit("{retransmission}")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfIntervalRetransmits) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutInt("iperf.interval.index", iperfIntervalIndexAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfIntervalRetransmits) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfIntervalRetransmits) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfIntervalRetransmits(cfg MetricConfig) metricIperfIntervalRetransmits {
+	m := metricIperfIntervalRetransmits{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfIntervalTCPSndCwnd struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.interval.tcp.snd_cwnd metric with initial data.
+func (m *metricIperfIntervalTCPSndCwnd) init() {
+	m.data.SetName("iperf.interval.tcp.snd_cwnd")
+	m.data.SetDescription("Largest TCP sender congestion window of any stream at the end of a single reporting interval (TCP only)")
+	m.data.SetUnit("By")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfIntervalTCPSndCwnd) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTCPCongestionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("iperf.tcp.congestion", iperfTCPCongestionAttributeValue)
+	dp.Attributes().PutInt("iperf.interval.index", iperfIntervalIndexAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfIntervalTCPSndCwnd) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfIntervalTCPSndCwnd) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfIntervalTCPSndCwnd(cfg MetricConfig) metricIperfIntervalTCPSndCwnd {
+	m := metricIperfIntervalTCPSndCwnd{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfJitter struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.jitter metric with initial data.
+func (m *metricIperfJitter) init() {
+	m.data.SetName("iperf.jitter")
+	m.data.SetDescription("Jitter measured during the test (UDP only)")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfJitter) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfTestLengthAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutInt("iperf.test.length", iperfTestLengthAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfJitter) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfJitter) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfJitter(cfg MetricConfig) metricIperfJitter {
+	m := metricIperfJitter{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfPacketLoss struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.packet_loss metric with initial data.
+func (m *metricIperfPacketLoss) init() {
+	m.data.SetName("iperf.packet_loss")
+	m.data.SetDescription("Percentage of packets lost (UDP only)")
+	m.data.SetUnit("%")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfPacketLoss) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfTestLengthAttributeValue int64) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.Attributes().PutInt("iperf.test.length", iperfTestLengthAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfPacketLoss) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfPacketLoss) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfPacketLoss(cfg MetricConfig) metricIperfPacketLoss {
+	m := metricIperfPacketLoss{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfRetransmits struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.retransmits metric with initial data.
+func (m *metricIperfRetransmits) init() {
+	m.data.SetName("iperf.retransmits")
+	m.data.SetDescription("Number of retransmissions (TCP and SCTP only)")
+	m.data.SetUnit("{retransmission}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfRetransmits) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfRetransmits) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfRetransmits) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfRetransmits(cfg MetricConfig) metricIperfRetransmits {
+	m := metricIperfRetransmits{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfRtt struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.rtt metric with initial data.
+func (m *metricIperfRtt) init() {
+	m.data.SetName("iperf.rtt")
+	m.data.SetDescription("Round trip time measured by the sender's TCP stack (TCP only)")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfRtt) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfRttStatisticAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.rtt.statistic", iperfRttStatisticAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfRtt) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfRtt) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfRtt(cfg MetricConfig) metricIperfRtt {
+	m := metricIperfRtt{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfRttVariance struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.rtt.variance metric with initial data.
+func (m *metricIperfRttVariance) init() {
+	m.data.SetName("iperf.rtt.variance")
+	m.data.SetDescription("Smoothed round trip time variation measured by the sender's TCP stack, averaged across streams (TCP only)")
+	m.data.SetUnit("ms")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfRttVariance) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfRttVariance) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfRttVariance) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfRttVariance(cfg MetricConfig) metricIperfRttVariance {
+	m := metricIperfRttVariance{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
+type metricIperfServerActiveConnections struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.bandwidth metric with initial data.
-func (m *metricIperfBandwidth) init() {
-	m.data.SetName("iperf.bandwidth")
-	m.data.SetDescription("Network bandwidth measured during the test")
-	m.data.SetUnit("bit/s")
+// init fills iperf.server.active_connections metric with initial data.
+func (m *metricIperfServerActiveConnections) init() {
+	m.data.SetName("iperf.server.active_connections")
+	m.data.SetDescription("Number of TCP connections clients currently hold to the iperf3 server, a control connection and one per TCP stream of each running test (server mode, Linux only)")
+	m.data.SetUnit("{connection}")
 	m.data.SetEmptyGauge()
 	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfBandwidth) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfTestStreamsAttributeValue int64) {
+func (m *metricIperfServerActiveConnections) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
 	if !m.config.Enabled {
 		return
 	}
 	dp := m.data.Gauge().DataPoints().AppendEmpty()
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
-	dp.SetDoubleValue(val)
-	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
-	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
-	dp.Attributes().PutInt("iperf.test.streams", iperfTestStreamsAttributeValue)
+	dp.SetIntValue(val)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfBandwidth) updateCapacity() {
+func (m *metricIperfServerActiveConnections) updateCapacity() {
 	if m.data.Gauge().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfBandwidth) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfServerActiveConnections) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -106,8 +952,8 @@ func (m *metricIperfBandwidth) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfBandwidth(cfg MetricConfig) metricIperfBandwidth {
-	m := metricIperfBandwidth{config: cfg}
+func newMetricIperfServerActiveConnections(cfg MetricConfig) metricIperfServerActiveConnections {
+	m := metricIperfServerActiveConnections{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -115,51 +961,51 @@ func newMetricIperfBandwidth(cfg MetricConfig) metricIperfBandwidth {
 	return m
 }
 
-type metricIperfCPUUtilization struct {
+type metricIperfServerRestart struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.cpu.utilization metric with initial data.
-func (m *metricIperfCPUUtilization) init() {
-	m.data.SetName("iperf.cpu.utilization")
-	m.data.SetDescription("CPU utilization during the test")
-	m.data.SetUnit("%")
-	m.data.SetEmptyGauge()
-	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+// init fills iperf.server.restart metric with initial data.
+func (m *metricIperfServerRestart) init() {
+	m.data.SetName("iperf.server.restart")
+	m.data.SetDescription("Number of times the iperf3 server was restarted after it exited (server mode)")
+	m.data.SetUnit("{restart}")
+	m.data.SetEmptySum()
+	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfCPUUtilization) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+func (m *metricIperfServerRestart) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64) {
 	if !m.config.Enabled {
 		return
 	}
-	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp := m.data.Sum().DataPoints().AppendEmpty()
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
-	dp.SetDoubleValue(val)
-	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
-	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+	dp.SetIntValue(val)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfCPUUtilization) updateCapacity() {
-	if m.data.Gauge().DataPoints().Len() > m.capacity {
-		m.capacity = m.data.Gauge().DataPoints().Len()
+func (m *metricIperfServerRestart) updateCapacity() {
+	if m.data.Sum().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Sum().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfCPUUtilization) emit(metrics pmetric.MetricSlice) {
-	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+func (m *metricIperfServerRestart) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
 		m.init()
 	}
 }
 
-func newMetricIperfCPUUtilization(cfg MetricConfig) metricIperfCPUUtilization {
-	m := metricIperfCPUUtilization{config: cfg}
+func newMetricIperfServerRestart(cfg MetricConfig) metricIperfServerRestart {
+	m := metricIperfServerRestart{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -167,22 +1013,22 @@ func newMetricIperfCPUUtilization(cfg MetricConfig) metricIperfCPUUtilization {
 	return m
 }
 
-type metricIperfCwnd struct {
+type metricIperfTCPSndCwnd struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.cwnd metric with initial data.
-func (m *metricIperfCwnd) init() {
-	m.data.SetName("iperf.cwnd")
-	m.data.SetDescription("TCP congestion window size")
+// init fills iperf.tcp.snd_cwnd metric with initial data.
+func (m *metricIperfTCPSndCwnd) init() {
+	m.data.SetName("iperf.tcp.snd_cwnd")
+	m.data.SetDescription("Largest TCP sender congestion window reached by any stream during the test (TCP only)")
 	m.data.SetUnit("By")
 	m.data.SetEmptyGauge()
 	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfCwnd) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
+func (m *metricIperfTCPSndCwnd) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTCPCongestionAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
@@ -190,18 +1036,18 @@ func (m *metricIperfCwnd) recordDataPoint(start pcommon.Timestamp, ts pcommon.Ti
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntValue(val)
-	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.tcp.congestion", iperfTCPCongestionAttributeValue)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfCwnd) updateCapacity() {
+func (m *metricIperfTCPSndCwnd) updateCapacity() {
 	if m.data.Gauge().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfCwnd) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfTCPSndCwnd) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -209,8 +1055,8 @@ func (m *metricIperfCwnd) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfCwnd(cfg MetricConfig) metricIperfCwnd {
-	m := metricIperfCwnd{config: cfg}
+func newMetricIperfTCPSndCwnd(cfg MetricConfig) metricIperfTCPSndCwnd {
+	m := metricIperfTCPSndCwnd{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -218,42 +1064,41 @@ func newMetricIperfCwnd(cfg MetricConfig) metricIperfCwnd {
 	return m
 }
 
-type metricIperfJitter struct {
+type metricIperfTestAttempts struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.jitter metric with initial data.
-func (m *metricIperfJitter) init() {
-	m.data.SetName("iperf.jitter")
-	m.data.SetDescription("Jitter measured during the test (UDP only)")
-	m.data.SetUnit("ms")
+// init fills iperf.test.attempts metric with initial data.
+func (m *metricIperfTestAttempts) init() {
+	m.data.SetName("iperf.test.attempts")
+	m.data.SetDescription("Number of attempts the test took, greater than 1 when it was retried, the highest of the samples when several samples are run")
+	m.data.SetUnit("{attempt}")
 	m.data.SetEmptyGauge()
 	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfJitter) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+func (m *metricIperfTestAttempts) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
 	dp := m.data.Gauge().DataPoints().AppendEmpty()
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
-	dp.SetDoubleValue(val)
+	dp.SetIntValue(val)
 	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
-	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfJitter) updateCapacity() {
+func (m *metricIperfTestAttempts) updateCapacity() {
 	if m.data.Gauge().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfJitter) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfTestAttempts) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -261,8 +1106,8 @@ func (m *metricIperfJitter) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfJitter(cfg MetricConfig) metricIperfJitter {
-	m := metricIperfJitter{config: cfg}
+func newMetricIperfTestAttempts(cfg MetricConfig) metricIperfTestAttempts {
+	m := metricIperfTestAttempts{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -270,22 +1115,22 @@ func newMetricIperfJitter(cfg MetricConfig) metricIperfJitter {
 	return m
 }
 
-type metricIperfPacketLoss struct {
+type metricIperfTestDuration struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.packet_loss metric with initial data.
-func (m *metricIperfPacketLoss) init() {
-	m.data.SetName("iperf.packet_loss")
-	m.data.SetDescription("Percentage of packets lost (UDP only)")
-	m.data.SetUnit("%")
+// init fills iperf.test.duration metric with initial data.
+func (m *metricIperfTestDuration) init() {
+	m.data.SetName("iperf.test.duration")
+	m.data.SetDescription("Duration of the iperf test")
+	m.data.SetUnit("s")
 	m.data.SetEmptyGauge()
 	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfPacketLoss) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+func (m *metricIperfTestDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
@@ -294,18 +1139,17 @@ func (m *metricIperfPacketLoss) recordDataPoint(start pcommon.Timestamp, ts pcom
 	dp.SetTimestamp(ts)
 	dp.SetDoubleValue(val)
 	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
-	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfPacketLoss) updateCapacity() {
+func (m *metricIperfTestDuration) updateCapacity() {
 	if m.data.Gauge().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfPacketLoss) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfTestDuration) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -313,8 +1157,8 @@ func (m *metricIperfPacketLoss) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfPacketLoss(cfg MetricConfig) metricIperfPacketLoss {
-	m := metricIperfPacketLoss{config: cfg}
+func newMetricIperfTestDuration(cfg MetricConfig) metricIperfTestDuration {
+	m := metricIperfTestDuration{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -322,24 +1166,24 @@ func newMetricIperfPacketLoss(cfg MetricConfig) metricIperfPacketLoss {
 	return m
 }
 
-type metricIperfRetransmits struct {
+type metricIperfTestError struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.retransmits metric with initial data.
-func (m *metricIperfRetransmits) init() {
-	m.data.SetName("iperf.retransmits")
-	m.data.SetDescription("Number of TCP retransmissions (TCP only)")
-	m.data.SetUnit("{retransmission}")
+// init fills iperf.test.error metric with initial data.
+func (m *metricIperfTestError) init() {
+	m.data.SetName("iperf.test.error")
+	m.data.SetDescription("Records errors occurring during iperf test")
+	m.data.SetUnit("{error}")
 	m.data.SetEmptySum()
-	m.data.Sum().SetIsMonotonic(true)
+	m.data.Sum().SetIsMonotonic(false)
 	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfRetransmits) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
+func (m *metricIperfTestError) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, errorMessageAttributeValue string, errorReasonAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
@@ -347,18 +1191,19 @@ func (m *metricIperfRetransmits) recordDataPoint(start pcommon.Timestamp, ts pco
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntValue(val)
-	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("error.message", errorMessageAttributeValue)
+	dp.Attributes().PutStr("error.reason", errorReasonAttributeValue)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfRetransmits) updateCapacity() {
+func (m *metricIperfTestError) updateCapacity() {
 	if m.data.Sum().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Sum().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfRetransmits) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfTestError) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -366,8 +1211,8 @@ func (m *metricIperfRetransmits) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfRetransmits(cfg MetricConfig) metricIperfRetransmits {
-	m := metricIperfRetransmits{config: cfg}
+func newMetricIperfTestError(cfg MetricConfig) metricIperfTestError {
+	m := metricIperfTestError{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -375,22 +1220,22 @@ func newMetricIperfRetransmits(cfg MetricConfig) metricIperfRetransmits {
 	return m
 }
 
-type metricIperfRtt struct {
+type metricIperfTestRequestedDuration struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.rtt metric with initial data.
-func (m *metricIperfRtt) init() {
-	m.data.SetName("iperf.rtt")
-	m.data.SetDescription("Round trip time")
-	m.data.SetUnit("ms")
+// init fills iperf.test.requested_duration metric with initial data.
+func (m *metricIperfTestRequestedDuration) init() {
+	m.data.SetName("iperf.test.requested_duration")
+	m.data.SetDescription("Duration requested for the iperf test, to compare with iperf.test.duration")
+	m.data.SetUnit("s")
 	m.data.SetEmptyGauge()
 	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfRtt) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
+func (m *metricIperfTestRequestedDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
@@ -402,14 +1247,14 @@ func (m *metricIperfRtt) recordDataPoint(start pcommon.Timestamp, ts pcommon.Tim
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfRtt) updateCapacity() {
+func (m *metricIperfTestRequestedDuration) updateCapacity() {
 	if m.data.Gauge().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfRtt) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfTestRequestedDuration) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -417,8 +1262,8 @@ func (m *metricIperfRtt) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfRtt(cfg MetricConfig) metricIperfRtt {
-	m := metricIperfRtt{config: cfg}
+func newMetricIperfTestRequestedDuration(cfg MetricConfig) metricIperfTestRequestedDuration {
+	m := metricIperfTestRequestedDuration{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -426,41 +1271,41 @@ func newMetricIperfRtt(cfg MetricConfig) metricIperfRtt {
 	return m
 }
 
-type metricIperfTestDuration struct {
+type metricIperfTestSkipped struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.test.duration metric with initial data.
-func (m *metricIperfTestDuration) init() {
-	m.data.SetName("iperf.test.duration")
-	m.data.SetDescription("Duration of the iperf test")
-	m.data.SetUnit("s")
+// init fills iperf.test.skipped metric with initial data.
+func (m *metricIperfTestSkipped) init() {
+	m.data.SetName("iperf.test.skipped")
+	m.data.SetDescription("Set to 1 when a scheduled test was not run")
+	m.data.SetUnit("1")
 	m.data.SetEmptyGauge()
 	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfTestDuration) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
+func (m *metricIperfTestSkipped) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfSkipReasonAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
 	dp := m.data.Gauge().DataPoints().AppendEmpty()
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
-	dp.SetDoubleValue(val)
-	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("iperf.skip.reason", iperfSkipReasonAttributeValue)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfTestDuration) updateCapacity() {
+func (m *metricIperfTestSkipped) updateCapacity() {
 	if m.data.Gauge().DataPoints().Len() > m.capacity {
 		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfTestDuration) emit(metrics pmetric.MetricSlice) {
+func (m *metricIperfTestSkipped) emit(metrics pmetric.MetricSlice) {
 	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
@@ -468,8 +1313,8 @@ func (m *metricIperfTestDuration) emit(metrics pmetric.MetricSlice) {
 	}
 }
 
-func newMetricIperfTestDuration(cfg MetricConfig) metricIperfTestDuration {
-	m := metricIperfTestDuration{config: cfg}
+func newMetricIperfTestSkipped(cfg MetricConfig) metricIperfTestSkipped {
+	m := metricIperfTestSkipped{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -477,52 +1322,50 @@ func newMetricIperfTestDuration(cfg MetricConfig) metricIperfTestDuration {
 	return m
 }
 
-type metricIperfTestError struct {
+type metricIperfTestTruncated struct {
 	data     pmetric.Metric // data buffer for generated metric.
 	config   MetricConfig   // metric config provided by user.
 	capacity int            // max observed number of data points added to the metric.
 }
 
-// init fills iperf.test.error metric with initial data.
-func (m *metricIperfTestError) init() {
-	m.data.SetName("iperf.test.error")
-	m.data.SetDescription("Records errors occurring during iperf test")
-	m.data.SetUnit("{error}")
-	m.data.SetEmptySum()
-	m.data.Sum().SetIsMonotonic(false)
-	m.data.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
-	m.data.Sum().DataPoints().EnsureCapacity(m.capacity)
+// init fills iperf.test.truncated metric with initial data.
+func (m *metricIperfTestTruncated) init() {
+	m.data.SetName("iperf.test.truncated")
+	m.data.SetDescription("Whether the test ended noticeably earlier than requested, e.g. because the server disconnected (1) or not (0)")
+	m.data.SetUnit("1")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
 }
 
-func (m *metricIperfTestError) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, errorMessageAttributeValue string) {
+func (m *metricIperfTestTruncated) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
 	if !m.config.Enabled {
 		return
 	}
-	dp := m.data.Sum().DataPoints().AppendEmpty()
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
 	dp.SetStartTimestamp(start)
 	dp.SetTimestamp(ts)
 	dp.SetIntValue(val)
-	dp.Attributes().PutStr("error.message", errorMessageAttributeValue)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
 }
 
 // updateCapacity saves max length of data point slices that will be used for the slice capacity.
-func (m *metricIperfTestError) updateCapacity() {
-	if m.data.Sum().DataPoints().Len() > m.capacity {
-		m.capacity = m.data.Sum().DataPoints().Len()
+func (m *metricIperfTestTruncated) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
 	}
 }
 
 // emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
-func (m *metricIperfTestError) emit(metrics pmetric.MetricSlice) {
-	if m.config.Enabled && m.data.Sum().DataPoints().Len() > 0 {
+func (m *metricIperfTestTruncated) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
 		m.updateCapacity()
 		m.data.MoveTo(metrics.AppendEmpty())
 		m.init()
 	}
 }
 
-func newMetricIperfTestError(cfg MetricConfig) metricIperfTestError {
-	m := metricIperfTestError{config: cfg}
+func newMetricIperfTestTruncated(cfg MetricConfig) metricIperfTestTruncated {
+	m := metricIperfTestTruncated{config: cfg}
 	if cfg.Enabled {
 		m.data = pmetric.NewMetric()
 		m.init()
@@ -584,26 +1427,94 @@ func newMetricIperfTransfer(cfg MetricConfig) metricIperfTransfer {
 	return m
 }
 
+type metricIperfWindow struct {
+	data     pmetric.Metric // data buffer for generated metric.
+	config   MetricConfig   // metric config provided by user.
+	capacity int            // max observed number of data points added to the metric.
+}
+
+// init fills iperf.window metric with initial data.
+func (m *metricIperfWindow) init() {
+	m.data.SetName("iperf.window")
+	m.data.SetDescription("Effective socket buffer size used by the test, which may be capped by kernel limits")
+	m.data.SetUnit("By")
+	m.data.SetEmptyGauge()
+	m.data.Gauge().DataPoints().EnsureCapacity(m.capacity)
+}
+
+func (m *metricIperfWindow) recordDataPoint(start pcommon.Timestamp, ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+	if !m.config.Enabled {
+		return
+	}
+	dp := m.data.Gauge().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(val)
+	dp.Attributes().PutStr("iperf.test.protocol", iperfTestProtocolAttributeValue)
+	dp.Attributes().PutStr("iperf.test.direction", iperfTestDirectionAttributeValue)
+}
+
+// updateCapacity saves max length of data point slices that will be used for the slice capacity.
+func (m *metricIperfWindow) updateCapacity() {
+	if m.data.Gauge().DataPoints().Len() > m.capacity {
+		m.capacity = m.data.Gauge().DataPoints().Len()
+	}
+}
+
+// emit appends recorded metric data to a metrics slice and prepares it for recording another set of data points.
+func (m *metricIperfWindow) emit(metrics pmetric.MetricSlice) {
+	if m.config.Enabled && m.data.Gauge().DataPoints().Len() > 0 {
+		m.updateCapacity()
+		m.data.MoveTo(metrics.AppendEmpty())
+		m.init()
+	}
+}
+
+func newMetricIperfWindow(cfg MetricConfig) metricIperfWindow {
+	m := metricIperfWindow{config: cfg}
+	if cfg.Enabled {
+		m.data = pmetric.NewMetric()
+		m.init()
+	}
+	return m
+}
+
 // MetricsBuilder provides an interface for scrapers to report metrics while taking care of all the transformations
 // required to produce metric representation defined in metadata and user config.
 type MetricsBuilder struct {
-	config                         MetricsBuilderConfig // config of the metrics builder.
-	startTime                      pcommon.Timestamp    // start time that will be applied to all recorded data points.
-	metricsCapacity                int                  // maximum observed number of metrics per resource.
-	metricsBuffer                  pmetric.Metrics      // accumulates metrics data before emitting.
-	buildInfo                      component.BuildInfo  // contains version information.
-	resourceAttributeIncludeFilter map[string]filter.Filter
-	resourceAttributeExcludeFilter map[string]filter.Filter
-	metricIperfBandwidth           metricIperfBandwidth
-	metricIperfCPUUtilization      metricIperfCPUUtilization
-	metricIperfCwnd                metricIperfCwnd
-	metricIperfJitter              metricIperfJitter
-	metricIperfPacketLoss          metricIperfPacketLoss
-	metricIperfRetransmits         metricIperfRetransmits
-	metricIperfRtt                 metricIperfRtt
-	metricIperfTestDuration        metricIperfTestDuration
-	metricIperfTestError           metricIperfTestError
-	metricIperfTransfer            metricIperfTransfer
+	config                             MetricsBuilderConfig // config of the metrics builder.
+	startTime                          pcommon.Timestamp    // start time that will be applied to all recorded data points.
+	metricsCapacity                    int                  // maximum observed number of metrics per resource.
+	metricsBuffer                      pmetric.Metrics      // accumulates metrics data before emitting.
+	buildInfo                          component.BuildInfo  // contains version information.
+	resourceAttributeIncludeFilter     map[string]filter.Filter
+	resourceAttributeExcludeFilter     map[string]filter.Filter
+	metricIperfBandwidth               metricIperfBandwidth
+	metricIperfBandwidthStddev         metricIperfBandwidthStddev
+	metricIperfBuildInfo               metricIperfBuildInfo
+	metricIperfCPUModeUtilization      metricIperfCPUModeUtilization
+	metricIperfCPUUtilization          metricIperfCPUUtilization
+	metricIperfIntervalBandwidth       metricIperfIntervalBandwidth
+	metricIperfIntervalJitter          metricIperfIntervalJitter
+	metricIperfIntervalPacketLoss      metricIperfIntervalPacketLoss
+	metricIperfIntervalRetransmits     metricIperfIntervalRetransmits
+	metricIperfIntervalTCPSndCwnd      metricIperfIntervalTCPSndCwnd
+	metricIperfJitter                  metricIperfJitter
+	metricIperfPacketLoss              metricIperfPacketLoss
+	metricIperfRetransmits             metricIperfRetransmits
+	metricIperfRtt                     metricIperfRtt
+	metricIperfRttVariance             metricIperfRttVariance
+	metricIperfServerActiveConnections metricIperfServerActiveConnections
+	metricIperfServerRestart           metricIperfServerRestart
+	metricIperfTCPSndCwnd              metricIperfTCPSndCwnd
+	metricIperfTestAttempts            metricIperfTestAttempts
+	metricIperfTestDuration            metricIperfTestDuration
+	metricIperfTestError               metricIperfTestError
+	metricIperfTestRequestedDuration   metricIperfTestRequestedDuration
+	metricIperfTestSkipped             metricIperfTestSkipped
+	metricIperfTestTruncated           metricIperfTestTruncated
+	metricIperfTransfer                metricIperfTransfer
+	metricIperfWindow                  metricIperfWindow
 }
 
 // MetricBuilderOption applies changes to default metrics builder.
@@ -625,22 +1536,44 @@ func WithStartTime(startTime pcommon.Timestamp) MetricBuilderOption {
 }
 func NewMetricsBuilder(mbc MetricsBuilderConfig, settings receiver.Settings, options ...MetricBuilderOption) *MetricsBuilder {
 	mb := &MetricsBuilder{
-		config:                         mbc,
-		startTime:                      pcommon.NewTimestampFromTime(time.Now()),
-		metricsBuffer:                  pmetric.NewMetrics(),
-		buildInfo:                      settings.BuildInfo,
-		metricIperfBandwidth:           newMetricIperfBandwidth(mbc.Metrics.IperfBandwidth),
-		metricIperfCPUUtilization:      newMetricIperfCPUUtilization(mbc.Metrics.IperfCPUUtilization),
-		metricIperfCwnd:                newMetricIperfCwnd(mbc.Metrics.IperfCwnd),
-		metricIperfJitter:              newMetricIperfJitter(mbc.Metrics.IperfJitter),
-		metricIperfPacketLoss:          newMetricIperfPacketLoss(mbc.Metrics.IperfPacketLoss),
-		metricIperfRetransmits:         newMetricIperfRetransmits(mbc.Metrics.IperfRetransmits),
-		metricIperfRtt:                 newMetricIperfRtt(mbc.Metrics.IperfRtt),
-		metricIperfTestDuration:        newMetricIperfTestDuration(mbc.Metrics.IperfTestDuration),
-		metricIperfTestError:           newMetricIperfTestError(mbc.Metrics.IperfTestError),
-		metricIperfTransfer:            newMetricIperfTransfer(mbc.Metrics.IperfTransfer),
-		resourceAttributeIncludeFilter: make(map[string]filter.Filter),
-		resourceAttributeExcludeFilter: make(map[string]filter.Filter),
+		config:                             mbc,
+		startTime:                          pcommon.NewTimestampFromTime(time.Now()),
+		metricsBuffer:                      pmetric.NewMetrics(),
+		buildInfo:                          settings.BuildInfo,
+		metricIperfBandwidth:               newMetricIperfBandwidth(mbc.Metrics.IperfBandwidth),
+		metricIperfBandwidthStddev:         newMetricIperfBandwidthStddev(mbc.Metrics.IperfBandwidthStddev),
+		metricIperfBuildInfo:               newMetricIperfBuildInfo(mbc.Metrics.IperfBuildInfo),
+		metricIperfCPUModeUtilization:      newMetricIperfCPUModeUtilization(mbc.Metrics.IperfCPUModeUtilization),
+		metricIperfCPUUtilization:          newMetricIperfCPUUtilization(mbc.Metrics.IperfCPUUtilization),
+		metricIperfIntervalBandwidth:       newMetricIperfIntervalBandwidth(mbc.Metrics.IperfIntervalBandwidth),
+		metricIperfIntervalJitter:          newMetricIperfIntervalJitter(mbc.Metrics.IperfIntervalJitter),
+		metricIperfIntervalPacketLoss:      newMetricIperfIntervalPacketLoss(mbc.Metrics.IperfIntervalPacketLoss),
+		metricIperfIntervalRetransmits:     newMetricIperfIntervalRetransmits(mbc.Metrics.IperfIntervalRetransmits),
+		metricIperfIntervalTCPSndCwnd:      newMetricIperfIntervalTCPSndCwnd(mbc.Metrics.IperfIntervalTCPSndCwnd),
+		metricIperfJitter:                  newMetricIperfJitter(mbc.Metrics.IperfJitter),
+		metricIperfPacketLoss:              newMetricIperfPacketLoss(mbc.Metrics.IperfPacketLoss),
+		metricIperfRetransmits:             newMetricIperfRetransmits(mbc.Metrics.IperfRetransmits),
+		metricIperfRtt:                     newMetricIperfRtt(mbc.Metrics.IperfRtt),
+		metricIperfRttVariance:             newMetricIperfRttVariance(mbc.Metrics.IperfRttVariance),
+		metricIperfServerActiveConnections: newMetricIperfServerActiveConnections(mbc.Metrics.IperfServerActiveConnections),
+		metricIperfServerRestart:           newMetricIperfServerRestart(mbc.Metrics.IperfServerRestart),
+		metricIperfTCPSndCwnd:              newMetricIperfTCPSndCwnd(mbc.Metrics.IperfTCPSndCwnd),
+		metricIperfTestAttempts:            newMetricIperfTestAttempts(mbc.Metrics.IperfTestAttempts),
+		metricIperfTestDuration:            newMetricIperfTestDuration(mbc.Metrics.IperfTestDuration),
+		metricIperfTestError:               newMetricIperfTestError(mbc.Metrics.IperfTestError),
+		metricIperfTestRequestedDuration:   newMetricIperfTestRequestedDuration(mbc.Metrics.IperfTestRequestedDuration),
+		metricIperfTestSkipped:             newMetricIperfTestSkipped(mbc.Metrics.IperfTestSkipped),
+		metricIperfTestTruncated:           newMetricIperfTestTruncated(mbc.Metrics.IperfTestTruncated),
+		metricIperfTransfer:                newMetricIperfTransfer(mbc.Metrics.IperfTransfer),
+		metricIperfWindow:                  newMetricIperfWindow(mbc.Metrics.IperfWindow),
+		resourceAttributeIncludeFilter:     make(map[string]filter.Filter),
+		resourceAttributeExcludeFilter:     make(map[string]filter.Filter),
+	}
+	if mbc.ResourceAttributes.IperfTargetAddressFamily.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.target.address_family"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTargetAddressFamily.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTargetAddressFamily.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.target.address_family"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTargetAddressFamily.MetricsExclude)
 	}
 	if mbc.ResourceAttributes.IperfTargetHost.MetricsInclude != nil {
 		mb.resourceAttributeIncludeFilter["iperf.target.host"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTargetHost.MetricsInclude)
@@ -654,6 +1587,66 @@ func NewMetricsBuilder(mbc MetricsBuilderConfig, settings receiver.Settings, opt
 	if mbc.ResourceAttributes.IperfTargetPort.MetricsExclude != nil {
 		mb.resourceAttributeExcludeFilter["iperf.target.port"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTargetPort.MetricsExclude)
 	}
+	if mbc.ResourceAttributes.IperfTestAggregation.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.aggregation"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestAggregation.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestAggregation.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.aggregation"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestAggregation.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfTestBitrate.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.bitrate"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestBitrate.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestBitrate.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.bitrate"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestBitrate.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfTestFqRate.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.fq_rate"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestFqRate.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestFqRate.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.fq_rate"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestFqRate.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfTestPps.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.pps"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestPps.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestPps.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.pps"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestPps.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfTestSamples.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.samples"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestSamples.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestSamples.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.samples"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestSamples.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfTestTermination.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.termination"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestTermination.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestTermination.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.termination"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestTermination.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfTestTos.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.test.tos"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestTos.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfTestTos.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.test.tos"] = filter.CreateFilter(mbc.ResourceAttributes.IperfTestTos.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.IperfVersion.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["iperf.version"] = filter.CreateFilter(mbc.ResourceAttributes.IperfVersion.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.IperfVersion.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["iperf.version"] = filter.CreateFilter(mbc.ResourceAttributes.IperfVersion.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.ServerAddress.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["server.address"] = filter.CreateFilter(mbc.ResourceAttributes.ServerAddress.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.ServerAddress.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["server.address"] = filter.CreateFilter(mbc.ResourceAttributes.ServerAddress.MetricsExclude)
+	}
+	if mbc.ResourceAttributes.ServerPort.MetricsInclude != nil {
+		mb.resourceAttributeIncludeFilter["server.port"] = filter.CreateFilter(mbc.ResourceAttributes.ServerPort.MetricsInclude)
+	}
+	if mbc.ResourceAttributes.ServerPort.MetricsExclude != nil {
+		mb.resourceAttributeExcludeFilter["server.port"] = filter.CreateFilter(mbc.ResourceAttributes.ServerPort.MetricsExclude)
+	}
 
 	for _, op := range options {
 		op.apply(mb)
@@ -724,15 +1717,31 @@ func (mb *MetricsBuilder) EmitForResource(options ...ResourceMetricsOption) {
 	ils.Scope().SetVersion(mb.buildInfo.Version)
 	ils.Metrics().EnsureCapacity(mb.metricsCapacity)
 	mb.metricIperfBandwidth.emit(ils.Metrics())
+	mb.metricIperfBandwidthStddev.emit(ils.Metrics())
+	mb.metricIperfBuildInfo.emit(ils.Metrics())
+	mb.metricIperfCPUModeUtilization.emit(ils.Metrics())
 	mb.metricIperfCPUUtilization.emit(ils.Metrics())
-	mb.metricIperfCwnd.emit(ils.Metrics())
+	mb.metricIperfIntervalBandwidth.emit(ils.Metrics())
+	mb.metricIperfIntervalJitter.emit(ils.Metrics())
+	mb.metricIperfIntervalPacketLoss.emit(ils.Metrics())
+	mb.metricIperfIntervalRetransmits.emit(ils.Metrics())
+	mb.metricIperfIntervalTCPSndCwnd.emit(ils.Metrics())
 	mb.metricIperfJitter.emit(ils.Metrics())
 	mb.metricIperfPacketLoss.emit(ils.Metrics())
 	mb.metricIperfRetransmits.emit(ils.Metrics())
 	mb.metricIperfRtt.emit(ils.Metrics())
+	mb.metricIperfRttVariance.emit(ils.Metrics())
+	mb.metricIperfServerActiveConnections.emit(ils.Metrics())
+	mb.metricIperfServerRestart.emit(ils.Metrics())
+	mb.metricIperfTCPSndCwnd.emit(ils.Metrics())
+	mb.metricIperfTestAttempts.emit(ils.Metrics())
 	mb.metricIperfTestDuration.emit(ils.Metrics())
 	mb.metricIperfTestError.emit(ils.Metrics())
+	mb.metricIperfTestRequestedDuration.emit(ils.Metrics())
+	mb.metricIperfTestSkipped.emit(ils.Metrics())
+	mb.metricIperfTestTruncated.emit(ils.Metrics())
 	mb.metricIperfTransfer.emit(ils.Metrics())
+	mb.metricIperfWindow.emit(ils.Metrics())
 
 	for _, op := range options {
 		op.apply(rm)
@@ -769,24 +1778,59 @@ func (mb *MetricsBuilder) RecordIperfBandwidthDataPoint(ts pcommon.Timestamp, va
 	mb.metricIperfBandwidth.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfTestStreamsAttributeValue)
 }
 
+// RecordIperfBandwidthStddevDataPoint adds a data point to iperf.bandwidth.stddev metric.
+func (mb *MetricsBuilder) RecordIperfBandwidthStddevDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+	mb.metricIperfBandwidthStddev.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue)
+}
+
+// RecordIperfBuildInfoDataPoint adds a data point to iperf.build.info metric.
+func (mb *MetricsBuilder) RecordIperfBuildInfoDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricIperfBuildInfo.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordIperfCPUModeUtilizationDataPoint adds a data point to iperf.cpu.mode_utilization metric.
+func (mb *MetricsBuilder) RecordIperfCPUModeUtilizationDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfCPUModeAttributeValue string) {
+	mb.metricIperfCPUModeUtilization.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfCPUModeAttributeValue)
+}
+
 // RecordIperfCPUUtilizationDataPoint adds a data point to iperf.cpu.utilization metric.
 func (mb *MetricsBuilder) RecordIperfCPUUtilizationDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
 	mb.metricIperfCPUUtilization.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue)
 }
 
-// RecordIperfCwndDataPoint adds a data point to iperf.cwnd metric.
-func (mb *MetricsBuilder) RecordIperfCwndDataPoint(ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
-	mb.metricIperfCwnd.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue)
+// RecordIperfIntervalBandwidthDataPoint adds a data point to iperf.interval.bandwidth metric.
+func (mb *MetricsBuilder) RecordIperfIntervalBandwidthDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	mb.metricIperfIntervalBandwidth.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfIntervalIndexAttributeValue)
+}
+
+// RecordIperfIntervalJitterDataPoint adds a data point to iperf.interval.jitter metric.
+func (mb *MetricsBuilder) RecordIperfIntervalJitterDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	mb.metricIperfIntervalJitter.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfIntervalIndexAttributeValue)
+}
+
+// RecordIperfIntervalPacketLossDataPoint adds a data point to iperf.interval.packet_loss metric.
+func (mb *MetricsBuilder) RecordIperfIntervalPacketLossDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	mb.metricIperfIntervalPacketLoss.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfIntervalIndexAttributeValue)
+}
+
+// RecordIperfIntervalRetransmitsDataPoint adds a data point to iperf.interval.retransmits metric.
+func (mb *MetricsBuilder) RecordIperfIntervalRetransmitsDataPoint(ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	mb.metricIperfIntervalRetransmits.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfIntervalIndexAttributeValue)
+}
+
+// RecordIperfIntervalTCPSndCwndDataPoint adds a data point to iperf.interval.tcp.snd_cwnd metric.
+func (mb *MetricsBuilder) RecordIperfIntervalTCPSndCwndDataPoint(ts pcommon.Timestamp, val int64, iperfTCPCongestionAttributeValue string, iperfIntervalIndexAttributeValue int64) {
+	mb.metricIperfIntervalTCPSndCwnd.recordDataPoint(mb.startTime, ts, val, iperfTCPCongestionAttributeValue, iperfIntervalIndexAttributeValue)
 }
 
 // RecordIperfJitterDataPoint adds a data point to iperf.jitter metric.
-func (mb *MetricsBuilder) RecordIperfJitterDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
-	mb.metricIperfJitter.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue)
+func (mb *MetricsBuilder) RecordIperfJitterDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfTestLengthAttributeValue int64) {
+	mb.metricIperfJitter.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfTestLengthAttributeValue)
 }
 
 // RecordIperfPacketLossDataPoint adds a data point to iperf.packet_loss metric.
-func (mb *MetricsBuilder) RecordIperfPacketLossDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
-	mb.metricIperfPacketLoss.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue)
+func (mb *MetricsBuilder) RecordIperfPacketLossDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string, iperfTestLengthAttributeValue int64) {
+	mb.metricIperfPacketLoss.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue, iperfTestLengthAttributeValue)
 }
 
 // RecordIperfRetransmitsDataPoint adds a data point to iperf.retransmits metric.
@@ -795,8 +1839,33 @@ func (mb *MetricsBuilder) RecordIperfRetransmitsDataPoint(ts pcommon.Timestamp,
 }
 
 // RecordIperfRttDataPoint adds a data point to iperf.rtt metric.
-func (mb *MetricsBuilder) RecordIperfRttDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
-	mb.metricIperfRtt.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue)
+func (mb *MetricsBuilder) RecordIperfRttDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string, iperfRttStatisticAttributeValue string) {
+	mb.metricIperfRtt.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfRttStatisticAttributeValue)
+}
+
+// RecordIperfRttVarianceDataPoint adds a data point to iperf.rtt.variance metric.
+func (mb *MetricsBuilder) RecordIperfRttVarianceDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
+	mb.metricIperfRttVariance.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue)
+}
+
+// RecordIperfServerActiveConnectionsDataPoint adds a data point to iperf.server.active_connections metric.
+func (mb *MetricsBuilder) RecordIperfServerActiveConnectionsDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricIperfServerActiveConnections.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordIperfServerRestartDataPoint adds a data point to iperf.server.restart metric.
+func (mb *MetricsBuilder) RecordIperfServerRestartDataPoint(ts pcommon.Timestamp, val int64) {
+	mb.metricIperfServerRestart.recordDataPoint(mb.startTime, ts, val)
+}
+
+// RecordIperfTCPSndCwndDataPoint adds a data point to iperf.tcp.snd_cwnd metric.
+func (mb *MetricsBuilder) RecordIperfTCPSndCwndDataPoint(ts pcommon.Timestamp, val int64, iperfTCPCongestionAttributeValue string) {
+	mb.metricIperfTCPSndCwnd.recordDataPoint(mb.startTime, ts, val, iperfTCPCongestionAttributeValue)
+}
+
+// RecordIperfTestAttemptsDataPoint adds a data point to iperf.test.attempts metric.
+func (mb *MetricsBuilder) RecordIperfTestAttemptsDataPoint(ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
+	mb.metricIperfTestAttempts.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue)
 }
 
 // RecordIperfTestDurationDataPoint adds a data point to iperf.test.duration metric.
@@ -805,8 +1874,23 @@ func (mb *MetricsBuilder) RecordIperfTestDurationDataPoint(ts pcommon.Timestamp,
 }
 
 // RecordIperfTestErrorDataPoint adds a data point to iperf.test.error metric.
-func (mb *MetricsBuilder) RecordIperfTestErrorDataPoint(ts pcommon.Timestamp, val int64, errorMessageAttributeValue string) {
-	mb.metricIperfTestError.recordDataPoint(mb.startTime, ts, val, errorMessageAttributeValue)
+func (mb *MetricsBuilder) RecordIperfTestErrorDataPoint(ts pcommon.Timestamp, val int64, errorMessageAttributeValue string, errorReasonAttributeValue string) {
+	mb.metricIperfTestError.recordDataPoint(mb.startTime, ts, val, errorMessageAttributeValue, errorReasonAttributeValue)
+}
+
+// RecordIperfTestRequestedDurationDataPoint adds a data point to iperf.test.requested_duration metric.
+func (mb *MetricsBuilder) RecordIperfTestRequestedDurationDataPoint(ts pcommon.Timestamp, val float64, iperfTestProtocolAttributeValue string) {
+	mb.metricIperfTestRequestedDuration.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue)
+}
+
+// RecordIperfTestSkippedDataPoint adds a data point to iperf.test.skipped metric.
+func (mb *MetricsBuilder) RecordIperfTestSkippedDataPoint(ts pcommon.Timestamp, val int64, iperfSkipReasonAttributeValue string) {
+	mb.metricIperfTestSkipped.recordDataPoint(mb.startTime, ts, val, iperfSkipReasonAttributeValue)
+}
+
+// RecordIperfTestTruncatedDataPoint adds a data point to iperf.test.truncated metric.
+func (mb *MetricsBuilder) RecordIperfTestTruncatedDataPoint(ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string) {
+	mb.metricIperfTestTruncated.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue)
 }
 
 // RecordIperfTransferDataPoint adds a data point to iperf.transfer metric.
@@ -814,6 +1898,11 @@ func (mb *MetricsBuilder) RecordIperfTransferDataPoint(ts pcommon.Timestamp, val
 	mb.metricIperfTransfer.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue)
 }
 
+// RecordIperfWindowDataPoint adds a data point to iperf.window metric.
+func (mb *MetricsBuilder) RecordIperfWindowDataPoint(ts pcommon.Timestamp, val int64, iperfTestProtocolAttributeValue string, iperfTestDirectionAttributeValue string) {
+	mb.metricIperfWindow.recordDataPoint(mb.startTime, ts, val, iperfTestProtocolAttributeValue, iperfTestDirectionAttributeValue)
+}
+
 // Reset resets metrics builder to its initial state. It should be used when external metrics source is restarted,
 // and metrics builder should update its startTime and reset it's internal state accordingly.
 func (mb *MetricsBuilder) Reset(options ...MetricBuilderOption) {
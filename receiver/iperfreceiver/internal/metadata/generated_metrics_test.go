@@ -72,26 +72,75 @@ func TestMetricsBuilder(t *testing.T) {
 			allMetricsCount++
 			mb.RecordIperfBandwidthDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", 18)
 
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfBandwidthStddevDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfBuildInfoDataPoint(ts, 1)
+
+			allMetricsCount++
+			mb.RecordIperfCPUModeUtilizationDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", "iperf.cpu.mode-val")
+
 			allMetricsCount++
 			mb.RecordIperfCPUUtilizationDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val")
 
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfIntervalBandwidthDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", 20)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfIntervalJitterDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", 20)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfIntervalPacketLossDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", 20)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfIntervalRetransmitsDataPoint(ts, 1, "iperf.test.protocol-val", 20)
+
+			defaultMetricsCount++
 			allMetricsCount++
-			mb.RecordIperfCwndDataPoint(ts, 1, "iperf.test.protocol-val")
+			mb.RecordIperfIntervalTCPSndCwndDataPoint(ts, 1, "iperf.tcp.congestion-val", 20)
 
 			defaultMetricsCount++
 			allMetricsCount++
-			mb.RecordIperfJitterDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val")
+			mb.RecordIperfJitterDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", 17)
 
 			defaultMetricsCount++
 			allMetricsCount++
-			mb.RecordIperfPacketLossDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val")
+			mb.RecordIperfPacketLossDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val", 17)
 
 			defaultMetricsCount++
 			allMetricsCount++
 			mb.RecordIperfRetransmitsDataPoint(ts, 1, "iperf.test.protocol-val")
 
+			defaultMetricsCount++
 			allMetricsCount++
-			mb.RecordIperfRttDataPoint(ts, 1, "iperf.test.protocol-val")
+			mb.RecordIperfRttDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.rtt.statistic-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfRttVarianceDataPoint(ts, 1, "iperf.test.protocol-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfServerActiveConnectionsDataPoint(ts, 1)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfServerRestartDataPoint(ts, 1)
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfTCPSndCwndDataPoint(ts, 1, "iperf.tcp.congestion-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfTestAttemptsDataPoint(ts, 1, "iperf.test.protocol-val")
 
 			defaultMetricsCount++
 			allMetricsCount++
@@ -99,15 +148,43 @@ func TestMetricsBuilder(t *testing.T) {
 
 			defaultMetricsCount++
 			allMetricsCount++
-			mb.RecordIperfTestErrorDataPoint(ts, 1, "error.message-val")
+			mb.RecordIperfTestErrorDataPoint(ts, 1, "error.message-val", "error.reason-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfTestRequestedDurationDataPoint(ts, 1, "iperf.test.protocol-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfTestSkippedDataPoint(ts, 1, "iperf.skip.reason-val")
+
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfTestTruncatedDataPoint(ts, 1, "iperf.test.protocol-val")
 
 			defaultMetricsCount++
 			allMetricsCount++
 			mb.RecordIperfTransferDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val")
 
+			defaultMetricsCount++
+			allMetricsCount++
+			mb.RecordIperfWindowDataPoint(ts, 1, "iperf.test.protocol-val", "iperf.test.direction-val")
+
 			rb := mb.NewResourceBuilder()
+			rb.SetIperfTargetAddressFamily("iperf.target.address_family-val")
 			rb.SetIperfTargetHost("iperf.target.host-val")
 			rb.SetIperfTargetPort(17)
+			rb.SetIperfTestAggregation("iperf.test.aggregation-val")
+			rb.SetIperfTestBitrate("iperf.test.bitrate-val")
+			rb.SetIperfTestFqRate("iperf.test.fq_rate-val")
+			rb.SetIperfTestLength(17)
+			rb.SetIperfTestPps(14)
+			rb.SetIperfTestSamples(12)
+			rb.SetIperfTestTermination("iperf.test.termination-val")
+			rb.SetIperfTestTos(14)
+			rb.SetIperfVersion("iperf.version-val")
+			rb.SetServerAddress("server.address-val")
+			rb.SetServerPort(11)
 			res := rb.Emit()
 			metrics := mb.Emit(WithResource(res))
 
@@ -151,6 +228,57 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok = dp.Attributes().Get("iperf.test.streams")
 					assert.True(t, ok)
 					assert.EqualValues(t, 18, attrVal.Int())
+				case "iperf.bandwidth.stddev":
+					assert.False(t, validatedMetrics["iperf.bandwidth.stddev"], "Found a duplicate in the metrics slice: iperf.bandwidth.stddev")
+					validatedMetrics["iperf.bandwidth.stddev"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Standard deviation of the bandwidth across the samples of a test, recorded when several samples are run", ms.At(i).Description())
+					assert.Equal(t, "bit/s", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+				case "iperf.build.info":
+					assert.False(t, validatedMetrics["iperf.build.info"], "Found a duplicate in the metrics slice: iperf.build.info")
+					validatedMetrics["iperf.build.info"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Always 1, identifies the iperf3 version through the iperf.version resource attribute", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+				case "iperf.cpu.mode_utilization":
+					assert.False(t, validatedMetrics["iperf.cpu.mode_utilization"], "Found a duplicate in the metrics slice: iperf.cpu.mode_utilization")
+					validatedMetrics["iperf.cpu.mode_utilization"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "CPU utilization during the test split into user and system time", ms.At(i).Description())
+					assert.Equal(t, "%", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.cpu.mode")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.cpu.mode-val", attrVal.Str())
 				case "iperf.cpu.utilization":
 					assert.False(t, validatedMetrics["iperf.cpu.utilization"], "Found a duplicate in the metrics slice: iperf.cpu.utilization")
 					validatedMetrics["iperf.cpu.utilization"] = true
@@ -169,13 +297,76 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
 					assert.True(t, ok)
 					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
-				case "iperf.cwnd":
-					assert.False(t, validatedMetrics["iperf.cwnd"], "Found a duplicate in the metrics slice: iperf.cwnd")
-					validatedMetrics["iperf.cwnd"] = true
+				case "iperf.interval.bandwidth":
+					assert.False(t, validatedMetrics["iperf.interval.bandwidth"], "Found a duplicate in the metrics slice: iperf.interval.bandwidth")
+					validatedMetrics["iperf.interval.bandwidth"] = true
 					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
 					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
-					assert.Equal(t, "TCP congestion window size", ms.At(i).Description())
-					assert.Equal(t, "By", ms.At(i).Unit())
+					assert.Equal(t, "Network bandwidth measured during a single reporting interval", ms.At(i).Description())
+					assert.Equal(t, "bit/s", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.interval.index")
+					assert.True(t, ok)
+					assert.EqualValues(t, 20, attrVal.Int())
+				case "iperf.interval.jitter":
+					assert.False(t, validatedMetrics["iperf.interval.jitter"], "Found a duplicate in the metrics slice: iperf.interval.jitter")
+					validatedMetrics["iperf.interval.jitter"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Jitter measured during a single reporting interval (UDP only)", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.interval.index")
+					assert.True(t, ok)
+					assert.EqualValues(t, 20, attrVal.Int())
+				case "iperf.interval.packet_loss":
+					assert.False(t, validatedMetrics["iperf.interval.packet_loss"], "Found a duplicate in the metrics slice: iperf.interval.packet_loss")
+					validatedMetrics["iperf.interval.packet_loss"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Percentage of packets lost during a single reporting interval (UDP only)", ms.At(i).Description())
+					assert.Equal(t, "%", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.interval.index")
+					assert.True(t, ok)
+					assert.EqualValues(t, 20, attrVal.Int())
+				case "iperf.interval.retransmits":
+					assert.False(t, validatedMetrics["iperf.interval.retransmits"], "Found a duplicate in the metrics slice: iperf.interval.retransmits")
+					validatedMetrics["iperf.interval.retransmits"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Number of retransmissions during a single reporting interval (TCP and SCTP only)", ms.At(i).Description())
+					assert.Equal(t, "{retransmission}", ms.At(i).Unit())
 					dp := ms.At(i).Gauge().DataPoints().At(0)
 					assert.Equal(t, start, dp.StartTimestamp())
 					assert.Equal(t, ts, dp.Timestamp())
@@ -184,6 +375,27 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
 					assert.True(t, ok)
 					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.interval.index")
+					assert.True(t, ok)
+					assert.EqualValues(t, 20, attrVal.Int())
+				case "iperf.interval.tcp.snd_cwnd":
+					assert.False(t, validatedMetrics["iperf.interval.tcp.snd_cwnd"], "Found a duplicate in the metrics slice: iperf.interval.tcp.snd_cwnd")
+					validatedMetrics["iperf.interval.tcp.snd_cwnd"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Largest TCP sender congestion window of any stream at the end of a single reporting interval (TCP only)", ms.At(i).Description())
+					assert.Equal(t, "By", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("iperf.tcp.congestion")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.tcp.congestion-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.interval.index")
+					assert.True(t, ok)
+					assert.EqualValues(t, 20, attrVal.Int())
 				case "iperf.jitter":
 					assert.False(t, validatedMetrics["iperf.jitter"], "Found a duplicate in the metrics slice: iperf.jitter")
 					validatedMetrics["iperf.jitter"] = true
@@ -202,6 +414,9 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
 					assert.True(t, ok)
 					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.length")
+					assert.True(t, ok)
+					assert.EqualValues(t, 17, attrVal.Int())
 				case "iperf.packet_loss":
 					assert.False(t, validatedMetrics["iperf.packet_loss"], "Found a duplicate in the metrics slice: iperf.packet_loss")
 					validatedMetrics["iperf.packet_loss"] = true
@@ -220,12 +435,15 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
 					assert.True(t, ok)
 					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.length")
+					assert.True(t, ok)
+					assert.EqualValues(t, 17, attrVal.Int())
 				case "iperf.retransmits":
 					assert.False(t, validatedMetrics["iperf.retransmits"], "Found a duplicate in the metrics slice: iperf.retransmits")
 					validatedMetrics["iperf.retransmits"] = true
 					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
 					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
-					assert.Equal(t, "Number of TCP retransmissions (TCP only)", ms.At(i).Description())
+					assert.Equal(t, "Number of retransmissions (TCP and SCTP only)", ms.At(i).Description())
 					assert.Equal(t, "{retransmission}", ms.At(i).Unit())
 					assert.True(t, ms.At(i).Sum().IsMonotonic())
 					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
@@ -242,7 +460,7 @@ func TestMetricsBuilder(t *testing.T) {
 					validatedMetrics["iperf.rtt"] = true
 					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
 					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
-					assert.Equal(t, "Round trip time", ms.At(i).Description())
+					assert.Equal(t, "Round trip time measured by the sender's TCP stack (TCP only)", ms.At(i).Description())
 					assert.Equal(t, "ms", ms.At(i).Unit())
 					dp := ms.At(i).Gauge().DataPoints().At(0)
 					assert.Equal(t, start, dp.StartTimestamp())
@@ -252,6 +470,80 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
 					assert.True(t, ok)
 					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.rtt.statistic")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.rtt.statistic-val", attrVal.Str())
+				case "iperf.rtt.variance":
+					assert.False(t, validatedMetrics["iperf.rtt.variance"], "Found a duplicate in the metrics slice: iperf.rtt.variance")
+					validatedMetrics["iperf.rtt.variance"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Smoothed round trip time variation measured by the sender's TCP stack, averaged across streams (TCP only)", ms.At(i).Description())
+					assert.Equal(t, "ms", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+				case "iperf.server.active_connections":
+					assert.False(t, validatedMetrics["iperf.server.active_connections"], "Found a duplicate in the metrics slice: iperf.server.active_connections")
+					validatedMetrics["iperf.server.active_connections"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Number of TCP connections clients currently hold to the iperf3 server, a control connection and one per TCP stream of each running test (server mode, Linux only)", ms.At(i).Description())
+					assert.Equal(t, "{connection}", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+				case "iperf.server.restart":
+					assert.False(t, validatedMetrics["iperf.server.restart"], "Found a duplicate in the metrics slice: iperf.server.restart")
+					validatedMetrics["iperf.server.restart"] = true
+					assert.Equal(t, pmetric.MetricTypeSum, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Sum().DataPoints().Len())
+					assert.Equal(t, "Number of times the iperf3 server was restarted after it exited (server mode)", ms.At(i).Description())
+					assert.Equal(t, "{restart}", ms.At(i).Unit())
+					assert.True(t, ms.At(i).Sum().IsMonotonic())
+					assert.Equal(t, pmetric.AggregationTemporalityCumulative, ms.At(i).Sum().AggregationTemporality())
+					dp := ms.At(i).Sum().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+				case "iperf.tcp.snd_cwnd":
+					assert.False(t, validatedMetrics["iperf.tcp.snd_cwnd"], "Found a duplicate in the metrics slice: iperf.tcp.snd_cwnd")
+					validatedMetrics["iperf.tcp.snd_cwnd"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Largest TCP sender congestion window reached by any stream during the test (TCP only)", ms.At(i).Description())
+					assert.Equal(t, "By", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("iperf.tcp.congestion")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.tcp.congestion-val", attrVal.Str())
+				case "iperf.test.attempts":
+					assert.False(t, validatedMetrics["iperf.test.attempts"], "Found a duplicate in the metrics slice: iperf.test.attempts")
+					validatedMetrics["iperf.test.attempts"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Number of attempts the test took, greater than 1 when it was retried, the highest of the samples when several samples are run", ms.At(i).Description())
+					assert.Equal(t, "{attempt}", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
 				case "iperf.test.duration":
 					assert.False(t, validatedMetrics["iperf.test.duration"], "Found a duplicate in the metrics slice: iperf.test.duration")
 					validatedMetrics["iperf.test.duration"] = true
@@ -284,6 +576,54 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok := dp.Attributes().Get("error.message")
 					assert.True(t, ok)
 					assert.Equal(t, "error.message-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("error.reason")
+					assert.True(t, ok)
+					assert.Equal(t, "error.reason-val", attrVal.Str())
+				case "iperf.test.requested_duration":
+					assert.False(t, validatedMetrics["iperf.test.requested_duration"], "Found a duplicate in the metrics slice: iperf.test.requested_duration")
+					validatedMetrics["iperf.test.requested_duration"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Duration requested for the iperf test, to compare with iperf.test.duration", ms.At(i).Description())
+					assert.Equal(t, "s", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeDouble, dp.ValueType())
+					assert.InDelta(t, float64(1), dp.DoubleValue(), 0.01)
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+				case "iperf.test.skipped":
+					assert.False(t, validatedMetrics["iperf.test.skipped"], "Found a duplicate in the metrics slice: iperf.test.skipped")
+					validatedMetrics["iperf.test.skipped"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Set to 1 when a scheduled test was not run", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("iperf.skip.reason")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.skip.reason-val", attrVal.Str())
+				case "iperf.test.truncated":
+					assert.False(t, validatedMetrics["iperf.test.truncated"], "Found a duplicate in the metrics slice: iperf.test.truncated")
+					validatedMetrics["iperf.test.truncated"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Whether the test ended noticeably earlier than requested, e.g. because the server disconnected (1) or not (0)", ms.At(i).Description())
+					assert.Equal(t, "1", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
 				case "iperf.transfer":
 					assert.False(t, validatedMetrics["iperf.transfer"], "Found a duplicate in the metrics slice: iperf.transfer")
 					validatedMetrics["iperf.transfer"] = true
@@ -304,6 +644,24 @@ func TestMetricsBuilder(t *testing.T) {
 					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
 					assert.True(t, ok)
 					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
+				case "iperf.window":
+					assert.False(t, validatedMetrics["iperf.window"], "Found a duplicate in the metrics slice: iperf.window")
+					validatedMetrics["iperf.window"] = true
+					assert.Equal(t, pmetric.MetricTypeGauge, ms.At(i).Type())
+					assert.Equal(t, 1, ms.At(i).Gauge().DataPoints().Len())
+					assert.Equal(t, "Effective socket buffer size used by the test, which may be capped by kernel limits", ms.At(i).Description())
+					assert.Equal(t, "By", ms.At(i).Unit())
+					dp := ms.At(i).Gauge().DataPoints().At(0)
+					assert.Equal(t, start, dp.StartTimestamp())
+					assert.Equal(t, ts, dp.Timestamp())
+					assert.Equal(t, pmetric.NumberDataPointValueTypeInt, dp.ValueType())
+					assert.Equal(t, int64(1), dp.IntValue())
+					attrVal, ok := dp.Attributes().Get("iperf.test.protocol")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.protocol-val", attrVal.Str())
+					attrVal, ok = dp.Attributes().Get("iperf.test.direction")
+					assert.True(t, ok)
+					assert.Equal(t, "iperf.test.direction-val", attrVal.Str())
 				}
 			}
 		})
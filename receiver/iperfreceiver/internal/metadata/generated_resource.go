@@ -21,6 +21,13 @@ func NewResourceBuilder(rac ResourceAttributesConfig) *ResourceBuilder {
 	}
 }
 
+// SetIperfTargetAddressFamily sets provided value as "iperf.target.address_family" attribute.
+func (rb *ResourceBuilder) SetIperfTargetAddressFamily(val string) {
+	if rb.config.IperfTargetAddressFamily.Enabled {
+		rb.res.Attributes().PutStr("iperf.target.address_family", val)
+	}
+}
+
 // SetIperfTargetHost sets provided value as "iperf.target.host" attribute.
 func (rb *ResourceBuilder) SetIperfTargetHost(val string) {
 	if rb.config.IperfTargetHost.Enabled {
@@ -35,6 +42,76 @@ func (rb *ResourceBuilder) SetIperfTargetPort(val int64) {
 	}
 }
 
+// SetIperfTestAggregation sets provided value as "iperf.test.aggregation" attribute.
+func (rb *ResourceBuilder) SetIperfTestAggregation(val string) {
+	if rb.config.IperfTestAggregation.Enabled {
+		rb.res.Attributes().PutStr("iperf.test.aggregation", val)
+	}
+}
+
+// SetIperfTestBitrate sets provided value as "iperf.test.bitrate" attribute.
+func (rb *ResourceBuilder) SetIperfTestBitrate(val string) {
+	if rb.config.IperfTestBitrate.Enabled {
+		rb.res.Attributes().PutStr("iperf.test.bitrate", val)
+	}
+}
+
+// SetIperfTestFqRate sets provided value as "iperf.test.fq_rate" attribute.
+func (rb *ResourceBuilder) SetIperfTestFqRate(val string) {
+	if rb.config.IperfTestFqRate.Enabled {
+		rb.res.Attributes().PutStr("iperf.test.fq_rate", val)
+	}
+}
+
+// SetIperfTestPps sets provided value as "iperf.test.pps" attribute.
+func (rb *ResourceBuilder) SetIperfTestPps(val int64) {
+	if rb.config.IperfTestPps.Enabled {
+		rb.res.Attributes().PutInt("iperf.test.pps", val)
+	}
+}
+
+// SetIperfTestSamples sets provided value as "iperf.test.samples" attribute.
+func (rb *ResourceBuilder) SetIperfTestSamples(val int64) {
+	if rb.config.IperfTestSamples.Enabled {
+		rb.res.Attributes().PutInt("iperf.test.samples", val)
+	}
+}
+
+// SetIperfTestTermination sets provided value as "iperf.test.termination" attribute.
+func (rb *ResourceBuilder) SetIperfTestTermination(val string) {
+	if rb.config.IperfTestTermination.Enabled {
+		rb.res.Attributes().PutStr("iperf.test.termination", val)
+	}
+}
+
+// SetIperfTestTos sets provided value as "iperf.test.tos" attribute.
+func (rb *ResourceBuilder) SetIperfTestTos(val int64) {
+	if rb.config.IperfTestTos.Enabled {
+		rb.res.Attributes().PutInt("iperf.test.tos", val)
+	}
+}
+
+// SetIperfVersion sets provided value as "iperf.version" attribute.
+func (rb *ResourceBuilder) SetIperfVersion(val string) {
+	if rb.config.IperfVersion.Enabled {
+		rb.res.Attributes().PutStr("iperf.version", val)
+	}
+}
+
+// SetServerAddress sets provided value as "server.address" attribute.
+func (rb *ResourceBuilder) SetServerAddress(val string) {
+	if rb.config.ServerAddress.Enabled {
+		rb.res.Attributes().PutStr("server.address", val)
+	}
+}
+
+// SetServerPort sets provided value as "server.port" attribute.
+func (rb *ResourceBuilder) SetServerPort(val int64) {
+	if rb.config.ServerPort.Enabled {
+		rb.res.Attributes().PutInt("server.port", val)
+	}
+}
+
 // Emit returns the built resource and resets the internal builder state.
 func (rb *ResourceBuilder) Emit() pcommon.Resource {
 	r := rb.res
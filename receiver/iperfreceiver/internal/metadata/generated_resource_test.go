@@ -13,17 +13,28 @@ func TestResourceBuilder(t *testing.T) {
 		t.Run(tt, func(t *testing.T) {
 			cfg := loadResourceAttributesConfig(t, tt)
 			rb := NewResourceBuilder(cfg)
+			rb.SetIperfTargetAddressFamily("iperf.target.address_family-val")
 			rb.SetIperfTargetHost("iperf.target.host-val")
 			rb.SetIperfTargetPort(17)
+			rb.SetIperfTestAggregation("iperf.test.aggregation-val")
+			rb.SetIperfTestBitrate("iperf.test.bitrate-val")
+			rb.SetIperfTestFqRate("iperf.test.fq_rate-val")
+			rb.SetIperfTestPps(14)
+			rb.SetIperfTestSamples(12)
+			rb.SetIperfTestTermination("iperf.test.termination-val")
+			rb.SetIperfTestTos(14)
+			rb.SetIperfVersion("iperf.version-val")
+			rb.SetServerAddress("server.address-val")
+			rb.SetServerPort(11)
 
 			res := rb.Emit()
 			assert.Equal(t, 0, rb.Emit().Attributes().Len()) // Second call should return empty Resource
 
 			switch tt {
 			case "default":
-				assert.Equal(t, 2, res.Attributes().Len())
+				assert.Equal(t, 13, res.Attributes().Len())
 			case "all_set":
-				assert.Equal(t, 2, res.Attributes().Len())
+				assert.Equal(t, 13, res.Attributes().Len())
 			case "none_set":
 				assert.Equal(t, 0, res.Attributes().Len())
 				return
@@ -31,7 +42,12 @@ func TestResourceBuilder(t *testing.T) {
 				assert.Failf(t, "unexpected test case: %s", tt)
 			}
 
-			val, ok := res.Attributes().Get("iperf.target.host")
+			val, ok := res.Attributes().Get("iperf.target.address_family")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "iperf.target.address_family-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("iperf.target.host")
 			assert.True(t, ok)
 			if ok {
 				assert.Equal(t, "iperf.target.host-val", val.Str())
@@ -41,6 +57,56 @@ func TestResourceBuilder(t *testing.T) {
 			if ok {
 				assert.EqualValues(t, 17, val.Int())
 			}
+			val, ok = res.Attributes().Get("iperf.test.aggregation")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "iperf.test.aggregation-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("iperf.test.bitrate")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "iperf.test.bitrate-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("iperf.test.fq_rate")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "iperf.test.fq_rate-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("iperf.test.pps")
+			assert.True(t, ok)
+			if ok {
+				assert.EqualValues(t, 14, val.Int())
+			}
+			val, ok = res.Attributes().Get("iperf.test.samples")
+			assert.True(t, ok)
+			if ok {
+				assert.EqualValues(t, 12, val.Int())
+			}
+			val, ok = res.Attributes().Get("iperf.test.termination")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "iperf.test.termination-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("iperf.test.tos")
+			assert.True(t, ok)
+			if ok {
+				assert.EqualValues(t, 14, val.Int())
+			}
+			val, ok = res.Attributes().Get("iperf.version")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "iperf.version-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("server.address")
+			assert.True(t, ok)
+			if ok {
+				assert.Equal(t, "server.address-val", val.Str())
+			}
+			val, ok = res.Attributes().Get("server.port")
+			assert.True(t, ok)
+			if ok {
+				assert.EqualValues(t, 11, val.Int())
+			}
 		})
 	}
 }
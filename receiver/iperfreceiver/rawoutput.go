@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// debugOutputLog is the debug_output value writing the raw output to the collector log
+const debugOutputLog = "log"
+
+// rawOutput captures the raw iperf3 output of client tests, to the collector log or appended to a file
+type rawOutput struct {
+	mu       sync.Mutex
+	file     *os.File // nil writes to the log
+	maxBytes int
+	logger   *zap.Logger
+}
+
+// newRawOutput opens the destination, "log" or a file path that is created if needed
+func newRawOutput(destination string, maxBytes int, logger *zap.Logger) (*rawOutput, error) {
+	output := &rawOutput{maxBytes: maxBytes, logger: logger}
+	if destination == debugOutputLog {
+		return output, nil
+	}
+	file, err := os.OpenFile(destination, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open debug output file: %w", err)
+	}
+	output.file = file
+	return output, nil
+}
+
+// capture reads what iperf3 wrote to path during a test to the target, cut to maxBytes
+func (o *rawOutput) capture(target TargetConfig, timestamp time.Time, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open iperf3 output: %w", err)
+	}
+	defer file.Close()
+	output, err := io.ReadAll(io.LimitReader(file, int64(o.maxBytes)+1))
+	if err != nil {
+		return fmt.Errorf("failed to read iperf3 output: %w", err)
+	}
+	truncated := len(output) > o.maxBytes
+	if truncated {
+		output = output[:o.maxBytes]
+	}
+	return o.write(target, timestamp, output, truncated)
+}
+
+// write records the output, concurrent tests to different targets are written as separate entries
+func (o *rawOutput) write(target TargetConfig, timestamp time.Time, output []byte, truncated bool) error {
+	if o.file == nil {
+		o.logger.Info("iperf3 output",
+			zap.String("host", target.Host),
+			zap.Int("port", target.Port),
+			zap.Bool("truncated", truncated),
+			zap.ByteString("output", output))
+		return nil
+	}
+
+	header := fmt.Sprintf("# %s %s:%d %s", timestamp.UTC().Format(time.RFC3339), target.Host, target.Port, target.Protocol)
+	if truncated {
+		header += fmt.Sprintf(" (truncated to %d bytes)", o.maxBytes)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, err := fmt.Fprintf(o.file, "%s\n%s\n", header, output)
+	return err
+}
+
+// captureOutput captures the raw output of a test iperf3 wrote to path, a no-op without path
+func (s *scraper) captureOutput(target TargetConfig, timestamp time.Time, path string) {
+	if path == "" {
+		return
+	}
+	if err := s.rawOutput.capture(target, timestamp, path); err != nil {
+		s.logger.Warn("Failed to capture iperf3 output",
+			zap.String("host", target.Host),
+			zap.Int("port", target.Port),
+			zap.Error(err))
+	}
+}
+
+func (o *rawOutput) close() error {
+	if o.file == nil {
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.file.Close()
+}
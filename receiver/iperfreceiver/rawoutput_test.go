@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRawOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "iperf3-output.log")
+	output, err := newRawOutput(path, 16, zap.NewNop())
+	require.NoError(t, err)
+
+	target := TargetConfig{Host: "192.0.2.1", Port: 5201, Protocol: "udp"}
+	timestamp := time.Date(2024, 11, 5, 10, 0, 0, 0, time.UTC)
+	short := filepath.Join(dir, "short.json")
+	require.NoError(t, os.WriteFile(short, []byte(`{"start":{}}`), 0o600))
+	long := filepath.Join(dir, "long.json")
+	require.NoError(t, os.WriteFile(long, []byte(`{"start":{},"end":{"sum":{}}}`), 0o600))
+
+	require.NoError(t, output.capture(target, timestamp, short))
+	require.NoError(t, output.capture(target, timestamp, long))
+	require.NoError(t, output.close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# 2024-11-05T10:00:00Z 192.0.2.1:5201 udp\n"+
+		`{"start":{}}`+"\n"+
+		"# 2024-11-05T10:00:00Z 192.0.2.1:5201 udp (truncated to 16 bytes)\n"+
+		`{"start":{},"end`+"\n", string(data))
+
+	assert.ErrorContains(t, output.capture(target, timestamp, filepath.Join(dir, "missing.json")), "failed to open iperf3 output")
+}
+
+func TestRawOutputLog(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	output, err := newRawOutput(debugOutputLog, 1024, zap.New(core))
+	require.NoError(t, err)
+
+	target := TargetConfig{Host: "192.0.2.1", Port: 5201, Protocol: "tcp"}
+	require.NoError(t, output.write(target, time.Now(), []byte(`{"error":"unable to connect"}`), false))
+	require.NoError(t, output.close())
+
+	entries := logs.FilterMessage("iperf3 output").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "192.0.2.1", fields["host"])
+	assert.Equal(t, false, fields["truncated"])
+	assert.Equal(t, `{"error":"unable to connect"}`, fields["output"])
+}
+
+func TestRawOutputOpenFailure(t *testing.T) {
+	_, err := newRawOutput(filepath.Join(t.TempDir(), "missing", "output.log"), 1024, zap.NewNop())
+	assert.ErrorContains(t, err, "failed to open debug output file")
+}
@@ -0,0 +1,140 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"math"
+	"slices"
+
+	iperf "github.com/BGrewell/go-iperf"
+)
+
+// sample is one of the tests run back to back against a target in a scrape
+type sample struct {
+	report   *iperf.Report
+	duration float64
+	attempt  int
+}
+
+// bandwidthSpread is the standard deviation of the bandwidth of a direction across the samples
+type bandwidthSpread struct {
+	direction string
+	stddev    float64
+}
+
+// sumSections are the summary sections of a report whose bandwidth is aggregated, in recording order
+var sumSections = []struct {
+	direction string
+	sum       func(end *iperf.End) **iperf.Sum
+}{
+	{"send", func(end *iperf.End) **iperf.Sum { return &end.SumSent }},
+	{"receive", func(end *iperf.End) **iperf.Sum { return &end.SumReceived }},
+	{"reverse_send", func(end *iperf.End) **iperf.Sum { return &end.SumSentBidirReverse }},
+	{"reverse_receive", func(end *iperf.End) **iperf.Sum { return &end.SumReceivedBidirReverse }},
+}
+
+// aggregateSamples combines the samples of a scrape into the one its metrics are recorded from. It
+// is the sample whose bandwidth is closest to the aggregate, so that the other metrics describe a
+// representative test, with the bandwidth of each direction replaced by the aggregate. The
+// standard deviation of each direction reported by several samples is returned along.
+func aggregateSamples(samples []sample, aggregation string) (sample, []bandwidthSpread) {
+	if len(samples) == 1 {
+		return samples[0], nil
+	}
+
+	aggregates := make([]float64, len(sumSections))
+	var spreads []bandwidthSpread
+	chosen := -1
+	for i, section := range sumSections {
+		values := make([]float64, 0, len(samples))
+		for _, s := range samples {
+			if s.report.End == nil {
+				continue
+			}
+			if sum := *section.sum(s.report.End); sum != nil {
+				values = append(values, sum.BitsPerSecond)
+			}
+		}
+		if len(values) == 0 {
+			aggregates[i] = math.NaN()
+			continue
+		}
+
+		if aggregation == "mean" {
+			aggregates[i] = mean(values)
+		} else {
+			aggregates[i] = median(values)
+		}
+		if len(values) > 1 {
+			spreads = append(spreads, bandwidthSpread{direction: section.direction, stddev: stddev(values)})
+		}
+
+		// The first direction every sample reports picks the representative sample
+		if chosen < 0 && len(values) == len(samples) {
+			chosen = closestSample(samples, section.sum, aggregates[i])
+		}
+	}
+	if chosen < 0 {
+		chosen = 0
+	}
+
+	// Copy the report, the samples keep their own bandwidth
+	result := samples[chosen]
+	if result.report.End == nil {
+		return result, spreads
+	}
+	report := *result.report
+	end := *report.End
+	report.End = &end
+	for i, section := range sumSections {
+		field := section.sum(&end)
+		if *field == nil || math.IsNaN(aggregates[i]) {
+			continue
+		}
+		sum := **field
+		sum.BitsPerSecond = aggregates[i]
+		*field = &sum
+	}
+	result.report = &report
+	return result, spreads
+}
+
+// closestSample returns the index of the sample whose bandwidth in a section is closest to value
+func closestSample(samples []sample, section func(end *iperf.End) **iperf.Sum, value float64) int {
+	closest, distance := 0, math.Inf(1)
+	for i, s := range samples {
+		if d := math.Abs((*section(s.report.End)).BitsPerSecond - value); d < distance {
+			closest, distance = i, d
+		}
+	}
+	return closest
+}
+
+func mean(values []float64) float64 {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// stddev returns the sample standard deviation of at least two values
+func stddev(values []float64) float64 {
+	m := mean(values)
+	var squares float64
+	for _, v := range values {
+		squares += (v - m) * (v - m)
+	}
+	return math.Sqrt(squares / float64(len(values)-1))
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"testing"
+
+	iperf "github.com/BGrewell/go-iperf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bandwidthSample(sent, received float64, attempt int) sample {
+	return sample{
+		report: &iperf.Report{End: &iperf.End{
+			SumSent:     &iperf.Sum{Bytes: 1000, BitsPerSecond: sent},
+			SumReceived: &iperf.Sum{Bytes: 900, BitsPerSecond: received},
+		}},
+		duration: 10,
+		attempt:  attempt,
+	}
+}
+
+func TestAggregateSamples(t *testing.T) {
+	samples := []sample{
+		bandwidthSample(100, 90, 1),
+		bandwidthSample(400, 380, 2),
+		bandwidthSample(120, 110, 1),
+	}
+
+	// The median keeps the middle sample and its own bandwidth
+	result, spreads := aggregateSamples(samples, "median")
+	assert.Equal(t, 1, result.attempt)
+	assert.InDelta(t, 120, result.report.End.SumSent.BitsPerSecond, 0.01)
+	assert.InDelta(t, 110, result.report.End.SumReceived.BitsPerSecond, 0.01)
+	require.Len(t, spreads, 2)
+	assert.Equal(t, "send", spreads[0].direction)
+	assert.InDelta(t, 167.73, spreads[0].stddev, 0.01)
+	assert.Equal(t, "receive", spreads[1].direction)
+
+	// The mean is written into the sample closest to it, the samples are left untouched
+	result, _ = aggregateSamples(samples, "mean")
+	assert.Equal(t, 1, result.attempt)
+	assert.InDelta(t, 206.67, result.report.End.SumSent.BitsPerSecond, 0.01)
+	assert.InDelta(t, 193.33, result.report.End.SumReceived.BitsPerSecond, 0.01)
+	assert.Equal(t, int64(1000), int64(result.report.End.SumSent.Bytes))
+	assert.InDelta(t, 120, samples[2].report.End.SumSent.BitsPerSecond, 0.01)
+
+	// An even number of samples takes the mean of the two middle ones
+	result, _ = aggregateSamples(samples[:2], "median")
+	assert.InDelta(t, 250, result.report.End.SumSent.BitsPerSecond, 0.01)
+
+	// A single sample is recorded as is
+	result, spreads = aggregateSamples(samples[:1], "median")
+	assert.Same(t, samples[0].report, result.report)
+	assert.Empty(t, spreads)
+}
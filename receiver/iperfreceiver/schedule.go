@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays maps the day names accepted in a schedule to their weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ScheduleConfig restricts tests to a daily time window, e.g. off-peak hours
+type ScheduleConfig struct {
+	// Start is the time of day the window opens, "HH:MM"
+	Start string `mapstructure:"start"`
+
+	// End is the time of day the window closes, "HH:MM". A window ending before it
+	// starts runs over midnight, a window ending when it starts lasts the whole day
+	End string `mapstructure:"end"`
+
+	// Days are the days the window opens on, e.g. ["sat", "sun"], every day when empty
+	Days []string `mapstructure:"days"`
+
+	// Timezone is the IANA time zone of Start and End, the local time zone when empty
+	Timezone string `mapstructure:"timezone"`
+}
+
+// Validate validates the schedule syntax
+func (cfg *ScheduleConfig) Validate() error {
+	if _, err := parseTimeOfDay(cfg.Start); err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	if _, err := parseTimeOfDay(cfg.End); err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	for _, day := range cfg.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid day %q, must be one of sun, mon, tue, wed, thu, fri, sat", day)
+		}
+	}
+	if _, err := cfg.location(); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	return nil
+}
+
+// location returns the time zone of the window
+func (cfg *ScheduleConfig) location() (*time.Location, error) {
+	if cfg.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(cfg.Timezone)
+}
+
+// allows reports whether t falls within the window, the configuration must be valid
+func (cfg *ScheduleConfig) allows(t time.Time) bool {
+	start, _ := parseTimeOfDay(cfg.Start)
+	end, _ := parseTimeOfDay(cfg.End)
+	loc, _ := cfg.location()
+	t = t.In(loc)
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+	switch {
+	case start == end:
+		return cfg.allowsDay(t.Weekday())
+	case start < end:
+		return now >= start && now < end && cfg.allowsDay(t.Weekday())
+	default:
+		// The part after midnight belongs to the window opened the day before
+		if now >= start {
+			return cfg.allowsDay(t.Weekday())
+		}
+		return now < end && cfg.allowsDay((t.Weekday()+6)%7)
+	}
+}
+
+func (cfg *ScheduleConfig) allowsDay(day time.Weekday) bool {
+	if len(cfg.Days) == 0 {
+		return true
+	}
+	for _, name := range cfg.Days {
+		if weekdays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeOfDay parses "HH:MM" into the time since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, errors.New("time of day cannot be empty")
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q must be of the form HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
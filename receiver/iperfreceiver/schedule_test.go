@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         ScheduleConfig
+		expectedErr string
+	}{
+		{name: "valid", cfg: ScheduleConfig{Start: "22:00", End: "06:00", Days: []string{"Sat", "sun"}, Timezone: "Europe/Berlin"}},
+		{name: "missing start", cfg: ScheduleConfig{End: "06:00"}, expectedErr: "invalid start: time of day cannot be empty"},
+		{name: "invalid end", cfg: ScheduleConfig{Start: "22:00", End: "25:00"}, expectedErr: `invalid end: "25:00" must be of the form HH:MM`},
+		{name: "invalid day", cfg: ScheduleConfig{Start: "22:00", End: "06:00", Days: []string{"sunday"}}, expectedErr: `invalid day "sunday", must be one of sun, mon, tue, wed, thu, fri, sat`},
+		{name: "invalid timezone", cfg: ScheduleConfig{Start: "22:00", End: "06:00", Timezone: "Mars/Olympus"}, expectedErr: "invalid timezone: unknown time zone Mars/Olympus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestScheduleAllows(t *testing.T) {
+	// 2024-01-06 is a Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	office := &ScheduleConfig{Start: "09:00", End: "17:00", Days: []string{"mon", "tue", "wed", "thu", "fri"}, Timezone: "UTC"}
+	assert.True(t, office.allows(at(8, 9, 0)))
+	assert.False(t, office.allows(at(8, 17, 0)))
+	assert.False(t, office.allows(at(6, 12, 0)))
+
+	// Friday night until Saturday morning belongs to Friday's window
+	overnight := &ScheduleConfig{Start: "22:00", End: "06:00", Days: []string{"fri"}, Timezone: "UTC"}
+	assert.True(t, overnight.allows(at(5, 23, 30)))
+	assert.True(t, overnight.allows(at(6, 5, 59)))
+	assert.False(t, overnight.allows(at(6, 6, 0)))
+	assert.False(t, overnight.allows(at(6, 23, 0)))
+	assert.False(t, overnight.allows(at(5, 3, 0)))
+
+	allDay := &ScheduleConfig{Start: "00:00", End: "00:00", Days: []string{"sat"}, Timezone: "UTC"}
+	assert.True(t, allDay.allows(at(6, 13, 0)))
+	assert.False(t, allDay.allows(at(7, 13, 0)))
+
+	// The window is evaluated in its own time zone
+	tokyo := &ScheduleConfig{Start: "01:00", End: "05:00", Timezone: "Asia/Tokyo"}
+	assert.True(t, tokyo.allows(at(6, 17, 0)))
+	assert.False(t, tokyo.allows(at(6, 1, 0)))
+}
+
+func TestRunClientTestOutsideSchedule(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	target := TargetConfig{
+		Host:     "192.0.2.30",
+		Port:     5201,
+		Protocol: "tcp",
+		Schedule: &ScheduleConfig{Start: "01:00", End: "05:00", Timezone: "UTC"},
+	}
+	noon := time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)
+	scraper.runClientTest(context.Background(), target, pcommon.NewTimestampFromTime(noon))
+
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	rm := metrics.ResourceMetrics().At(0)
+	host, ok := rm.Resource().Attributes().Get("iperf.target.host")
+	require.True(t, ok)
+	assert.Equal(t, "192.0.2.30", host.Str())
+	ms := rm.ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "iperf.test.skipped", ms.At(0).Name())
+	reason, ok := ms.At(0).Gauge().DataPoints().At(0).Attributes().Get("iperf.skip.reason")
+	require.True(t, ok)
+	assert.Equal(t, "outside_schedule", reason.Str())
+}
@@ -5,13 +5,22 @@ package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collect
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	iperf "github.com/BGrewell/go-iperf"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.uber.org/zap"
@@ -19,13 +28,51 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
 )
 
+// truncationTolerance is how much shorter than requested a test may run before it is flagged as truncated
+const truncationTolerance = 500 * time.Millisecond
+
+// errPrecheckFailed marks a test that was not run because the server did not accept a connection
+var errPrecheckFailed = errors.New("iperf3 server is not reachable")
+
 type scraper struct {
 	cfg      *Config
 	logger   *zap.Logger
 	settings receiver.Settings
 	mb       *metadata.MetricsBuilder
-	server   *iperf.Server
+	server   iperfServer
 	mu       sync.Mutex
+
+	// supervisor restarts the server in server mode when it exits
+	supervisor *serverSupervisor
+
+	// clock defaults to the system clock, tests inject a fake
+	clock clock
+
+	// portCursor holds the next offset into each target's port range
+	portCursor map[string]int
+
+	// lastRun holds when the last test to each host started
+	lastRun map[string]time.Time
+
+	// serverLog holds the reports of the tests run against the server in server mode
+	serverLog *serverLog
+
+	// version is the iperf3 version detected at start
+	version string
+
+	// logsConsumer receives one log record per test run when a logs pipeline is configured
+	logsConsumer consumer.Logs
+	logs         plog.Logs
+
+	// rawOutput captures the raw iperf3 output of client tests, nil without debug_output
+	rawOutput *rawOutput
+
+	// reports holds the last report of each client target for the debug endpoint
+	reports map[string]lastReport
+
+	// debugServer serves the last reports, nil without debug_endpoint
+	debugServer   *http.Server
+	debugListener net.Listener
 }
 
 func newScraper(cfg *Config, settings receiver.Settings) *scraper {
@@ -33,34 +80,82 @@ func newScraper(cfg *Config, settings receiver.Settings) *scraper {
 		cfg:      cfg,
 		logger:   settings.Logger,
 		settings: settings,
+		clock:    realClock{},
+		logs:     plog.NewLogs(),
 	}
 }
 
 func (s *scraper) start(ctx context.Context, host component.Host) error {
 	s.mb = metadata.NewMetricsBuilder(s.cfg.MetricsBuilderConfig, s.settings)
 
+	// Metrics from hosts running different iperf3 versions may not be comparable
+	s.version = unknownVersion
+	if output, err := detectVersion(ctx); err != nil {
+		s.logger.Warn("Failed to detect iperf3 version", zap.Error(err))
+	} else if s.version, err = parseVersion(output); err != nil {
+		s.version = unknownVersion
+		s.logger.Warn("Failed to detect iperf3 version", zap.Error(err))
+	}
+
 	// If running in server mode, start the iperf3 server
 	if s.cfg.Mode == "server" {
-		s.server = iperf.NewServer()
-		s.server.SetPort(s.cfg.ServerPort)
-		s.server.SetJSON(true)
+		server := iperf.NewServer()
+		server.SetPort(s.cfg.ServerPort)
+		server.SetJSON(true)
+
+		// The server writes a JSON report per test to its log file, read on every scrape
+		logFile, err := os.CreateTemp("", "iperf3-server-*.json")
+		if err != nil {
+			return fmt.Errorf("failed to create iperf3 server log: %w", err)
+		}
+		logFile.Close()
+		s.serverLog = &serverLog{path: logFile.Name()}
+		server.SetLogFile(logFile.Name())
+
+		if s.cfg.AuthPrivateKeyPath != "" {
+			server.SetRSAPrivateKeyPath(s.cfg.AuthPrivateKeyPath)
+			server.SetAuthorizedUsersPath(s.cfg.AuthAuthorizedUsersPath)
+		}
 
 		s.logger.Info("Starting iperf3 server", zap.Int("port", s.cfg.ServerPort))
-		
-		go func() {
-			if err := s.server.Start(); err != nil {
-				s.logger.Error("Failed to start iperf3 server", zap.Error(err))
-			}
-		}()
-		
+
+		// Restart the server when it exits, unattended servers would otherwise stop reporting
+		s.server = goIperfServer{Server: server, port: s.cfg.ServerPort}
+		s.supervisor = newServerSupervisor(s.server, s.logger, s.clock)
+		s.supervisor.start()
+
 		// Give the server time to start
 		time.Sleep(2 * time.Second)
 	}
 
+	if s.cfg.DebugOutput != "" {
+		output, err := newRawOutput(s.cfg.DebugOutput, s.cfg.DebugOutputMaxBytes, s.logger)
+		if err != nil {
+			return err
+		}
+		s.rawOutput = output
+	}
+
+	if s.cfg.DebugEndpoint != "" {
+		return s.startDebugServer()
+	}
 	return nil
 }
 
 func (s *scraper) shutdown(ctx context.Context) error {
+	if s.debugServer != nil {
+		if err := s.debugServer.Close(); err != nil {
+			s.logger.Warn("Failed to close debug server", zap.Error(err))
+		}
+	}
+	if s.rawOutput != nil {
+		if err := s.rawOutput.close(); err != nil {
+			s.logger.Warn("Failed to close debug output", zap.Error(err))
+		}
+	}
+	if s.supervisor != nil {
+		s.supervisor.stop()
+	}
 	if s.server != nil {
 		s.logger.Info("Stopping iperf3 server")
 		if err := s.server.Stop(); err != nil {
@@ -68,52 +163,376 @@ func (s *scraper) shutdown(ctx context.Context) error {
 			return err
 		}
 	}
+	if s.serverLog != nil {
+		if err := os.Remove(s.serverLog.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Warn("Failed to remove iperf3 server log", zap.Error(err))
+		}
+	}
 	return nil
 }
 
 func (s *scraper) scrape(ctx context.Context) (pmetric.Metrics, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	now := pcommon.NewTimestampFromTime(s.clock.Now())
 
-	now := pcommon.NewTimestampFromTime(time.Now())
-
-	// Server mode: collect server-side metrics if available
+	// Server mode: record the tests clients ran against the server since the last scrape
 	if s.cfg.Mode == "server" {
-		// In server mode, we would collect metrics from the running server
-		// This would require implementing a way to get metrics from the server
-		s.logger.Debug("Server mode metrics collection not fully implemented")
-		return s.mb.Emit(), nil
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.collectServerReports(now)
+		if s.supervisor != nil {
+			s.mb.RecordIperfServerRestartDataPoint(now, s.supervisor.restarts.Load())
+		}
+		s.recordActiveConnections(now)
+		return s.emit(now), nil
+	}
+
+	// Client mode: run tests against configured targets. The lock is only
+	// taken while recording so long-running tests don't block each other.
+	maxConcurrent := s.cfg.MaxConcurrentTests
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
 	}
+	sem := make(chan struct{}, maxConcurrent)
 
-	// Client mode: run tests against configured targets
 	var wg sync.WaitGroup
 	for _, target := range s.cfg.Targets {
 		wg.Add(1)
 		go func(t TargetConfig) {
 			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			s.runClientTest(ctx, t, now)
 		}(target)
 	}
 	wg.Wait()
 
-	return s.mb.Emit(), nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushLogs(ctx)
+	return s.emit(now), nil
+}
+
+// emit records the build info and returns all recorded metrics, must be called with s.mu held
+func (s *scraper) emit(timestamp pcommon.Timestamp) pmetric.Metrics {
+	s.mb.RecordIperfBuildInfoDataPoint(timestamp, 1)
+	rb := s.mb.NewResourceBuilder()
+	rb.SetIperfVersion(s.version)
+	return s.mb.Emit(metadata.WithResource(rb.Emit()))
 }
 
 func (s *scraper) runClientTest(ctx context.Context, target TargetConfig, timestamp pcommon.Timestamp) {
+	// Disruptive tests only run within their window
+	if target.Schedule != nil && !target.Schedule.allows(timestamp.AsTime()) {
+		s.recordSkipped(target, timestamp, "outside_schedule")
+		return
+	}
+
+	// Back-to-back tests to the same host interfere with each other
+	if !s.claimRun(target, timestamp.AsTime()) {
+		s.recordSkipped(target, timestamp, "cooldown")
+		return
+	}
+
+	// iperf3 only implements SCTP on a few platforms, fail loudly elsewhere
+	if target.Protocol == "sctp" && !sctpSupported(runtime.GOOS) {
+		s.recordTestError(target, timestamp, "Failed to run iperf test", "unsupported_protocol",
+			fmt.Errorf("sctp is not supported on %s", runtime.GOOS))
+		return
+	}
+
+	// Make sure the requested local address can actually be bound
+	if target.BindAddress != "" {
+		if err := checkLocalAddress(target.BindAddress); err != nil {
+			s.recordTestError(target, timestamp, "Failed to bind iperf client", "bind_failed", err)
+			return
+		}
+	}
+
+	// Run the samples back to back, a failed sample fails the whole test
+	samples := make([]sample, 0, max(target.Samples, 1))
+	for len(samples) < cap(samples) {
+		report, testDuration, attempt, err := s.runWithRetries(ctx, &target)
+		if err != nil {
+			s.recordTestError(target, timestamp, "Failed to run iperf test", errorReason(err), err)
+			return
+		}
+		samples = append(samples, sample{report: report, duration: testDuration, attempt: attempt})
+	}
+	result, spreads := aggregateSamples(samples, target.Aggregation)
+	report, testDuration := result.report, result.duration
+
+	// The kernel caps socket buffers, a capped auto_window cannot fill the path
+	if target.AutoWindow {
+		s.checkAutoWindow(report, target)
+	}
+
+	// The highest attempt shows whether any sample had to be retried
+	attempt := 0
+	for i := range samples {
+		attempt = max(attempt, samples[i].attempt)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Set resource attributes
+	rb := s.mb.NewResourceBuilder()
+	s.setTargetAttributes(rb, target)
+	rb.SetIperfTestSamples(int64(len(samples)))
+	if len(samples) > 1 {
+		rb.SetIperfTestAggregation(target.Aggregation)
+	}
+	rb.SetIperfTestTermination(target.termination())
+	rb.SetIperfVersion(s.version)
+	if family := resolvedFamily(report, target); family != "" {
+		rb.SetIperfTargetAddressFamily(family)
+	}
+	if bitrate := target.bitrate(); bitrate != "" {
+		rb.SetIperfTestBitrate(bitrate)
+	}
+	if target.Pps > 0 {
+		rb.SetIperfTestPps(int64(target.Pps))
+	}
+	if target.FQRate != "" {
+		rb.SetIperfTestFqRate(target.FQRate)
+	}
+	if target.Tos > 0 {
+		rb.SetIperfTestTos(int64(target.Tos))
+	}
+
+	// Record metrics from the report
+	s.recordMetrics(report, target, timestamp, testDuration)
+	s.mb.RecordIperfTestAttemptsDataPoint(timestamp, int64(attempt), target.Protocol)
+	for _, spread := range spreads {
+		s.mb.RecordIperfBandwidthStddevDataPoint(timestamp, spread.stddev, target.Protocol, spread.direction)
+	}
+	if s.cfg.EmitIntervalMetrics {
+		s.recordIntervalMetrics(report, target, timestamp)
+	}
+	s.appendTestLog(target, timestamp, report, nil)
+	s.storeReport(target, timestamp, report, nil)
+
+	res := rb.Emit()
+	for key, value := range target.Tags {
+		res.Attributes().PutStr(key, value)
+	}
+	s.mb.EmitForResource(metadata.WithResource(res))
+}
+
+// checkAutoWindow warns when the kernel granted a smaller send buffer than auto_window computed,
+// e.g. because net.core.wmem_max is lower. Linux reports twice the requested size, so only a
+// capped buffer falls short of it.
+func (s *scraper) checkAutoWindow(report *iperf.Report, target TargetConfig) {
+	window, err := parseByteSize(target.window())
+	if err != nil || report.Start == nil || report.Start.SndBufActual <= 0 {
+		return
+	}
+	if granted := int64(report.Start.SndBufActual); granted < window {
+		s.logger.Warn("Kernel granted a smaller window than auto_window computed, raise the socket buffer limits to fill the path",
+			zap.String("host", target.Host),
+			zap.Int("port", target.Port),
+			zap.Int64("window", window),
+			zap.Int64("granted", granted))
+	}
+}
+
+// runWithRetries runs a test, retrying transient failures with exponential backoff. It returns the
+// report, its wall-clock duration and the attempt it succeeded on. With a port range the target
+// is updated to the port of the last attempt.
+func (s *scraper) runWithRetries(ctx context.Context, target *TargetConfig) (*iperf.Report, float64, int, error) {
+	var (
+		report       *iperf.Report
+		testDuration float64
+		err          error
+		attempt      int
+	)
+	for attempt = 1; attempt <= target.Retries+1; attempt++ {
+		if attempt > 1 {
+			backoff := target.RetryBackoff * time.Duration(1<<(attempt-2))
+			s.logger.Warn("Retrying iperf test",
+				zap.String("host", target.Host),
+				zap.Int("port", target.Port),
+				zap.Int("attempt", attempt),
+				zap.Duration("backoff", backoff),
+				zap.Error(err))
+			if waitErr := sleepWithContext(ctx, s.clock, backoff); waitErr != nil {
+				err = fmt.Errorf("iperf test aborted: %w", waitErr)
+				break
+			}
+		}
+
+		// Spread tests over the server's port range, retries move on to the next port
+		if target.PortRange != "" {
+			target.Port = s.nextPort(*target)
+		}
+
+		// Don't spend the whole test on connect timeouts when the server is down
+		if target.PrecheckTimeout > 0 {
+			if err = precheck(ctx, *target); err != nil {
+				break
+			}
+		}
+
+		report, testDuration, err = s.runTest(ctx, *target)
+		if err == nil {
+			break
+		}
+	}
+
+	return report, testDuration, attempt, err
+}
+
+// runTest runs a single iperf test attempt and returns its report and wall-clock duration
+func (s *scraper) runTest(ctx context.Context, target TargetConfig) (*iperf.Report, float64, error) {
+	client := newClient(target)
+
+	// Have iperf3 write its output to a file the raw output is captured from
+	var outputPath string
+	if s.rawOutput != nil {
+		if file, err := os.CreateTemp("", "iperf3-client-*.json"); err != nil {
+			s.logger.Warn("Failed to capture iperf3 output", zap.Error(err))
+		} else {
+			file.Close()
+			outputPath = file.Name()
+			defer os.Remove(outputPath)
+			client.SetLogFile(outputPath)
+		}
+	}
+
+	// Abort the test if the scrape is cancelled or it overruns
+	var runCtx context.Context
+	var cancel context.CancelFunc
+	if target.MaxRuntime > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, target.MaxRuntime)
+	} else {
+		runCtx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	startTime := s.clock.Now()
+	err := runWithContext(runCtx, client.Start, client.Stop)
+	testDuration := s.clock.Now().Sub(startTime).Seconds()
+	if err != nil {
+		s.captureOutput(target, startTime, outputPath)
+		return nil, testDuration, err
+	}
+
+	// Get test report
+	report := client.Report()
+	if outputPath != "" {
+		s.captureOutput(target, startTime, outputPath)
+		if report == nil {
+			// iperf3 wrote the report to the log file instead of stdout, read it like the server's
+			if reports, _ := (&serverLog{path: outputPath}).read(); len(reports) > 0 {
+				report = reports[0]
+			}
+		}
+	}
+	if report == nil {
+		return nil, testDuration, errors.New("iperf test returned no report")
+	}
+	if err := reportError(report); err != nil {
+		return nil, testDuration, err
+	}
+	return report, testDuration, nil
+}
+
+// precheck connects to the server's control port, which is TCP for every test protocol
+func precheck(ctx context.Context, target TargetConfig) error {
+	network := "tcp"
+	if target.AddressFamily == "ip4" || target.AddressFamily == "ip6" {
+		network = "tcp" + target.AddressFamily[2:]
+	}
+	address := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+	dialer := net.Dialer{Timeout: target.PrecheckTimeout}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPrecheckFailed, err)
+	}
+	return conn.Close()
+}
+
+// reportError returns the error iperf3 reported in the JSON output. iperf3 exits cleanly
+// for some failures, e.g. "the server is busy running a test", leaving an empty report.
+func reportError(report *iperf.Report) error {
+	if msg := strings.TrimSpace(report.Error); msg != "" {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// newClient creates an iperf client configured for the target
+func newClient(target TargetConfig) *iperf.Client {
 	client := iperf.NewClient(target.Host)
 	client.SetPort(target.Port)
 	client.SetJSON(true)
 	client.SetStreams(target.Streams)
-	client.SetTimeSec(int(target.Duration.Seconds()))
+	switch target.termination() {
+	case "bytes":
+		client.SetBytes(target.Bytes)
+	case "blocks":
+		client.SetBlockCount(target.Blocks)
+	default:
+		client.SetTimeSec(int(target.Duration.Seconds()))
+	}
 	client.SetOmitSec(target.OmitSec)
 	client.SetReverse(target.Reverse)
+	client.SetBidirectional(target.Bidirectional)
+	if target.ConnectTimeout > 0 {
+		client.SetConnectTimeout(int(target.ConnectTimeout.Milliseconds()))
+	}
+
+	// Pin the local address and source port if requested
+	if target.BindAddress != "" {
+		client.SetBind(target.BindAddress)
+	}
+	if target.ClientPort > 0 {
+		client.SetClientPort(target.ClientPort)
+	}
+
+	// Authenticate against servers that require credentials
+	if target.AuthUsername != "" {
+		client.SetUsername(target.AuthUsername)
+		client.SetPassword(string(target.AuthPassword))
+		client.SetRSAPublicKeyPath(target.AuthPublicKeyPath)
+	}
+
+	// Pin dual-stack hosts to a single address family
+	switch target.AddressFamily {
+	case "ip4":
+		client.SetIPv4Only(true)
+	case "ip6":
+		client.SetIPv6Only(true)
+	}
+
+	// Cap the bitrate, optionally sending in bursts, for any protocol
+	if bitrate := target.bitrate(); bitrate != "" {
+		client.SetBandwidth(bitrate)
+	}
+
+	// Validation rejects fq_rate when the client cannot set it
+	if setter, ok := any(client).(fqRateSetter); ok && target.FQRate != "" {
+		setter.SetFQRate(target.FQRate)
+	}
+
+	// Mark packets in both TCP and UDP tests, and in both directions when reversed
+	if target.Tos > 0 {
+		client.SetTOS(target.Tos)
+	}
+
+	// Socket buffer size of TCP and UDP tests, "0" leaves it to the kernel
+	if window := target.window(); target.Protocol != "sctp" {
+		if size, err := parseByteSize(window); err == nil && size > 0 {
+			client.SetWindow(window)
+		}
+	}
 
 	// Set protocol-specific options
 	switch target.Protocol {
 	case "udp":
 		client.SetProto(iperf.PROTO_UDP)
-		if target.Bandwidth != "" {
-			client.SetBandwidth(target.Bandwidth)
+		if target.Length != "" {
+			client.SetLength(target.Length)
 		}
 	case "sctp":
 		client.SetProto(iperf.PROTO_SCTP)
@@ -128,48 +547,252 @@ func (s *scraper) runClientTest(ctx context.Context, target TargetConfig, timest
 		if target.MSS > 0 {
 			client.SetMSS(target.MSS)
 		}
-		if target.Window != "" {
-			client.SetWindow(target.Window) 
-		}
 		if target.Congestion != "" {
 			client.SetCongestionAlgorithm(target.Congestion)
 		}
 	}
 
-	// Run the test
-	startTime := time.Now()
-	err := client.Start()
-	testDuration := time.Since(startTime).Seconds()
+	return client
+}
+
+// sleepWithContext waits for the given duration on the clock unless ctx is done first
+func sleepWithContext(ctx context.Context, clk clock, d time.Duration) error {
+	timer := clk.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runWithContext runs a blocking test, stopping it if ctx is done before it completes
+func runWithContext(ctx context.Context, start func() error, stop func()) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- start()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		stop()
+		return fmt.Errorf("iperf test aborted: %w", ctx.Err())
+	}
+}
+
+// recordTestError logs a failed test and records it as an error metric
+func (s *scraper) recordTestError(target TargetConfig, timestamp pcommon.Timestamp, msg, reason string, err error) {
+	s.logger.Error(msg,
+		zap.String("host", target.Host),
+		zap.Int("port", target.Port),
+		zap.String("reason", reason),
+		zap.Error(err))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mb.RecordIperfTestErrorDataPoint(timestamp, 1, err.Error(), reason)
+	s.appendTestLog(target, timestamp, nil, err)
+	s.storeReport(target, timestamp, nil, err)
+	rb := s.mb.NewResourceBuilder()
+	s.setTargetAttributes(rb, target)
+	rb.SetIperfVersion(s.version)
+	s.mb.EmitForResource(metadata.WithResource(rb.Emit()))
+}
+
+// claimRun records that a test to the target's host starts at now, unless the last
+// one started less than the target's min_interval ago
+func (s *scraper) claimRun(target TargetConfig, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lastRun == nil {
+		s.lastRun = make(map[string]time.Time)
+	}
+	if last, ok := s.lastRun[target.Host]; ok && now.Sub(last) < target.MinInterval {
+		return false
+	}
+	s.lastRun[target.Host] = now
+	return true
+}
+
+// recordSkipped records that a test was not run
+func (s *scraper) recordSkipped(target TargetConfig, timestamp pcommon.Timestamp, reason string) {
+	s.logger.Debug("Skipping iperf test",
+		zap.String("host", target.Host),
+		zap.Int("port", target.Port),
+		zap.String("reason", reason))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mb.RecordIperfTestSkippedDataPoint(timestamp, 1, reason)
+	rb := s.mb.NewResourceBuilder()
+	s.setTargetAttributes(rb, target)
+	rb.SetIperfVersion(s.version)
+	s.mb.EmitForResource(metadata.WithResource(rb.Emit()))
+}
+
+// appendTestLog buffers a log record summarizing a test run, must be called with s.mu held
+func (s *scraper) appendTestLog(target TargetConfig, timestamp pcommon.Timestamp, report *iperf.Report, err error) {
+	if s.logsConsumer == nil {
+		return
+	}
+	if s.logs.ResourceLogs().Len() == 0 {
+		sl := s.logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+		sl.Scope().SetName(metadata.ScopeName)
+	}
+
+	record := s.logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty()
+	record.SetTimestamp(timestamp)
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(s.clock.Now()))
+	attrs := record.Attributes()
+	attrs.PutStr("iperf.target.host", target.Host)
+	attrs.PutInt("iperf.target.port", int64(target.Port))
+	attrs.PutStr("iperf.test.protocol", target.Protocol)
 
 	if err != nil {
-		s.logger.Error("Failed to run iperf test", 
-			zap.String("host", target.Host),
-			zap.Int("port", target.Port),
-			zap.Error(err))
-		
-		// Record error metric
-		s.mb.RecordIperfTestErrorDataPoint(timestamp, 1, err.Error())
+		record.SetSeverityNumber(plog.SeverityNumberError)
+		record.SetSeverityText("ERROR")
+		record.Body().SetStr(fmt.Sprintf("iperf test to %s:%d failed", target.Host, target.Port))
+		attrs.PutStr("error.message", err.Error())
 		return
 	}
 
-	// Get test report
-	report := client.Report()
-	if report == nil {
-		s.logger.Error("Failed to get iperf report",
-			zap.String("host", target.Host),
-			zap.Int("port", target.Port))
+	record.SetSeverityNumber(plog.SeverityNumberInfo)
+	record.SetSeverityText("INFO")
+	record.Body().SetStr(fmt.Sprintf("iperf test to %s:%d completed", target.Host, target.Port))
+	if report.End == nil {
 		return
 	}
 
-	// Set resource attributes
-	rb := s.mb.NewResourceBuilder()
-	rb.SetIperfTargetHost(target.Host)
-	rb.SetIperfTargetPort(int64(target.Port))
-	resource := rb.Emit()
-	s.mb.ResourceOption(resource)
+	// Prefer what actually arrived over what was sent
+	if sum := report.End.SumReceived; sum != nil {
+		attrs.PutDouble("iperf.bandwidth", sum.BitsPerSecond)
+	} else if sum := report.End.SumSent; sum != nil {
+		attrs.PutDouble("iperf.bandwidth", sum.BitsPerSecond)
+	}
+	if report.End.SumSent != nil && (target.Protocol == "tcp" || target.Protocol == "sctp") {
+		attrs.PutInt("iperf.retransmits", int64(report.End.SumSent.Retransmits))
+	}
+}
 
-	// Record metrics from the report
-	s.recordMetrics(report, target, timestamp, testDuration)
+// flushLogs sends the buffered test logs to the logs consumer, must be called with s.mu held
+func (s *scraper) flushLogs(ctx context.Context) {
+	if s.logsConsumer == nil || s.logs.LogRecordCount() == 0 {
+		return
+	}
+
+	logs := s.logs
+	s.logs = plog.NewLogs()
+	if err := s.logsConsumer.ConsumeLogs(ctx, logs); err != nil {
+		s.logger.Error("Failed to consume iperf test logs", zap.Error(err))
+	}
+}
+
+// errorReason classifies a failed test run so errors can be aggregated without parsing messages
+func errorReason(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errPrecheckFailed):
+		return "precheck_failed"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "aborted"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "connect_timeout"
+	case strings.Contains(strings.ToLower(err.Error()), "unable to connect") &&
+		strings.Contains(strings.ToLower(err.Error()), "timed out"):
+		// iperf3 reports an expired --connect-timeout as a plain error message
+		return "connect_timeout"
+	default:
+		return "test_failed"
+	}
+}
+
+// nextPort returns the next port of the target's port range in round-robin order
+func (s *scraper) nextPort(target TargetConfig) int {
+	// Validation has already checked the range
+	low, high, _ := parsePortRange(target.PortRange)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.portCursor == nil {
+		s.portCursor = make(map[string]int)
+	}
+	key := target.Host + "/" + target.PortRange
+	offset := s.portCursor[key]
+	s.portCursor[key] = (offset + 1) % (high - low + 1)
+	return low + offset
+}
+
+// sctpSupported reports whether iperf3 supports SCTP tests on the given OS
+func sctpSupported(goos string) bool {
+	switch goos {
+	case "linux", "freebsd", "solaris":
+		return true
+	default:
+		return false
+	}
+}
+
+// effectiveLength returns the UDP datagram length used by the test, preferring the value reported by iperf3
+func effectiveLength(report *iperf.Report, target TargetConfig) int64 {
+	if report.Start != nil && report.Start.TestStart != nil && report.Start.TestStart.BlkSize > 0 {
+		return int64(report.Start.TestStart.BlkSize)
+	}
+
+	// Fall back to the configured value, which validation has already checked
+	length, _ := parseByteSize(target.Length)
+	return length
+}
+
+// congestionAlgorithm returns the congestion control algorithm reported by the sender, or the configured one
+func congestionAlgorithm(report *iperf.Report, target TargetConfig) string {
+	if report.End != nil && report.End.SenderTCPCongestion != "" {
+		return report.End.SenderTCPCongestion
+	}
+	if target.Congestion != "" {
+		return target.Congestion
+	}
+	return "unknown"
+}
+
+// resolvedFamily returns the address family the test actually connected over, "ip4" or "ip6"
+func resolvedFamily(report *iperf.Report, target TargetConfig) string {
+	if report.Start != nil && len(report.Start.Connected) > 0 {
+		if ip := net.ParseIP(report.Start.Connected[0].RemoteHost); ip != nil {
+			if ip.To4() != nil {
+				return "ip4"
+			}
+			return "ip6"
+		}
+	}
+
+	// Fall back to the pinned family, auto cannot be resolved without a connection
+	if target.AddressFamily == "auto" {
+		return ""
+	}
+	return target.AddressFamily
+}
+
+// checkLocalAddress verifies that addr is assigned to one of the host's interfaces
+func checkLocalAddress(addr string) error {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return fmt.Errorf("invalid bind address %q", addr)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return fmt.Errorf("failed to list interface addresses: %w", err)
+	}
+	for _, a := range addrs {
+		if ipNet, ok := a.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("bind address %s is not assigned to any local interface", addr)
 }
 
 func (s *scraper) recordMetrics(report *iperf.Report, target TargetConfig, timestamp pcommon.Timestamp, testDuration float64) {
@@ -183,39 +806,34 @@ func (s *scraper) recordMetrics(report *iperf.Report, target TargetConfig, times
 	// Record test duration
 	s.mb.RecordIperfTestDurationDataPoint(timestamp, testDuration, target.Protocol)
 
-	// Process sum stats
-	if report.End.SumSent != nil {
-		// Bandwidth (bits per second)
-		s.mb.RecordIperfBandwidthDataPoint(timestamp,
-			report.End.SumSent.BitsPerSecond,
-			target.Protocol,
-			"send",
-			int64(target.Streams))
-
-		// Transfer (bytes)
-		s.mb.RecordIperfTransferDataPoint(timestamp,
-			int64(report.End.SumSent.Bytes),
-			target.Protocol,
-			"send")
+	// Flag tests that ended early, e.g. because the server disconnected. The
+	// measured time excludes connection setup, so prefer it over wall-clock time.
+	// Fixed-volume tests have no requested duration.
+	if target.termination() == "time" {
+		measured := testDuration
+		if report.End.SumSent != nil && report.End.SumSent.Seconds > 0 {
+			measured = report.End.SumSent.Seconds
+		}
+		truncated := int64(0)
+		if measured+truncationTolerance.Seconds() < target.Duration.Seconds() {
+			truncated = 1
+		}
+		s.mb.RecordIperfTestRequestedDurationDataPoint(timestamp, target.Duration.Seconds(), target.Protocol)
+		s.mb.RecordIperfTestTruncatedDataPoint(timestamp, truncated, target.Protocol)
 	}
 
-	if report.End.SumReceived != nil {
-		// Bandwidth (bits per second) 
-		s.mb.RecordIperfBandwidthDataPoint(timestamp,
-			report.End.SumReceived.BitsPerSecond,
-			target.Protocol,
-			"receive",
-			int64(target.Streams))
+	// Process sum stats
+	s.recordSum(report.End.SumSent, target, timestamp, "send")
+	s.recordSum(report.End.SumReceived, target, timestamp, "receive")
 
-		// Transfer (bytes)
-		s.mb.RecordIperfTransferDataPoint(timestamp,
-			int64(report.End.SumReceived.Bytes),
-			target.Protocol,
-			"receive")
+	// Bidirectional tests report the server-to-client streams separately
+	if target.Bidirectional {
+		s.recordSum(report.End.SumSentBidirReverse, target, timestamp, "reverse_send")
+		s.recordSum(report.End.SumReceivedBidirReverse, target, timestamp, "reverse_receive")
 	}
 
-	// TCP-specific metrics
-	if target.Protocol == "tcp" && report.End.SumSent != nil {
+	// TCP and SCTP metrics
+	if (target.Protocol == "tcp" || target.Protocol == "sctp") && report.End.SumSent != nil {
 		// Retransmits
 		if report.End.SumSent.Retransmits > 0 {
 			s.mb.RecordIperfRetransmitsDataPoint(timestamp,
@@ -224,15 +842,35 @@ func (s *scraper) recordMetrics(report *iperf.Report, target TargetConfig, times
 		}
 	}
 
-	// UDP-specific metrics
+	// TCP round trip times and congestion windows, reported per stream by the sender
+	if target.Protocol == "tcp" {
+		s.recordRTT(report.End.Streams, target, timestamp)
+
+		var maxCwnd int
+		for _, stream := range report.End.Streams {
+			if stream != nil && stream.Sender != nil && stream.Sender.MaxSndCwnd > maxCwnd {
+				maxCwnd = stream.Sender.MaxSndCwnd
+			}
+		}
+		if maxCwnd > 0 {
+			s.mb.RecordIperfTCPSndCwndDataPoint(timestamp,
+				int64(maxCwnd),
+				congestionAlgorithm(report, target))
+		}
+	}
+
+	// UDP-specific metrics, tagged with the datagram length they depend on
 	if target.Protocol == "udp" {
 		if report.End.SumReceived != nil {
+			length := effectiveLength(report, target)
+
 			// Jitter
 			if report.End.SumReceived.Jitter > 0 {
 				s.mb.RecordIperfJitterDataPoint(timestamp,
 					report.End.SumReceived.Jitter,
 					target.Protocol,
-					"receive")
+					"receive",
+					length)
 			}
 
 			// Packet loss
@@ -240,24 +878,178 @@ func (s *scraper) recordMetrics(report *iperf.Report, target TargetConfig, times
 				s.mb.RecordIperfPacketLossDataPoint(timestamp,
 					report.End.SumReceived.LostPercent,
 					target.Protocol,
-					"receive")
+					"receive",
+					length)
 			}
 		}
 	}
 
-	// CPU utilization (if available)
-	if report.End.CPUUtilizationPercent != nil {
-		if report.End.CPUUtilizationPercent.HostTotal > 0 {
-			s.mb.RecordIperfCpuUtilizationDataPoint(timestamp,
-				report.End.CPUUtilizationPercent.HostTotal,
+	// Socket buffers actually granted by the kernel, which may be smaller than requested
+	if report.Start != nil {
+		if report.Start.SndBufActual > 0 {
+			s.mb.RecordIperfWindowDataPoint(timestamp,
+				int64(report.Start.SndBufActual),
 				target.Protocol,
 				"send")
 		}
-		if report.End.CPUUtilizationPercent.RemoteTotal > 0 {
-			s.mb.RecordIperfCpuUtilizationDataPoint(timestamp,
-				report.End.CPUUtilizationPercent.RemoteTotal,
+		if report.Start.RcvBufActual > 0 {
+			s.mb.RecordIperfWindowDataPoint(timestamp,
+				int64(report.Start.RcvBufActual),
 				target.Protocol,
 				"receive")
 		}
 	}
-}
\ No newline at end of file
+
+	// CPU utilization (if available), host is this side of the test and remote the other one
+	if cpu := report.End.CPUUtilizationPercent; cpu != nil {
+		hostDirection, remoteDirection := "send", "receive"
+		if !s.localSender(target) {
+			hostDirection, remoteDirection = remoteDirection, hostDirection
+		}
+		s.recordCPU(cpu.HostTotal, cpu.HostUser, cpu.HostSystem, target, timestamp, hostDirection)
+		s.recordCPU(cpu.RemoteTotal, cpu.RemoteUser, cpu.RemoteSystem, target, timestamp, remoteDirection)
+	}
+}
+
+// recordRTT records the mean, min and max round trip time and the RTT variation across all streams
+func (s *scraper) recordRTT(streams []*iperf.StreamEnd, target TargetConfig, timestamp pcommon.Timestamp) {
+	var sumMean, sumVar float64
+	minRTT, maxRTT, n := 0, 0, 0
+	for _, stream := range streams {
+		if stream == nil || stream.Sender == nil || stream.Sender.MeanRtt <= 0 {
+			continue
+		}
+		sender := stream.Sender
+		sumMean += float64(sender.MeanRtt)
+		sumVar += float64(sender.Rttvar)
+		if n == 0 || sender.MinRtt < minRTT {
+			minRTT = sender.MinRtt
+		}
+		if sender.MaxRtt > maxRTT {
+			maxRTT = sender.MaxRtt
+		}
+		n++
+	}
+	if n == 0 {
+		return
+	}
+
+	// iperf3 reports RTTs in microseconds
+	s.mb.RecordIperfRttDataPoint(timestamp, sumMean/float64(n)/1000, target.Protocol, "mean")
+	s.mb.RecordIperfRttDataPoint(timestamp, float64(minRTT)/1000, target.Protocol, "min")
+	s.mb.RecordIperfRttDataPoint(timestamp, float64(maxRTT)/1000, target.Protocol, "max")
+	s.mb.RecordIperfRttVarianceDataPoint(timestamp, sumVar/float64(n)/1000, target.Protocol)
+}
+
+// recordCPU records total CPU utilization and its user/system split for one side of the test
+func (s *scraper) recordCPU(total, user, system float64, target TargetConfig, timestamp pcommon.Timestamp, direction string) {
+	if total > 0 {
+		s.mb.RecordIperfCPUUtilizationDataPoint(timestamp, total, target.Protocol, direction)
+	}
+	if user > 0 {
+		s.mb.RecordIperfCPUModeUtilizationDataPoint(timestamp, user, target.Protocol, direction, "user")
+	}
+	if system > 0 {
+		s.mb.RecordIperfCPUModeUtilizationDataPoint(timestamp, system, target.Protocol, direction, "system")
+	}
+}
+
+// recordSum records the bandwidth and transfer metrics of a summary section
+func (s *scraper) recordSum(sum *iperf.Sum, target TargetConfig, timestamp pcommon.Timestamp, direction string) {
+	if sum == nil {
+		return
+	}
+
+	// Bandwidth (bits per second)
+	s.mb.RecordIperfBandwidthDataPoint(timestamp,
+		sum.BitsPerSecond,
+		target.Protocol,
+		direction,
+		int64(target.Streams))
+
+	// Transfer (bytes)
+	s.mb.RecordIperfTransferDataPoint(timestamp,
+		int64(sum.Bytes),
+		target.Protocol,
+		direction)
+}
+
+// recordIntervalMetrics records a data point per reporting interval of the test
+func (s *scraper) recordIntervalMetrics(report *iperf.Report, target TargetConfig, timestamp pcommon.Timestamp) {
+	// Interval samples are reported from this side's point of view
+	direction := "send"
+	if !s.localSender(target) {
+		direction = "receive"
+	}
+
+	for i, interval := range report.Intervals {
+		if interval == nil {
+			continue
+		}
+		index := int64(i)
+
+		s.recordIntervalSum(interval.Sum, target, timestamp, direction, index)
+		if target.Bidirectional {
+			s.recordIntervalSum(interval.SumBidirReverse, target, timestamp, "reverse_receive", index)
+		}
+
+		if target.Protocol == "tcp" {
+			var maxCwnd int
+			for _, stream := range interval.Streams {
+				if stream != nil && stream.SndCwnd > maxCwnd {
+					maxCwnd = stream.SndCwnd
+				}
+			}
+			if maxCwnd > 0 {
+				s.mb.RecordIperfIntervalTCPSndCwndDataPoint(timestamp,
+					int64(maxCwnd),
+					congestionAlgorithm(report, target),
+					index)
+			}
+		}
+	}
+}
+
+// recordIntervalSum records the metrics of a single interval summary section
+func (s *scraper) recordIntervalSum(sum *iperf.Sum, target TargetConfig, timestamp pcommon.Timestamp, direction string, index int64) {
+	if sum == nil {
+		return
+	}
+
+	s.mb.RecordIperfIntervalBandwidthDataPoint(timestamp,
+		sum.BitsPerSecond,
+		target.Protocol,
+		direction,
+		index)
+
+	switch target.Protocol {
+	case "tcp", "sctp":
+		s.mb.RecordIperfIntervalRetransmitsDataPoint(timestamp,
+			int64(sum.Retransmits),
+			target.Protocol,
+			index)
+	case "udp":
+		s.mb.RecordIperfIntervalJitterDataPoint(timestamp,
+			sum.Jitter,
+			target.Protocol,
+			direction,
+			index)
+		s.mb.RecordIperfIntervalPacketLossDataPoint(timestamp,
+			sum.LostPercent,
+			target.Protocol,
+			direction,
+			index)
+	}
+}
+
+// setTargetAttributes sets the server of the test as iperf.target.* or, with the semconv
+// attribute convention, as server.* resource attributes
+func (s *scraper) setTargetAttributes(rb *metadata.ResourceBuilder, target TargetConfig) {
+	if s.cfg.AttributeConvention == "semconv" {
+		rb.SetServerAddress(target.Host)
+		rb.SetServerPort(int64(target.Port))
+		return
+	}
+	rb.SetIperfTargetHost(target.Host)
+	rb.SetIperfTargetPort(int64(target.Port))
+}
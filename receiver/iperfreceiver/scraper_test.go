@@ -5,6 +5,12 @@ package iperfreceiver
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,9 +18,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 	"go.opentelemetry.io/collector/scraper/scraperhelper"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
 )
@@ -118,8 +130,12 @@ func TestRecordMetrics(t *testing.T) {
 				LostPercent:   0.1,
 			},
 			CPUUtilizationPercent: &iperf.CPUUtilizationPercent{
-				HostTotal:   25.5,
-				RemoteTotal: 30.2,
+				HostTotal:    25.5,
+				HostUser:     5.5,
+				HostSystem:   20.0,
+				RemoteTotal:  30.2,
+				RemoteUser:   2.2,
+				RemoteSystem: 28.0,
 			},
 		},
 	}
@@ -146,6 +162,169 @@ func TestRecordMetrics(t *testing.T) {
 	assert.Greater(t, metrics.DataPointCount(), 0)
 }
 
+func TestRecordMetricsCPUMode(t *testing.T) {
+	mbc := metadata.DefaultMetricsBuilderConfig()
+	mbc.Metrics.IperfCPUModeUtilization.Enabled = true
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: mbc,
+		Mode:                 "client",
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// A receiver bound by kernel time
+	report := &iperf.Report{
+		End: &iperf.End{
+			CPUUtilizationPercent: &iperf.CPUUtilizationPercent{
+				HostTotal:    25.5,
+				HostUser:     5.5,
+				HostSystem:   20.0,
+				RemoteTotal:  30.2,
+				RemoteUser:   2.2,
+				RemoteSystem: 28.0,
+			},
+		},
+	}
+
+	target := TargetConfig{
+		Host:     "localhost",
+		Port:     5201,
+		Protocol: "tcp",
+		Streams:  1,
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// Record metrics
+	scraper.recordMetrics(report, target, timestamp, 10.0)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify the user/system split is recorded for both sides
+	split := map[string]float64{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != "iperf.cpu.mode_utilization" {
+			continue
+		}
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			direction, ok := dps.At(j).Attributes().Get("iperf.test.direction")
+			require.True(t, ok)
+			mode, ok := dps.At(j).Attributes().Get("iperf.cpu.mode")
+			require.True(t, ok)
+			split[direction.Str()+"/"+mode.Str()] = dps.At(j).DoubleValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"send/user":      5.5,
+		"send/system":    20.0,
+		"receive/user":   2.2,
+		"receive/system": 28.0,
+	}, split)
+}
+
+func TestRecordMetricsTruncated(t *testing.T) {
+	tests := []struct {
+		name         string
+		seconds      float64
+		testDuration float64
+		expected     int64
+	}{
+		{name: "clean run", seconds: 10.0, testDuration: 10.3, expected: 0},
+		{name: "server disconnect", seconds: 2.0, testDuration: 2.2, expected: 1},
+		{name: "wall-clock fallback", seconds: 0, testDuration: 3.0, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+				MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+				Mode:                 "client",
+			}
+
+			settings := receivertest.NewNopSettings()
+			scraper := newScraper(cfg, settings)
+			require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+			report := &iperf.Report{
+				End: &iperf.End{
+					SumSent: &iperf.Sum{
+						Seconds:       tt.seconds,
+						Bytes:         1024000,
+						BitsPerSecond: 8192000,
+					},
+				},
+			}
+
+			target := TargetConfig{
+				Host:     "localhost",
+				Port:     5201,
+				Protocol: "tcp",
+				Duration: 10 * time.Second,
+				Streams:  1,
+			}
+
+			scraper.recordMetrics(report, target, pcommon.NewTimestampFromTime(time.Now()), tt.testDuration)
+
+			ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			found := 0
+			for i := 0; i < ms.Len(); i++ {
+				m := ms.At(i)
+				switch m.Name() {
+				case "iperf.test.requested_duration":
+					found++
+					assert.InDelta(t, 10.0, m.Gauge().DataPoints().At(0).DoubleValue(), 0.001)
+				case "iperf.test.truncated":
+					found++
+					assert.Equal(t, tt.expected, m.Gauge().DataPoints().At(0).IntValue())
+				}
+			}
+			assert.Equal(t, 2, found)
+		})
+	}
+}
+
+func TestRecordMetricsFixedVolume(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	// A 100M transfer that completed in 2 seconds is not truncated
+	report := &iperf.Report{
+		End: &iperf.End{
+			SumSent: &iperf.Sum{Seconds: 2.0, Bytes: 104857600, BitsPerSecond: 419430400},
+		},
+	}
+	target := TargetConfig{Host: "localhost", Port: 5201, Protocol: "tcp", Bytes: "100M", Streams: 1}
+	scraper.recordMetrics(report, target, pcommon.NewTimestampFromTime(time.Now()), 2.1)
+
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	names := make([]string, 0, ms.Len())
+	for i := 0; i < ms.Len(); i++ {
+		names = append(names, ms.At(i).Name())
+	}
+	assert.Contains(t, names, "iperf.test.duration")
+	assert.NotContains(t, names, "iperf.test.requested_duration")
+	assert.NotContains(t, names, "iperf.test.truncated")
+}
+
 func TestRecordMetricsWithNilReport(t *testing.T) {
 	cfg := &Config{
 		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
@@ -226,6 +405,7 @@ func TestRecordMetricsUDP(t *testing.T) {
 		Protocol:  "udp",
 		Streams:   1,
 		Bandwidth: "10M",
+		Length:    "1400",
 	}
 
 	timestamp := pcommon.NewTimestampFromTime(time.Now())
@@ -241,4 +421,777 @@ func TestRecordMetricsUDP(t *testing.T) {
 	// Verify UDP-specific metrics were recorded
 	assert.Greater(t, metrics.MetricCount(), 0)
 	assert.Greater(t, metrics.DataPointCount(), 0)
-}
\ No newline at end of file
+
+	// Verify the UDP metrics carry the datagram length
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	found := 0
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != "iperf.jitter" && m.Name() != "iperf.packet_loss" {
+			continue
+		}
+		length, ok := m.Gauge().DataPoints().At(0).Attributes().Get("iperf.test.length")
+		require.True(t, ok)
+		assert.EqualValues(t, 1400, length.Int())
+		found++
+	}
+	assert.Equal(t, 2, found)
+}
+func TestRecordMetricsBidirectional(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// Create a bidirectional report
+	report := &iperf.Report{
+		End: &iperf.End{
+			SumSent: &iperf.Sum{
+				Bytes:         1024000,
+				BitsPerSecond: 8192000,
+			},
+			SumReceived: &iperf.Sum{
+				Bytes:         1024000,
+				BitsPerSecond: 8192000,
+			},
+			SumSentBidirReverse: &iperf.Sum{
+				Bytes:         512000,
+				BitsPerSecond: 4096000,
+			},
+			SumReceivedBidirReverse: &iperf.Sum{
+				Bytes:         512000,
+				BitsPerSecond: 4096000,
+			},
+		},
+	}
+
+	target := TargetConfig{
+		Host:          "localhost",
+		Port:          5201,
+		Protocol:      "tcp",
+		Streams:       1,
+		Bidirectional: true,
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+	testDuration := 10.0
+
+	// Record metrics
+	scraper.recordMetrics(report, target, timestamp, testDuration)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify bandwidth was recorded for both directions of both streams
+	directions := map[string]bool{}
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() != "iperf.bandwidth" {
+			continue
+		}
+		dps := ms.At(i).Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			direction, ok := dps.At(j).Attributes().Get("iperf.test.direction")
+			require.True(t, ok)
+			directions[direction.Str()] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		"send":            true,
+		"receive":         true,
+		"reverse_send":    true,
+		"reverse_receive": true,
+	}, directions)
+}
+
+func TestRecordMetricsWindow(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// A 4M window was requested but the kernel capped the socket buffers
+	data, err := os.ReadFile(filepath.Join("testdata", "window_report.json"))
+	require.NoError(t, err)
+	report := &iperf.Report{}
+	require.NoError(t, json.Unmarshal(data, report))
+
+	target := TargetConfig{
+		Host:     "localhost",
+		Port:     5201,
+		Protocol: "tcp",
+		Streams:  1,
+		Window:   "4M",
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// Record metrics
+	scraper.recordMetrics(report, target, timestamp, 10.0)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify the effective window is reported per direction
+	windows := map[string]int64{}
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != "iperf.window" {
+			continue
+		}
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			direction, ok := dps.At(j).Attributes().Get("iperf.test.direction")
+			require.True(t, ok)
+			windows[direction.Str()] = dps.At(j).IntValue()
+		}
+	}
+	assert.Equal(t, map[string]int64{"send": 425984, "receive": 131072}, windows)
+}
+
+func TestCheckAutoWindow(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	settings := receivertest.NewNopSettings()
+	settings.Logger = zap.New(core)
+	scraper := newScraper(&Config{Mode: "client"}, settings)
+
+	// The kernel granted 416K, short of the computed window
+	data, err := os.ReadFile(filepath.Join("testdata", "window_report.json"))
+	require.NoError(t, err)
+	report := &iperf.Report{}
+	require.NoError(t, json.Unmarshal(data, report))
+
+	target := TargetConfig{Host: "localhost", Port: 5201, Protocol: "tcp", AutoWindow: true, ExpectedBandwidth: "1G", ExpectedRTT: 20 * time.Millisecond}
+	scraper.checkAutoWindow(report, target)
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(4883*1024), entries[0].ContextMap()["window"])
+	assert.Equal(t, int64(425984), entries[0].ContextMap()["granted"])
+
+	// A window the kernel granted in full is not reported
+	target.ExpectedBandwidth = "40M"
+	scraper.checkAutoWindow(report, target)
+	assert.Zero(t, logs.Len())
+}
+
+func TestRecordMetricsRTT(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// Two TCP streams with different round trip times
+	data, err := os.ReadFile(filepath.Join("testdata", "rtt_report.json"))
+	require.NoError(t, err)
+	report := &iperf.Report{}
+	require.NoError(t, json.Unmarshal(data, report))
+
+	target := TargetConfig{
+		Host:     "localhost",
+		Port:     5201,
+		Protocol: "tcp",
+		Streams:  2,
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// Record metrics
+	scraper.recordMetrics(report, target, timestamp, 10.0)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify RTT statistics are aggregated across streams and converted to milliseconds
+	rtt := map[string]float64{}
+	foundVariance := false
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		switch m.Name() {
+		case "iperf.rtt":
+			dps := m.Gauge().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				statistic, ok := dps.At(j).Attributes().Get("iperf.rtt.statistic")
+				require.True(t, ok)
+				rtt[statistic.Str()] = dps.At(j).DoubleValue()
+			}
+		case "iperf.rtt.variance":
+			foundVariance = true
+			assert.InDelta(t, 2.0, m.Gauge().DataPoints().At(0).DoubleValue(), 0.001)
+		}
+	}
+	assert.Equal(t, map[string]float64{"mean": 5.0, "min": 2.0, "max": 20.0}, rtt)
+	assert.True(t, foundVariance, "rtt variance metric not found")
+
+	// UDP tests carry no RTT data
+	target.Protocol = "udp"
+	scraper.recordMetrics(report, target, timestamp, 10.0)
+	ms = scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		assert.NotEqual(t, "iperf.rtt", ms.At(i).Name())
+	}
+}
+
+func TestRecordMetricsSndCwnd(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+		EmitIntervalMetrics:  true,
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// Two TCP streams over two intervals, sent with bbr
+	data, err := os.ReadFile(filepath.Join("testdata", "cwnd_report.json"))
+	require.NoError(t, err)
+	report := &iperf.Report{}
+	require.NoError(t, json.Unmarshal(data, report))
+
+	target := TargetConfig{
+		Host:       "localhost",
+		Port:       5201,
+		Protocol:   "tcp",
+		Streams:    2,
+		Congestion: "cubic",
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// Record metrics
+	scraper.recordMetrics(report, target, timestamp, 2.0)
+	scraper.recordIntervalMetrics(report, target, timestamp)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify the largest window is recorded, tagged with the algorithm the sender reported
+	var endCwnd int64
+	var intervalCwnd []int64
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		switch m.Name() {
+		case "iperf.tcp.snd_cwnd", "iperf.interval.tcp.snd_cwnd":
+		default:
+			continue
+		}
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			congestion, ok := dps.At(j).Attributes().Get("iperf.tcp.congestion")
+			require.True(t, ok)
+			assert.Equal(t, "bbr", congestion.Str())
+			if m.Name() == "iperf.tcp.snd_cwnd" {
+				endCwnd = dps.At(j).IntValue()
+			} else {
+				intervalCwnd = append(intervalCwnd, dps.At(j).IntValue())
+			}
+		}
+	}
+	assert.Equal(t, int64(786432), endCwnd)
+	assert.Equal(t, []int64{262144, 786432}, intervalCwnd)
+}
+
+func TestCongestionAlgorithm(t *testing.T) {
+	reported := &iperf.Report{End: &iperf.End{SenderTCPCongestion: "bbr"}}
+	assert.Equal(t, "bbr", congestionAlgorithm(reported, TargetConfig{Congestion: "cubic"}))
+	assert.Equal(t, "cubic", congestionAlgorithm(&iperf.Report{}, TargetConfig{Congestion: "cubic"}))
+	assert.Equal(t, "unknown", congestionAlgorithm(&iperf.Report{}, TargetConfig{}))
+}
+
+func TestRecordIntervalMetrics(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+		EmitIntervalMetrics:  true,
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// Create a report with three intervals
+	report := &iperf.Report{
+		Intervals: []*iperf.Interval{
+			{Sum: &iperf.Sum{BitsPerSecond: 1000000, Retransmits: 0}},
+			{Sum: &iperf.Sum{BitsPerSecond: 5000000, Retransmits: 2}},
+			{Sum: &iperf.Sum{BitsPerSecond: 9000000, Retransmits: 1}},
+		},
+	}
+
+	target := TargetConfig{
+		Host:     "localhost",
+		Port:     5201,
+		Protocol: "tcp",
+		Streams:  1,
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// Record metrics
+	scraper.recordIntervalMetrics(report, target, timestamp)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify one data point per interval, keyed by interval index
+	found := false
+	for i := 0; i < ms.Len(); i++ {
+		if ms.At(i).Name() != "iperf.interval.bandwidth" {
+			continue
+		}
+		found = true
+		dps := ms.At(i).Gauge().DataPoints()
+		require.Equal(t, 3, dps.Len())
+		for j := 0; j < dps.Len(); j++ {
+			index, ok := dps.At(j).Attributes().Get("iperf.interval.index")
+			require.True(t, ok)
+			assert.Equal(t, int64(j), index.Int())
+		}
+		assert.InDelta(t, 9000000, dps.At(2).DoubleValue(), 0.01)
+	}
+	assert.True(t, found, "interval bandwidth metric not found")
+}
+
+func TestCheckLocalAddress(t *testing.T) {
+	assert.NoError(t, checkLocalAddress("127.0.0.1"))
+	assert.ErrorContains(t, checkLocalAddress("192.0.2.123"), "not assigned to any local interface")
+	assert.ErrorContains(t, checkLocalAddress("not-an-ip"), "invalid bind address")
+}
+
+func TestResolvedFamily(t *testing.T) {
+	connected := func(host string) *iperf.Report {
+		return &iperf.Report{Start: &iperf.Start{Connected: []iperf.Connected{{RemoteHost: host}}}}
+	}
+
+	assert.Equal(t, "ip4", resolvedFamily(connected("192.0.2.1"), TargetConfig{AddressFamily: "auto"}))
+	assert.Equal(t, "ip4", resolvedFamily(connected("::ffff:192.0.2.1"), TargetConfig{AddressFamily: "auto"}))
+	assert.Equal(t, "ip6", resolvedFamily(connected("2001:db8::1"), TargetConfig{AddressFamily: "auto"}))
+	assert.Equal(t, "ip6", resolvedFamily(&iperf.Report{}, TargetConfig{AddressFamily: "ip6"}))
+	assert.Equal(t, "", resolvedFamily(&iperf.Report{}, TargetConfig{AddressFamily: "auto"}))
+}
+
+func TestRecordMetricsSCTP(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+
+	// Initialize metrics builder
+	ctx := context.Background()
+	host := componenttest.NewNopHost()
+	err := scraper.start(ctx, host)
+	require.NoError(t, err)
+
+	// Create an SCTP report
+	report := &iperf.Report{
+		End: &iperf.End{
+			SumSent: &iperf.Sum{
+				Bytes:         1024000,
+				BitsPerSecond: 8192000,
+				Retransmits:   3,
+			},
+			SumReceived: &iperf.Sum{
+				Bytes:         1024000,
+				BitsPerSecond: 8192000,
+			},
+		},
+	}
+
+	target := TargetConfig{
+		Host:     "localhost",
+		Port:     5201,
+		Protocol: "sctp",
+		Streams:  2,
+	}
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// Record metrics
+	scraper.recordMetrics(report, target, timestamp, 10.0)
+
+	// Get metrics
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	// Verify retransmits are recorded and every data point is tagged as sctp
+	foundRetransmits := false
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() == "iperf.retransmits" {
+			foundRetransmits = true
+			assert.Equal(t, int64(3), m.Sum().DataPoints().At(0).IntValue())
+		}
+
+		var dps pmetric.NumberDataPointSlice
+		switch m.Type() {
+		case pmetric.MetricTypeGauge:
+			dps = m.Gauge().DataPoints()
+		case pmetric.MetricTypeSum:
+			dps = m.Sum().DataPoints()
+		}
+		for j := 0; j < dps.Len(); j++ {
+			protocol, ok := dps.At(j).Attributes().Get("iperf.test.protocol")
+			require.True(t, ok)
+			assert.Equal(t, "sctp", protocol.Str())
+		}
+	}
+	assert.True(t, foundRetransmits, "retransmits metric not found")
+}
+
+func TestNextPort(t *testing.T) {
+	scraper := newScraper(&Config{}, receivertest.NewNopSettings())
+	a := TargetConfig{Host: "a.example.com", PortRange: "5201-5203"}
+	b := TargetConfig{Host: "b.example.com", PortRange: "5201-5203"}
+
+	// Each target rotates through its range independently and wraps around
+	assert.Equal(t, 5201, scraper.nextPort(a))
+	assert.Equal(t, 5202, scraper.nextPort(a))
+	assert.Equal(t, 5201, scraper.nextPort(b))
+	assert.Equal(t, 5203, scraper.nextPort(a))
+	assert.Equal(t, 5201, scraper.nextPort(a))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClaimRunCooldown(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	target := TargetConfig{Host: "192.0.2.40", Port: 5201, Protocol: "tcp", MinInterval: time.Minute}
+	start := time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)
+	require.True(t, scraper.claimRun(target, start))
+
+	// A scrape 30s later is within the cooldown and does not run the test
+	scraper.runClientTest(context.Background(), target, pcommon.NewTimestampFromTime(start.Add(30*time.Second)))
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "iperf.test.skipped", ms.At(0).Name())
+	reason, ok := ms.At(0).Gauge().DataPoints().At(0).Attributes().Get("iperf.skip.reason")
+	require.True(t, ok)
+	assert.Equal(t, "cooldown", reason.Str())
+
+	// Another target on the same host shares the cooldown, other hosts do not
+	assert.False(t, scraper.claimRun(TargetConfig{Host: "192.0.2.40", Port: 5202, MinInterval: time.Minute}, start.Add(45*time.Second)))
+	assert.True(t, scraper.claimRun(TargetConfig{Host: "192.0.2.41", Port: 5201, MinInterval: time.Minute}, start.Add(45*time.Second)))
+
+	// Skipped tests do not extend the cooldown
+	assert.True(t, scraper.claimRun(target, start.Add(time.Minute)))
+	assert.False(t, scraper.claimRun(target, start.Add(90*time.Second)))
+
+	// Without min_interval tests always run
+	assert.True(t, scraper.claimRun(TargetConfig{Host: "192.0.2.40"}, start.Add(91*time.Second)))
+}
+
+func TestErrorReason(t *testing.T) {
+	assert.Equal(t, "connect_timeout", errorReason(timeoutError{}))
+	assert.Equal(t, "connect_timeout", errorReason(errors.New("unable to connect to server: Connection timed out")))
+	assert.Equal(t, "aborted", errorReason(fmt.Errorf("iperf test aborted: %w", context.DeadlineExceeded)))
+	assert.Equal(t, "precheck_failed", errorReason(fmt.Errorf("%w: %w", errPrecheckFailed, timeoutError{})))
+	assert.Equal(t, "test_failed", errorReason(errors.New("the server is busy running a test")))
+}
+
+func TestReportError(t *testing.T) {
+	// iperf3 exits cleanly when the server is busy, only the report carries the error
+	data, err := os.ReadFile(filepath.Join("testdata", "busy_report.json"))
+	require.NoError(t, err)
+	report := &iperf.Report{}
+	require.NoError(t, json.Unmarshal(data, report))
+	assert.EqualError(t, reportError(report), "the server is busy running a test. try again later")
+
+	data, err = os.ReadFile(filepath.Join("testdata", "rtt_report.json"))
+	require.NoError(t, err)
+	report = &iperf.Report{}
+	require.NoError(t, json.Unmarshal(data, report))
+	assert.NoError(t, reportError(report))
+}
+
+func TestRecordReportError(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	target := TargetConfig{Host: "192.0.2.20", Port: 5201, Protocol: "tcp", Streams: 1}
+	err := reportError(&iperf.Report{Error: "the server is busy running a test. try again later"})
+	scraper.recordTestError(target, pcommon.NewTimestampFromTime(time.Now()), "Failed to run iperf test", errorReason(err), err)
+
+	// Only the error is recorded, no zero bandwidth
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "iperf.test.error", ms.At(0).Name())
+	message, ok := ms.At(0).Sum().DataPoints().At(0).Attributes().Get("error.message")
+	require.True(t, ok)
+	assert.Equal(t, "the server is busy running a test. try again later", message.Str())
+}
+
+func TestRecordTestErrorResource(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// The first target fails, the second one passes and emits its own resource like runClientTest
+	failing := TargetConfig{Host: "192.0.2.20", Port: 5201, Protocol: "tcp", Streams: 1}
+	scraper.recordTestError(failing, timestamp, "Failed to run iperf test", "connection_refused", errors.New("connection refused"))
+
+	passing := TargetConfig{Host: "192.0.2.21", Port: 5201, Protocol: "tcp", Streams: 1}
+	report := &iperf.Report{End: &iperf.End{SumSent: &iperf.Sum{Bytes: 1024, BitsPerSecond: 8192}}}
+	scraper.mu.Lock()
+	scraper.recordMetrics(report, passing, timestamp, 10)
+	rb := scraper.mb.NewResourceBuilder()
+	scraper.setTargetAttributes(rb, passing)
+	scraper.mb.EmitForResource(metadata.WithResource(rb.Emit()))
+	metrics := scraper.emit(timestamp)
+	scraper.mu.Unlock()
+
+	// The error is on the failing host's resource only
+	errorHosts := map[string]bool{}
+	rms := metrics.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		host, ok := rms.At(i).Resource().Attributes().Get("iperf.target.host")
+		if !ok {
+			continue
+		}
+		ms := rms.At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			if ms.At(j).Name() == "iperf.test.error" {
+				errorHosts[host.Str()] = true
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{"192.0.2.20": true}, errorHosts)
+}
+
+func TestRunClientTestPrecheckFailed(t *testing.T) {
+	// Nothing listens on the port once the listener is closed
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+	}
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	target := TargetConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		Protocol:        "tcp",
+		Streams:         1,
+		Duration:        10 * time.Second,
+		MaxRuntime:      40 * time.Second,
+		PrecheckTimeout: time.Second,
+		Retries:         2,
+		RetryBackoff:    time.Minute,
+	}
+	start := time.Now()
+	scraper.runClientTest(context.Background(), target, pcommon.NewTimestampFromTime(start))
+
+	// The test and its retries are skipped
+	assert.Less(t, time.Since(start), 5*time.Second)
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "iperf.test.error", ms.At(0).Name())
+	reason, ok := ms.At(0).Sum().DataPoints().At(0).Attributes().Get("error.reason")
+	require.True(t, ok)
+	assert.Equal(t, "precheck_failed", reason.Str())
+}
+
+func TestScrapeLogs(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "client",
+		MaxConcurrentTests:   1,
+	}
+
+	sink := new(consumertest.LogsSink)
+	scraper := newScraper(cfg, receivertest.NewNopSettings())
+	scraper.logsConsumer = sink
+	require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+	target := TargetConfig{
+		Host:     "localhost",
+		Port:     5201,
+		Protocol: "tcp",
+		Streams:  1,
+	}
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+	// One successful run and one failed run
+	report := &iperf.Report{
+		End: &iperf.End{
+			SumSent:     &iperf.Sum{BitsPerSecond: 9000000, Retransmits: 4},
+			SumReceived: &iperf.Sum{BitsPerSecond: 8000000},
+		},
+	}
+	scraper.mu.Lock()
+	scraper.appendTestLog(target, timestamp, report, nil)
+	scraper.mu.Unlock()
+	scraper.recordTestError(target, timestamp, "Failed to run iperf test", "test_failed", errors.New("connection refused"))
+
+	// Buffered records are flushed at the end of the scrape
+	_, err := scraper.scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(sink.AllLogs()))
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+
+	ok := records.At(0)
+	assert.Equal(t, plog.SeverityNumberInfo, ok.SeverityNumber())
+	bandwidth, found := ok.Attributes().Get("iperf.bandwidth")
+	require.True(t, found)
+	assert.InDelta(t, 8000000, bandwidth.Double(), 0.01)
+	retransmits, found := ok.Attributes().Get("iperf.retransmits")
+	require.True(t, found)
+	assert.Equal(t, int64(4), retransmits.Int())
+
+	failed := records.At(1)
+	assert.Equal(t, plog.SeverityNumberError, failed.SeverityNumber())
+	msg, found := failed.Attributes().Get("error.message")
+	require.True(t, found)
+	assert.Equal(t, "connection refused", msg.Str())
+
+	// Nothing is sent when no test ran
+	_, err = scraper.scrape(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(sink.AllLogs()))
+}
+
+func TestSCTPSupported(t *testing.T) {
+	assert.True(t, sctpSupported("linux"))
+	assert.True(t, sctpSupported("freebsd"))
+	assert.False(t, sctpSupported("darwin"))
+	assert.False(t, sctpSupported("windows"))
+}
+
+func TestRunWithContext(t *testing.T) {
+	t.Run("completes", func(t *testing.T) {
+		err := runWithContext(context.Background(), func() error { return nil }, func() {
+			t.Fatal("stop should not be called for a completed test")
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("cancelled mid-run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		start := func() error {
+			// Simulate a hung iperf server
+			<-stopped
+			return nil
+		}
+		stop := func() {
+			close(stopped)
+		}
+
+		time.AfterFunc(50*time.Millisecond, cancel)
+		err := runWithContext(ctx, start, stop)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "iperf test aborted")
+
+		select {
+		case <-stopped:
+		default:
+			t.Fatal("stop was not called")
+		}
+	})
+
+	t.Run("max runtime exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		stopped := make(chan struct{})
+
+		err := runWithContext(ctx, func() error {
+			<-stopped
+			return nil
+		}, func() {
+			close(stopped)
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestSleepWithContext(t *testing.T) {
+	assert.NoError(t, sleepWithContext(context.Background(), realClock{}, time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, sleepWithContext(ctx, realClock{}, time.Hour), context.Canceled)
+}
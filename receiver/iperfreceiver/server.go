@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	iperf "github.com/BGrewell/go-iperf"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+// serverLog reads the JSON reports the iperf3 server appends to its log file, one document per test
+type serverLog struct {
+	path   string
+	offset int64
+}
+
+// read returns the reports completed since the last read. A report still being
+// written is left for the next read. Once every report is read the log is
+// truncated, the server appends to it for as long as it runs.
+func (l *serverLog) read() ([]*iperf.Report, error) {
+	file, err := os.OpenFile(l.path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open iperf3 server log: %w", err)
+	}
+	defer file.Close()
+	if _, err = file.Seek(l.offset, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read iperf3 server log: %w", err)
+	}
+
+	var reports []*iperf.Report
+	decoder := json.NewDecoder(file)
+	for {
+		report := &iperf.Report{}
+		err = decoder.Decode(report)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			// Skip whatever was written so far, the next test starts a new document
+			end, _ := file.Seek(0, io.SeekEnd)
+			l.offset = end
+			l.truncate(file)
+			return reports, fmt.Errorf("failed to parse iperf3 server log: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	l.offset += decoder.InputOffset()
+
+	// Only whitespace left after the last report, nothing is being written
+	rest, _ := io.ReadAll(decoder.Buffered())
+	if len(bytes.TrimSpace(rest)) == 0 {
+		l.offset += int64(len(rest))
+		l.truncate(file)
+	}
+	return reports, nil
+}
+
+// truncate empties the log once everything up to offset was read. The server opens
+// the log in append mode and keeps writing at the new end.
+func (l *serverLog) truncate(file *os.File) {
+	if info, err := file.Stat(); err != nil || info.Size() != l.offset {
+		return
+	}
+	if err := file.Truncate(0); err == nil {
+		l.offset = 0
+	}
+}
+
+// serverTarget describes a test received by the server with the settings the client requested
+func serverTarget(report *iperf.Report, serverPort int) TargetConfig {
+	target := TargetConfig{
+		Port:     serverPort,
+		Protocol: "tcp",
+		Streams:  1,
+	}
+	if report.Start == nil {
+		return target
+	}
+	if len(report.Start.Connected) > 0 {
+		target.Host = report.Start.Connected[0].RemoteHost
+	}
+	if start := report.Start.TestStart; start != nil {
+		if start.Protocol != "" {
+			target.Protocol = strings.ToLower(start.Protocol)
+		}
+		if start.NumStreams > 0 {
+			target.Streams = start.NumStreams
+		}
+		target.Duration = time.Duration(start.Duration) * time.Second
+		if start.Bytes > 0 {
+			target.Bytes = fmt.Sprint(start.Bytes)
+		} else if start.Blocks > 0 {
+			target.Blocks = fmt.Sprint(start.Blocks)
+		}
+		target.OmitSec = start.Omit
+		target.Reverse = start.Reverse != 0
+	}
+	return target
+}
+
+// recordServerReport records a test received by the server, must be called with s.mu held
+func (s *scraper) recordServerReport(report *iperf.Report, timestamp pcommon.Timestamp) {
+	target := serverTarget(report, s.cfg.ServerPort)
+
+	// The server does not time the test itself, use what iperf3 measured
+	var testDuration float64
+	if report.End != nil && report.End.SumReceived != nil {
+		testDuration = report.End.SumReceived.Seconds
+	}
+
+	rb := s.mb.NewResourceBuilder()
+	s.setTargetAttributes(rb, target)
+	rb.SetIperfTestTermination(target.termination())
+	rb.SetIperfVersion(s.version)
+	if family := resolvedFamily(report, target); family != "" {
+		rb.SetIperfTargetAddressFamily(family)
+	}
+
+	s.recordMetrics(report, target, timestamp, testDuration)
+	if s.cfg.EmitIntervalMetrics {
+		s.recordIntervalMetrics(report, target, timestamp)
+	}
+	s.mb.EmitForResource(metadata.WithResource(rb.Emit()))
+}
+
+// collectServerReports records the tests the server completed since the last scrape, must be called with s.mu held
+func (s *scraper) collectServerReports(timestamp pcommon.Timestamp) {
+	if s.serverLog == nil {
+		return
+	}
+	reports, err := s.serverLog.read()
+	if err != nil {
+		s.logger.Warn("Failed to read iperf3 server reports", zap.Error(err))
+	}
+	for _, report := range reports {
+		if report.End == nil {
+			continue
+		}
+		s.recordServerReport(report, timestamp)
+	}
+}
+
+// recordActiveConnections records how many connections clients hold to the server, must be called with s.mu held
+func (s *scraper) recordActiveConnections(timestamp pcommon.Timestamp) {
+	if s.server == nil {
+		return
+	}
+	connections, err := s.server.activeConnections()
+	if err != nil {
+		s.logger.Debug("Failed to count iperf3 server connections", zap.Error(err))
+		return
+	}
+	s.mb.RecordIperfServerActiveConnectionsDataPoint(timestamp, int64(connections))
+}
+
+// localSender reports whether this side of the test sent the data. Clients send unless
+// the test is reversed, servers only send in reversed tests.
+func (s *scraper) localSender(target TargetConfig) bool {
+	return target.Reverse == (s.cfg.Mode == "server")
+}
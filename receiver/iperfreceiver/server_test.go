@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+func TestServerLogRead(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "server_reverse_report.json"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "server.json")
+
+	// Two complete reports and one still being written
+	half := len(data) / 2
+	require.NoError(t, os.WriteFile(path, append(append(append([]byte{}, data...), data...), data[:half]...), 0o600))
+	log := &serverLog{path: path}
+	reports, err := log.read()
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "192.0.2.50", reports[1].Start.Connected[0].RemoteHost)
+
+	// The partial report is read once it is complete
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.Write(data[half:])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	reports, err = log.read()
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+
+	// The log is emptied once every report was read, later reports are still read
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size())
+	reports, err = log.read()
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+
+	file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	reports, err = log.read()
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+}
+
+func TestCollectServerReportsReverse(t *testing.T) {
+	mbc := metadata.DefaultMetricsBuilderConfig()
+	mbc.Metrics.IperfCPUUtilization.Enabled = true
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: mbc,
+		Mode:                 "server",
+		ServerPort:           5201,
+		EmitIntervalMetrics:  true,
+	}
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+	scraper.mb = metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, settings)
+
+	// A client ran a reversed test against the server, so the server sent the data
+	data, err := os.ReadFile(filepath.Join("testdata", "server_reverse_report.json"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "server.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	scraper.serverLog = &serverLog{path: path}
+
+	scraper.collectServerReports(pcommon.NewTimestampFromTime(time.Now()))
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	rm := metrics.ResourceMetrics().At(0)
+
+	host, ok := rm.Resource().Attributes().Get("iperf.target.host")
+	require.True(t, ok)
+	assert.Equal(t, "192.0.2.50", host.Str())
+	port, ok := rm.Resource().Attributes().Get("iperf.target.port")
+	require.True(t, ok)
+	assert.Equal(t, int64(5201), port.Int())
+
+	values := map[string]float64{}
+	ms := rm.ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		switch m.Name() {
+		case "iperf.bandwidth", "iperf.cpu.utilization", "iperf.interval.bandwidth":
+		case "iperf.test.requested_duration":
+			values[m.Name()] = m.Gauge().DataPoints().At(0).DoubleValue()
+			continue
+		default:
+			continue
+		}
+		dps := m.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			direction, ok := dps.At(j).Attributes().Get("iperf.test.direction")
+			require.True(t, ok)
+			values[m.Name()+"/"+direction.Str()] = dps.At(j).DoubleValue()
+		}
+	}
+	assert.Equal(t, map[string]float64{
+		"iperf.bandwidth/send":          838860800,
+		"iperf.bandwidth/receive":       830472192,
+		"iperf.cpu.utilization/send":    35.0,
+		"iperf.cpu.utilization/receive": 12.0,
+		"iperf.interval.bandwidth/send": 838860800,
+		"iperf.test.requested_duration": 10,
+	}, values)
+}
+
+func TestCollectServerReportsSemconv(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "server",
+		ServerPort:           5201,
+		AttributeConvention:  "semconv",
+	}
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+	scraper.mb = metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, settings)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "server_reverse_report.json"))
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "server.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	scraper.serverLog = &serverLog{path: path}
+
+	scraper.collectServerReports(pcommon.NewTimestampFromTime(time.Now()))
+	metrics := scraper.mb.Emit()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	attrs := metrics.ResourceMetrics().At(0).Resource().Attributes()
+
+	// The server is described by the server.* attributes instead of iperf.target.*
+	address, ok := attrs.Get("server.address")
+	require.True(t, ok)
+	assert.Equal(t, "192.0.2.50", address.Str())
+	port, ok := attrs.Get("server.port")
+	require.True(t, ok)
+	assert.Equal(t, int64(5201), port.Int())
+	_, ok = attrs.Get("iperf.target.host")
+	assert.False(t, ok)
+	_, ok = attrs.Get("iperf.target.port")
+	assert.False(t, ok)
+}
+
+func TestLocalSender(t *testing.T) {
+	tests := []struct {
+		mode    string
+		reverse bool
+		want    bool
+	}{
+		{mode: "client", reverse: false, want: true},
+		{mode: "client", reverse: true, want: false},
+		{mode: "server", reverse: false, want: false},
+		{mode: "server", reverse: true, want: true},
+	}
+	for _, tt := range tests {
+		s := newScraper(&Config{Mode: tt.mode}, receivertest.NewNopSettings())
+		assert.Equal(t, tt.want, s.localSender(TargetConfig{Reverse: tt.reverse}), "%s reverse=%t", tt.mode, tt.reverse)
+	}
+}
+
+func TestRecordActiveConnections(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "server",
+		ServerPort:           5201,
+	}
+	settings := receivertest.NewNopSettings()
+	scraper := newScraper(cfg, settings)
+	scraper.mb = metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, settings)
+
+	// Two clients testing with a control and a data connection each
+	server := &fakeServer{connections: 4}
+	scraper.server = server
+	scraper.recordActiveConnections(pcommon.NewTimestampFromTime(time.Now()))
+	ms := scraper.mb.Emit().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, ms.Len())
+	assert.Equal(t, "iperf.server.active_connections", ms.At(0).Name())
+	assert.Equal(t, int64(4), ms.At(0).Gauge().DataPoints().At(0).IntValue())
+
+	// Nothing is recorded when the connections cannot be counted
+	server.connErr = errors.New("TCP sockets are not available on this platform")
+	scraper.recordActiveConnections(pcommon.NewTimestampFromTime(time.Now()))
+	assert.Zero(t, scraper.mb.Emit().MetricCount())
+}
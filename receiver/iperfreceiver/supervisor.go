@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	iperf "github.com/BGrewell/go-iperf"
+	"go.uber.org/zap"
+)
+
+const (
+	// superviseInterval is how often the supervisor checks the iperf3 server is still running
+	superviseInterval = time.Second
+	// restartBackoff is the wait before the first restart, doubled while the server keeps exiting
+	restartBackoff    = time.Second
+	maxRestartBackoff = time.Minute
+)
+
+// iperfServer is the iperf3 server run in server mode, replaced in tests
+type iperfServer interface {
+	Start() error
+	Stop() error
+	// running reports whether the iperf3 process is alive
+	running() bool
+	// activeConnections returns the number of connections of clients currently testing
+	activeConnections() (int, error)
+}
+
+// goIperfServer adapts the go-iperf server, whose Start returns once the iperf3 process is launched
+type goIperfServer struct {
+	*iperf.Server
+	port int
+}
+
+func (s goIperfServer) running() bool {
+	return s.Running
+}
+
+func (s goIperfServer) activeConnections() (int, error) {
+	return countConnections(s.port)
+}
+
+// serverSupervisor keeps the iperf3 server running, restarting it with backoff when it exits
+type serverSupervisor struct {
+	server     iperfServer
+	logger     *zap.Logger
+	clock      clock
+	interval   time.Duration
+	backoff    time.Duration
+	maxBackoff time.Duration
+
+	// restarts counts the restarts since the receiver started
+	restarts atomic.Int64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newServerSupervisor(server iperfServer, logger *zap.Logger, clk clock) *serverSupervisor {
+	return &serverSupervisor{
+		server:     server,
+		logger:     logger,
+		clock:      clk,
+		interval:   superviseInterval,
+		backoff:    restartBackoff,
+		maxBackoff: maxRestartBackoff,
+	}
+}
+
+// start starts the server and supervises it until stop
+func (v *serverSupervisor) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.done = make(chan struct{})
+	go v.run(ctx)
+}
+
+// stop ends the supervision and waits for a pending restart to finish, the server is left running
+func (v *serverSupervisor) stop() {
+	if v.cancel == nil {
+		return
+	}
+	v.cancel()
+	<-v.done
+}
+
+func (v *serverSupervisor) run(ctx context.Context) {
+	defer close(v.done)
+	if err := v.server.Start(); err != nil {
+		v.logger.Error("Failed to start iperf3 server", zap.Error(err))
+	}
+
+	backoff := v.backoff
+	ticker := v.clock.NewTicker(v.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+
+		// The server survived since the last restart
+		if v.server.running() {
+			backoff = v.backoff
+			continue
+		}
+
+		v.logger.Warn("iperf3 server exited, restarting", zap.Duration("backoff", backoff))
+		if err := sleepWithContext(ctx, v.clock, backoff); err != nil {
+			return
+		}
+		v.restarts.Add(1)
+		if err := v.server.Start(); err != nil {
+			v.logger.Error("Failed to restart iperf3 server", zap.Error(err))
+		}
+		backoff = min(2*backoff, v.maxBackoff)
+	}
+}
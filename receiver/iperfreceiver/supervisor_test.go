@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/scraper/scraperhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+// fakeServer is an iperf3 server that exits whenever the test kills it
+type fakeServer struct {
+	mu          sync.Mutex
+	alive       bool
+	starts      int
+	stops       int
+	startErr    error
+	connections int
+	connErr     error
+}
+
+func (s *fakeServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.starts++
+	if s.startErr != nil {
+		return s.startErr
+	}
+	s.alive = true
+	return nil
+}
+
+func (s *fakeServer) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stops++
+	s.alive = false
+	return nil
+}
+
+func (s *fakeServer) running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.alive
+}
+
+func (s *fakeServer) activeConnections() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections, s.connErr
+}
+
+// kill simulates the iperf3 process exiting, e.g. after a crash
+func (s *fakeServer) kill() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alive = false
+}
+
+func (s *fakeServer) startCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.starts
+}
+
+func newTestSupervisor(server iperfServer) *serverSupervisor {
+	v := newServerSupervisor(server, zap.NewNop(), realClock{})
+	v.interval = 10 * time.Millisecond
+	v.backoff = 10 * time.Millisecond
+	v.maxBackoff = 40 * time.Millisecond
+	return v
+}
+
+func TestSupervisorRestartsExitedServer(t *testing.T) {
+	server := &fakeServer{}
+	v := newTestSupervisor(server)
+	v.start()
+	require.Eventually(t, server.running, 5*time.Second, 5*time.Millisecond)
+	assert.Zero(t, v.restarts.Load())
+
+	server.kill()
+	require.Eventually(t, func() bool { return v.restarts.Load() == 1 && server.running() }, 5*time.Second, 5*time.Millisecond)
+	server.kill()
+	require.Eventually(t, func() bool { return v.restarts.Load() == 2 && server.running() }, 5*time.Second, 5*time.Millisecond)
+
+	// Stopping the supervisor leaves the server to shutdown
+	v.stop()
+	assert.Equal(t, 3, server.startCount())
+	assert.True(t, server.running())
+}
+
+func TestSupervisorBacksOffFailedRestarts(t *testing.T) {
+	server := &fakeServer{startErr: errors.New("address already in use")}
+	v := newTestSupervisor(server)
+	v.interval = time.Millisecond
+	v.start()
+
+	// Every check finds the server dead, the waits grow to the maximum backoff
+	time.Sleep(200 * time.Millisecond)
+	v.stop()
+	starts := server.startCount()
+	assert.Greater(t, starts, 2)
+	assert.Less(t, starts, 12)
+	assert.Equal(t, int64(starts-1), v.restarts.Load())
+}
+
+func TestSupervisorOnClock(t *testing.T) {
+	server := &fakeServer{}
+	clk := &fakeClock{now: time.Date(2024, 1, 6, 12, 0, 0, 0, time.UTC)}
+	v := newServerSupervisor(server, zap.NewNop(), clk)
+	v.start()
+	defer v.stop()
+	require.Eventually(t, func() bool { return server.running() && clk.tickerCount() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	// The exited server is noticed on the next check and restarted after the backoff, both on the clock
+	server.kill()
+	clk.Advance(superviseInterval)
+	require.Eventually(t, func() bool { return clk.tickerCount() == 2 }, 5*time.Second, 5*time.Millisecond)
+	assert.Zero(t, v.restarts.Load())
+	clk.Advance(restartBackoff)
+	require.Eventually(t, func() bool { return v.restarts.Load() == 1 && server.running() }, 5*time.Second, 5*time.Millisecond)
+}
+
+func TestSupervisorStopDuringBackoff(t *testing.T) {
+	server := &fakeServer{startErr: errors.New("address already in use")}
+	v := newTestSupervisor(server)
+	v.backoff = time.Hour
+	v.start()
+	require.Eventually(t, func() bool { return server.startCount() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		v.stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop during the restart backoff")
+	}
+	assert.Zero(t, v.restarts.Load())
+}
+
+func TestScrapeServerRestarts(t *testing.T) {
+	cfg := &Config{
+		ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+		MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+		Mode:                 "server",
+		ServerPort:           5201,
+	}
+	s := newScraper(cfg, receivertest.NewNopSettings())
+	s.mb = metadata.NewMetricsBuilder(cfg.MetricsBuilderConfig, receivertest.NewNopSettings())
+	server := &fakeServer{}
+	s.server = server
+	s.supervisor = newTestSupervisor(server)
+	s.supervisor.start()
+	require.Eventually(t, server.running, 5*time.Second, 5*time.Millisecond)
+
+	server.kill()
+	require.Eventually(t, func() bool { return s.supervisor.restarts.Load() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	var restarts int64 = -1
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		ms := rms.At(i).ScopeMetrics().At(0).Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			if ms.At(j).Name() == "iperf.server.restart" {
+				restarts = ms.At(j).Sum().DataPoints().At(0).IntValue()
+			}
+		}
+	}
+	assert.Equal(t, int64(1), restarts)
+
+	require.NoError(t, s.shutdown(context.Background()))
+	assert.False(t, server.running())
+	assert.Equal(t, 1, server.stops)
+}
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver"
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// unknownVersion is reported when the iperf3 version cannot be detected
+const unknownVersion = "unknown"
+
+// detectVersion returns the output of iperf3 --version, replaced in tests
+var detectVersion = func(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "iperf3", "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run iperf3 --version: %w", err)
+	}
+	return string(out), nil
+}
+
+// parseVersion extracts the version from iperf3 --version output such as
+// "iperf 3.16 (cJSON 1.7.15)"
+func parseVersion(output string) (string, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "iperf" {
+		return "", fmt.Errorf("unexpected iperf3 version output %q", line)
+	}
+	return fields[1], nil
+}
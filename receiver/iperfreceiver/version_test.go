@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package iperfreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.opentelemetry.io/collector/scraper/scraperhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iperfreceiver/internal/metadata"
+)
+
+func TestParseVersion(t *testing.T) {
+	version, err := parseVersion("iperf 3.16 (cJSON 1.7.15)\nLinux host 6.1.0 #1 SMP x86_64\nOptional features available: CPU affinity setting\n")
+	require.NoError(t, err)
+	assert.Equal(t, "3.16", version)
+
+	_, err = parseVersion("command not found")
+	assert.EqualError(t, err, `unexpected iperf3 version output "command not found"`)
+}
+
+func TestScraperVersion(t *testing.T) {
+	original := detectVersion
+	t.Cleanup(func() { detectVersion = original })
+
+	tests := []struct {
+		name   string
+		detect func(context.Context) (string, error)
+		want   string
+	}{
+		{
+			name:   "detected",
+			detect: func(context.Context) (string, error) { return "iperf 3.16 (cJSON 1.7.15)\n", nil },
+			want:   "3.16",
+		},
+		{
+			name:   "not installed",
+			detect: func(context.Context) (string, error) { return "", errors.New("executable file not found") },
+			want:   "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detectVersion = tt.detect
+			cfg := &Config{
+				ControllerConfig:     scraperhelper.NewDefaultControllerConfig(),
+				MetricsBuilderConfig: metadata.DefaultMetricsBuilderConfig(),
+				Mode:                 "client",
+			}
+			scraper := newScraper(cfg, receivertest.NewNopSettings())
+			require.NoError(t, scraper.start(context.Background(), componenttest.NewNopHost()))
+
+			scraper.mu.Lock()
+			metrics := scraper.emit(pcommon.NewTimestampFromTime(time.Now()))
+			scraper.mu.Unlock()
+
+			require.Equal(t, 1, metrics.ResourceMetrics().Len())
+			rm := metrics.ResourceMetrics().At(0)
+			version, ok := rm.Resource().Attributes().Get("iperf.version")
+			require.True(t, ok)
+			assert.Equal(t, tt.want, version.Str())
+			ms := rm.ScopeMetrics().At(0).Metrics()
+			require.Equal(t, 1, ms.Len())
+			assert.Equal(t, "iperf.build.info", ms.At(0).Name())
+			assert.Equal(t, int64(1), ms.At(0).Gauge().DataPoints().At(0).IntValue())
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import "sync"
+
+// finalHop is the enrichment of the hop a target answered from in its last trace
+type finalHop struct {
+	ip      string
+	city    string
+	country string
+	asn     string
+}
+
+// anycastDetector flags targets whose address is answered from different locations or autonomous
+// systems across cycles, a hint that it is anycast. It is a heuristic: a geolocation database
+// update or a renumbered network trips it too, and an anycast address always answered by the
+// same point of presence does not.
+type anycastDetector struct {
+	mu    sync.Mutex
+	final map[string]finalHop
+}
+
+func newAnycastDetector() *anycastDetector {
+	return &anycastDetector{final: make(map[string]finalHop)}
+}
+
+// check compares the final hop of a trace that reached the target with the one of the previous
+// trace of the target and remembers it for the next one
+func (d *anycastDetector) check(target string, result *Result) bool {
+	if !result.TargetReached || len(result.Hops) == 0 {
+		return false
+	}
+	hop := result.Hops[len(result.Hops)-1]
+	current := finalHop{ip: hop.IP, city: hop.City, country: hop.Country, asn: hop.ASN}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	previous, ok := d.final[target]
+	d.final[target] = current
+	if !ok || previous.ip != current.ip {
+		// A new address is a DNS change, not anycast
+		return false
+	}
+	return enrichmentChanged(previous.city, current.city) || enrichmentChanged(previous.country, current.country) || enrichmentChanged(previous.asn, current.asn)
+}
+
+// enrichmentChanged reports whether an enrichment field has another value, a missing lookup is not a change
+func enrichmentChanged(previous, current string) bool {
+	return previous != "" && current != "" && previous != current
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func reachedFrom(ip, city, asn string) *Result {
+	return &Result{
+		TargetReached: true,
+		ResolvedIP:    ip,
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1"},
+			{TTL: 2, IP: ip, City: city, Country: "United States", ASN: asn},
+		},
+	}
+}
+
+func TestAnycastDetector(t *testing.T) {
+	d := newAnycastDetector()
+
+	// The first trace has nothing to compare with
+	assert.False(t, d.check("example.com", reachedFrom("203.0.113.1", "Ashburn", "AS13335")))
+	assert.False(t, d.check("example.com", reachedFrom("203.0.113.1", "Ashburn", "AS13335")))
+
+	// The same address answered from another location
+	assert.True(t, d.check("example.com", reachedFrom("203.0.113.1", "Dallas", "AS13335")))
+	assert.True(t, d.check("example.com", reachedFrom("203.0.113.1", "Dallas", "AS64500")))
+
+	// A failed lookup is not a change
+	assert.False(t, d.check("example.com", reachedFrom("203.0.113.1", "", "")))
+	assert.False(t, d.check("example.com", reachedFrom("203.0.113.1", "Dallas", "AS64500")))
+
+	// Another address is a DNS change
+	assert.False(t, d.check("example.com", reachedFrom("203.0.113.2", "Ashburn", "AS13335")))
+
+	// Traces that did not reach the target are skipped and the targets are compared separately
+	assert.False(t, d.check("example.com", &Result{Hops: []Hop{{TTL: 1, IP: "192.168.1.1", City: "Paris"}}}))
+	assert.False(t, d.check("example.org", reachedFrom("203.0.113.2", "Paris", "AS64501")))
+	assert.False(t, d.check("example.com", reachedFrom("203.0.113.2", "Ashburn", "AS13335")))
+}
+
+func TestAnycastSuspectedAttribute(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	result := reachedFrom("203.0.113.1", "Dallas", "AS13335")
+	result.AnycastSuspected = true
+
+	root := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	attr, ok := root.Attributes().Get("ztrace.target.anycast_suspected")
+	require.True(t, ok)
+	assert.True(t, attr.Bool())
+
+	// Without reaching the target there is no final hop to compare
+	result.TargetReached = false
+	root = r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	_, ok = root.Attributes().Get("ztrace.target.anycast_suspected")
+	assert.False(t, ok)
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import "time"
+
+// clock is the source of time for scheduling traces and timestamping telemetry, tests replace it with a fake
+type clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) ticker
+	After(d time.Duration) <-chan time.Time
+}
+
+// ticker delivers ticks on C until stopped
+type ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// realClock reads the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// now reads the receiver clock, falling back to the system clock before Start
+func (r *ztraceReceiver) now() time.Time {
+	if r.clock == nil {
+		return time.Now()
+	}
+	return r.clock.Now()
+}
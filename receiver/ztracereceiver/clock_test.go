@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// fakeClock only moves when advanced, firing the tickers that are due
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// After is a ticker that fires once
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, c: make(chan time.Time, 1), period: d, next: c.now.Add(d), once: true}
+	c.tickers = append(c.tickers, t)
+	return t.c
+}
+
+// Advance moves the clock forward, like time.Ticker a ticker drops ticks its reader is not ready for
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		if t.stopped {
+			continue
+		}
+		for !t.next.After(c.now) {
+			select {
+			case t.c <- t.next:
+			default:
+			}
+			t.next = t.next.Add(t.period)
+			if t.once {
+				t.stopped = true
+				break
+			}
+		}
+	}
+}
+
+// tickerCount returns the number of tickers created so far
+func (c *fakeClock) tickerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.tickers)
+}
+
+// stoppedTickerCount returns the number of tickers stopped so far
+func (c *fakeClock) stoppedTickerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stopped := 0
+	for _, t := range c.tickers {
+		if t.stopped {
+			stopped++
+		}
+	}
+	return stopped
+}
+
+type fakeTicker struct {
+	clock   *fakeClock
+	c       chan time.Time
+	period  time.Duration
+	next    time.Time
+	once    bool
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
+
+func TestCollectOnClock(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Minute
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clk := newFakeClock(start)
+	sink := new(consumertest.MetricsSink)
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		clock:    clk,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	// The first trace runs right after start, the next one only once the interval passed on the clock
+	require.Eventually(t, func() bool { return sink.DataPointCount() > 0 && clk.tickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, sink.AllMetrics(), 1)
+	clk.Advance(cfg.CollectionInterval)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	for i, want := range []time.Time{start, start.Add(cfg.CollectionInterval)} {
+		dp := sink.AllMetrics()[i].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+		assert.Equal(t, pcommon.NewTimestampFromTime(want), dp.Timestamp())
+	}
+}
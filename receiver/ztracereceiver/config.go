@@ -6,10 +6,16 @@ package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"errors"
 	"fmt"
+	"net"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.uber.org/multierr"
 )
 
 // Config defines configuration for the ztrace receiver
@@ -19,13 +25,23 @@ type Config struct {
 	// Targets defines the list of targets to trace
 	Targets []TargetConfig `mapstructure:"targets"`
 
+	// TargetsFile is the path of a JSON file listing more targets, or a glob pattern matching several.
+	// The files are re-read on SIGHUP and every TargetsReloadInterval, new targets are traced without a restart
+	TargetsFile string `mapstructure:"targets_file"`
+
+	// TargetsReloadInterval is how often targets_file is re-read, 0 only re-reads it on SIGHUP
+	TargetsReloadInterval time.Duration `mapstructure:"targets_reload_interval"`
+
+	// TargetGroups are named sets of settings targets inherit by referencing the group
+	TargetGroups map[string]TargetGroupConfig `mapstructure:"target_groups"`
+
 	// CollectionInterval is the interval at which to collect ztrace data
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
 
 	// Timeout for each trace operation
 	Timeout time.Duration `mapstructure:"timeout"`
 
-	// Protocol to use for tracing (udp, icmp, tcp)
+	// Protocol to use for tracing (udp, icmp, tcp, or udp-icmp to confirm the target with an ICMP echo)
 	Protocol string `mapstructure:"protocol"`
 
 	// MaxHops is the maximum number of hops to trace
@@ -37,11 +53,129 @@ type Config struct {
 	// Retries is the number of retries for each hop
 	Retries int `mapstructure:"retries"`
 
+	// TCPFlags is the flag set of tcp probes: "syn" looks like a new connection, "ack" like a packet
+	// of an established one
+	TCPFlags string `mapstructure:"tcp_flags"`
+
+	// SourceAddresses are local addresses the connections to the proxy are made from, rotated per
+	// trace of each target by weight to spread the traces over several egress links. Only with a proxy
+	SourceAddresses []SourceAddressConfig `mapstructure:"source_addresses"`
+
+	// InterProbeDelay is the gap between the probes to the same hop, to avoid ICMP rate limiting
+	InterProbeDelay time.Duration `mapstructure:"inter_probe_delay"`
+
+	// ProbeRateLimit caps the probes sent per second across all targets, 0 sends them as fast as they
+	// are answered
+	ProbeRateLimit float64 `mapstructure:"probe_rate_limit"`
+
+	// ProbeTimeout bounds each probe, 0 only bounds the whole trace by Timeout
+	ProbeTimeout time.Duration `mapstructure:"probe_timeout"`
+
+	// MTU of the path to the targets, packet_size must fit in it. 0 disables the check
+	MTU int `mapstructure:"mtu"`
+
 	// EnableGeolocation enables geolocation lookup for IP addresses
 	EnableGeolocation bool `mapstructure:"enable_geolocation"`
 
 	// EnableASNLookup enables ASN lookup for IP addresses
 	EnableASNLookup bool `mapstructure:"enable_asn_lookup"`
+
+	// LookupCacheSize is the number of hop addresses whose geolocation and ASN are cached across
+	// targets and cycles, 0 disables the cache
+	LookupCacheSize int `mapstructure:"lookup_cache_size"`
+
+	// LookupCacheTTL is how long a cached lookup is reused, 0 keeps it until it is evicted
+	LookupCacheTTL time.Duration `mapstructure:"lookup_cache_ttl"`
+
+	// HopLatencyHistogram emits a histogram of all probe RTTs per hop alongside the latency gauge
+	HopLatencyHistogram bool `mapstructure:"hop_latency_histogram"`
+
+	// TotalLatencyHistogram emits a histogram of the total latency of every trace since the first one
+	// alongside the total latency gauge
+	TotalLatencyHistogram bool `mapstructure:"total_latency_histogram"`
+
+	// TotalLatencyBuckets are the bucket boundaries of the total latency histogram, in increasing order
+	TotalLatencyBuckets []time.Duration `mapstructure:"total_latency_buckets"`
+
+	// IncludeSourceHost adds the hostname of the probing host to every result, to tell vantage points apart
+	IncludeSourceHost bool `mapstructure:"include_source_host"`
+
+	// SourceRegion and SourceSite optionally describe where the probing host is located
+	SourceRegion string `mapstructure:"source_region"`
+	SourceSite   string `mapstructure:"source_site"`
+
+	// Proxy is a socks5:// URL the tcp protocol connects through. Hops cannot be discovered through
+	// a proxy, only the connect latency to the target is measured
+	Proxy string `mapstructure:"proxy"`
+
+	// Source of the trace results: "network" probes the targets, "file" replays the results
+	// recorded with raw_output_path from SourcePath
+	Source     string `mapstructure:"source"`
+	SourcePath string `mapstructure:"source_path"`
+
+	// RawOutputPath is a file every trace result is appended to as a JSON line, for offline analysis
+	RawOutputPath string `mapstructure:"raw_output_path"`
+
+	// PacketLossEventThreshold is the hop packet loss percentage above which a high_packet_loss event is emitted
+	PacketLossEventThreshold float64 `mapstructure:"packet_loss_event_threshold"`
+
+	// SpanTopology is how hop spans are parented: "star" parents every hop to the root span,
+	// "chain" parents each hop to the previous one and links it to the root span
+	SpanTopology string `mapstructure:"span_topology"`
+
+	// SpanMode is how hops are emitted in traces: "span_per_hop" as a span each, "events" as events
+	// of the root span, a single span per trace
+	SpanMode string `mapstructure:"span_mode"`
+
+	// LatencyWindow is the number of collection cycles of latency history kept per hop for
+	// ztrace.hop.latency.stddev, 0 disables the history
+	LatencyWindow int `mapstructure:"latency_window"`
+
+	// LatencySpikeFactor adds a latency_spike event to a hop span when the hop latency exceeds the
+	// median of its previous cycles by this factor, 0 disables it. Requires latency_window
+	LatencySpikeFactor float64 `mapstructure:"latency_spike_factor"`
+
+	// LatencyUnit is the unit latencies are reported in: "ms", "us" or "s"
+	LatencyUnit string `mapstructure:"latency_unit"`
+
+	// ExportRetry retries and queues the metrics the next consumer rejected, disabled by default
+	ExportRetry ExportRetryConfig `mapstructure:"export_retry"`
+
+	// MetricAttributes drops data point attributes from the emitted metrics to bound their cardinality,
+	// traces and logs keep every attribute
+	MetricAttributes MetricAttributesConfig `mapstructure:"metric_attributes"`
+
+	// DeltaMode only emits the metrics of hops that changed since they were last emitted, the
+	// trace summary metrics are always emitted. It trades the completeness of every cycle for volume
+	DeltaMode bool `mapstructure:"delta_mode"`
+
+	// DeltaLatencyThreshold is how far the latency of a hop must move to count as a change in delta_mode
+	DeltaLatencyThreshold time.Duration `mapstructure:"delta_latency_threshold"`
+
+	// MaxTraceDuration caps the time a trace walks the hops, the hops beyond it are skipped and the
+	// partial result is reported. Unlike Timeout it keeps the hops walked so far. 0 disables the cap
+	MaxTraceDuration time.Duration `mapstructure:"max_trace_duration"`
+
+	// MaxDataPoints caps the metric data points and spans converted from one trace, the hops beyond
+	// it are dropped with a warning while the trace summary is always kept. 0 disables the cap
+	MaxDataPoints int `mapstructure:"max_data_points"`
+
+	// AttributeConvention names the attributes of the target and hops after "ztrace" or the
+	// OpenTelemetry semantic conventions with "semconv"
+	AttributeConvention string `mapstructure:"attribute_convention"`
+
+	// ServiceName is the service.name resource attribute of the emitted traces, to tell the collectors
+	// apart in a shared backend
+	ServiceName string `mapstructure:"service_name"`
+
+	// ScopeName is the instrumentation scope name of the emitted metrics, traces and logs
+	ScopeName string `mapstructure:"scope_name"`
+
+	// PingOnly skips the per-TTL walk and only probes the target, for reachability and end-to-end latency
+	PingOnly bool `mapstructure:"ping_only"`
+
+	// DNSCacheTTL is how long a resolved target address is reused before resolving it again, 0 resolves on every trace
+	DNSCacheTTL time.Duration `mapstructure:"dns_cache_ttl"`
 }
 
 // TargetConfig defines configuration for a single target
@@ -52,50 +186,522 @@ type TargetConfig struct {
 	// Port is the target port (for TCP/UDP protocols)
 	Port int `mapstructure:"port"`
 
+	// Ports are additionally probed on the target after each tcp trace, to report which ones it can be reached on
+	Ports []int `mapstructure:"ports"`
+
 	// Tags are optional tags to add to the metrics
 	Tags map[string]string `mapstructure:"tags"`
+
+	// Enabled allows to keep a target in the configuration without tracing it, defaults to true
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Group is the name of the target group the target inherits its settings from
+	Group string `mapstructure:"group"`
+
+	// Protocol, CollectionInterval, EnableGeolocation and EnableASNLookup override the settings
+	// of the group and the receiver for this target
+	Protocol           string        `mapstructure:"protocol"`
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+	EnableGeolocation  *bool         `mapstructure:"enable_geolocation"`
+	EnableASNLookup    *bool         `mapstructure:"enable_asn_lookup"`
+}
+
+// TargetGroupConfig defines the settings shared by the targets of a group. Unset settings are
+// taken from the receiver, settings of a member target take precedence.
+type TargetGroupConfig struct {
+	// Protocol to trace the member targets with
+	Protocol string `mapstructure:"protocol"`
+
+	// CollectionInterval is the interval at which the member targets are traced
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Tags are added to the metrics of every member target, tags of the target take precedence
+	Tags map[string]string `mapstructure:"tags"`
+
+	// EnableGeolocation and EnableASNLookup enable the lookups for the hops of the member targets
+	EnableGeolocation *bool `mapstructure:"enable_geolocation"`
+	EnableASNLookup   *bool `mapstructure:"enable_asn_lookup"`
+}
+
+// ExportRetryConfig defines how metrics rejected by the next consumer are retried. Retries wait
+// within the trace timeout, metrics still rejected are queued and pushed before the next trace's.
+type ExportRetryConfig struct {
+	// MaxRetries is the number of retries of a rejected push, 0 does not retry
+	MaxRetries int `mapstructure:"max_retries"`
+
+	// InitialBackoff is the wait before the first retry, doubled up to MaxBackoff
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+
+	// QueueSize is the number of rejected metric batches kept per target, the oldest is dropped
+	// when it is full. 0 drops the metrics once the retries are exhausted
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// MetricAttributesConfig selects the hop attributes of the emitted data points, either the
+// attributes to keep or the ones to drop. The other data point attributes are always kept.
+type MetricAttributesConfig struct {
+	// Include keeps only the listed hop attributes, all of them when empty
+	Include []string `mapstructure:"include"`
+
+	// Exclude drops the listed hop attributes
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// SourceAddressConfig is a local address the connections to the proxy are made from
+type SourceAddressConfig struct {
+	// Address is the local IP address, it is not checked against the interfaces of the host
+	Address string `mapstructure:"address"`
+
+	// Weight is the share of the traces sent from the address relative to the other addresses,
+	// 0 or unset counts as 1
+	Weight int `mapstructure:"weight"`
+}
+
+// weight returns the weight of the address, unset and 0 weights count as 1
+func (source SourceAddressConfig) weight() int {
+	if source.Weight == 0 {
+		return 1
+	}
+	return source.Weight
+}
+
+// enabled reports whether the target is traced
+func (target TargetConfig) enabled() bool {
+	return target.Enabled == nil || *target.Enabled
+}
+
+// resolveTarget returns the target with the settings of its group it does not override
+func (cfg *Config) resolveTarget(target TargetConfig) TargetConfig {
+	group, ok := cfg.TargetGroups[target.Group]
+	if !ok {
+		return target
+	}
+	if target.Protocol == "" {
+		target.Protocol = group.Protocol
+	}
+	if target.CollectionInterval == 0 {
+		target.CollectionInterval = group.CollectionInterval
+	}
+	if target.EnableGeolocation == nil {
+		target.EnableGeolocation = group.EnableGeolocation
+	}
+	if target.EnableASNLookup == nil {
+		target.EnableASNLookup = group.EnableASNLookup
+	}
+	if len(group.Tags) > 0 {
+		tags := make(map[string]string, len(group.Tags)+len(target.Tags))
+		for k, v := range group.Tags {
+			tags[k] = v
+		}
+		for k, v := range target.Tags {
+			tags[k] = v
+		}
+		target.Tags = tags
+	}
+	return target
+}
+
+// forTarget returns the receiver settings with the overrides of a resolved target applied
+func (cfg *Config) forTarget(target TargetConfig) *Config {
+	settings := *cfg
+	if target.Protocol != "" {
+		settings.Protocol = target.Protocol
+	}
+	if target.CollectionInterval > 0 {
+		settings.CollectionInterval = target.CollectionInterval
+	}
+	if target.EnableGeolocation != nil {
+		settings.EnableGeolocation = *target.EnableGeolocation
+	}
+	if target.EnableASNLookup != nil {
+		settings.EnableASNLookup = *target.EnableASNLookup
+	}
+	return &settings
+}
+
+// validProtocol reports whether protocol is one of the supported protocols
+func validProtocol(protocol string) bool {
+	return protocol == "udp" || protocol == "icmp" || protocol == "tcp" || protocol == "udp-icmp"
+}
+
+// serviceName returns the service.name of the emitted traces, "ztrace" when unset
+func (cfg *Config) serviceName() string {
+	if cfg.ServiceName == "" {
+		return "ztrace"
+	}
+	return cfg.ServiceName
+}
+
+// scopeName returns the instrumentation scope name of the emitted telemetry, "ztrace" when unset
+func (cfg *Config) scopeName() string {
+	if cfg.ScopeName == "" {
+		return "ztrace"
+	}
+	return cfg.ScopeName
+}
+
+// latencyScale returns the unit latencies are reported in and the factor converting milliseconds to it
+func (cfg *Config) latencyScale() (string, float64) {
+	switch cfg.LatencyUnit {
+	case "us":
+		return "us", 1000
+	case "s":
+		return "s", 0.001
+	default:
+		return "ms", 1
+	}
+}
+
+// maxSweepPorts bounds the ports probed per target, every port adds retries + 1 probes to each trace
+const maxSweepPorts = 64
+
+// minDataPoints is the smallest max_data_points. The trace summary is emitted whole and takes up
+// to about 90 data points, most of them for the maxSweepPorts swept ports.
+const minDataPoints = 100
+
+// validatePorts checks the ports swept on the target, named e.g. target[0] in errors
+func (target TargetConfig) validatePorts(name string, protocol string) error {
+	if protocol != "tcp" {
+		return fmt.Errorf("%s: ports requires the tcp protocol", name)
+	}
+	if len(target.Ports) > maxSweepPorts {
+		return fmt.Errorf("%s: at most %d ports can be probed, got %d", name, maxSweepPorts, len(target.Ports))
+	}
+	seen := make(map[int]bool, len(target.Ports))
+	for _, port := range target.Ports {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("%s: port %d in ports must be between 1 and 65535", name, port)
+		}
+		if seen[port] {
+			return fmt.Errorf("%s: port %d is listed twice in ports", name, port)
+		}
+		seen[port] = true
+	}
+	return nil
+}
+
+// validateTarget checks a target of the configuration or of targets_file, named e.g. target[0] in errors
+func (cfg *Config) validateTarget(name string, target TargetConfig) error {
+	var err error
+	if target.Group != "" {
+		if _, ok := cfg.TargetGroups[target.Group]; !ok {
+			err = multierr.Append(err, fmt.Errorf("%s: undefined group %q", name, target.Group))
+		}
+	}
+	if target.Protocol != "" && !validProtocol(target.Protocol) {
+		err = multierr.Append(err, fmt.Errorf("%s: invalid protocol %q, must be one of: udp, icmp, tcp, udp-icmp", name, target.Protocol))
+	}
+	if target.CollectionInterval < 0 {
+		err = multierr.Append(err, fmt.Errorf("%s: collection_interval must be non-negative", name))
+	}
+	protocol := cfg.forTarget(cfg.resolveTarget(target)).Protocol
+	if target.Endpoint == "" {
+		err = multierr.Append(err, fmt.Errorf("%s: endpoint cannot be empty", name))
+	}
+	if protocol != "icmp" && target.Port <= 0 && target.enabled() {
+		err = multierr.Append(err, fmt.Errorf("%s: port must be specified for %s protocol", name, protocol))
+	}
+	if len(target.Ports) > 0 {
+		err = multierr.Append(err, target.validatePorts(name, protocol))
+	}
+	if cfg.Proxy != "" && protocol != cfg.Protocol && protocol != "tcp" {
+		err = multierr.Append(err, fmt.Errorf("%s: proxy requires the tcp protocol, target uses %s", name, protocol))
+	}
+	return err
+}
+
+// targetKey identifies a target by its effective protocol, endpoint and port, ICMP ignores the port
+func (cfg *Config) targetKey(target TargetConfig) string {
+	protocol := cfg.forTarget(cfg.resolveTarget(target)).Protocol
+	key := strings.ToLower(target.Endpoint)
+	if protocol != "icmp" {
+		key = fmt.Sprintf("%s:%d", key, target.Port)
+	}
+	return protocol + "/" + key
 }
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
-	if len(cfg.Targets) == 0 {
-		return errors.New("at least one target must be specified")
+	var err error
+
+	if len(cfg.Targets) == 0 && cfg.TargetsFile == "" {
+		err = multierr.Append(err, errors.New("at least one target must be specified"))
 	}
 
-	for i, target := range cfg.Targets {
-		if target.Endpoint == "" {
-			return fmt.Errorf("target[%d]: endpoint cannot be empty", i)
+	if cfg.TargetsFile != "" {
+		if _, globErr := filepath.Match(cfg.TargetsFile, ""); globErr != nil {
+			err = multierr.Append(err, fmt.Errorf("invalid targets_file pattern %q: %w", cfg.TargetsFile, globErr))
 		}
-		if cfg.Protocol != "icmp" && target.Port <= 0 {
-			return fmt.Errorf("target[%d]: port must be specified for %s protocol", i, cfg.Protocol)
+	}
+
+	if cfg.TargetsReloadInterval < 0 {
+		err = multierr.Append(err, errors.New("targets_reload_interval must be non-negative"))
+	}
+
+	// Groups only override the receiver settings, unset ones are inherited
+	names := make([]string, 0, len(cfg.TargetGroups))
+	for name := range cfg.TargetGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group := cfg.TargetGroups[name]
+		if group.Protocol != "" && !validProtocol(group.Protocol) {
+			err = multierr.Append(err, fmt.Errorf("target_groups[%s]: invalid protocol %q, must be one of: udp, icmp, tcp, udp-icmp", name, group.Protocol))
+		}
+		if group.CollectionInterval < 0 {
+			err = multierr.Append(err, fmt.Errorf("target_groups[%s]: collection_interval must be non-negative", name))
 		}
 	}
 
+	// Every target is traced with its effective protocol, ICMP ignores the port
+	seen := make(map[string]int, len(cfg.Targets))
+	for i, target := range cfg.Targets {
+		err = multierr.Append(err, cfg.validateTarget(fmt.Sprintf("target[%d]", i), target))
+		key := cfg.targetKey(target)
+		if j, ok := seen[key]; ok {
+			protocol := cfg.forTarget(cfg.resolveTarget(target)).Protocol
+			err = multierr.Append(err, fmt.Errorf("target[%d]: duplicate of target[%d] (endpoint %q, port %d, protocol %s)", i, j, target.Endpoint, target.Port, protocol))
+		}
+		seen[key] = i
+	}
+
 	if cfg.CollectionInterval <= 0 {
-		return errors.New("collection_interval must be positive")
+		err = multierr.Append(err, errors.New("collection_interval must be positive"))
 	}
 
 	if cfg.Timeout <= 0 {
-		return errors.New("timeout must be positive")
+		err = multierr.Append(err, errors.New("timeout must be positive"))
 	}
 
-	if cfg.Protocol != "udp" && cfg.Protocol != "icmp" && cfg.Protocol != "tcp" {
-		return fmt.Errorf("invalid protocol %q, must be one of: udp, icmp, tcp", cfg.Protocol)
+	if cfg.PacketLossEventThreshold < 0 || cfg.PacketLossEventThreshold > 100 {
+		err = multierr.Append(err, errors.New("packet_loss_event_threshold must be between 0 and 100"))
+	}
+
+	if cfg.Proxy != "" {
+		if cfg.Protocol != "tcp" {
+			err = multierr.Append(err, errors.New("proxy requires the tcp protocol"))
+		}
+		if _, proxyErr := parseProxyURL(cfg.Proxy); proxyErr != nil {
+			err = multierr.Append(err, proxyErr)
+		}
+	}
+
+	switch cfg.TCPFlags {
+	case "", "syn":
+	case "ack":
+		if cfg.Protocol != "tcp" {
+			err = multierr.Append(err, errors.New("tcp_flags ack requires the tcp protocol"))
+		}
+		if cfg.Proxy != "" {
+			err = multierr.Append(err, errors.New("tcp_flags ack is not supported through a proxy, it connects to the target"))
+		}
+	default:
+		err = multierr.Append(err, fmt.Errorf("invalid tcp_flags %q, must be one of: syn, ack", cfg.TCPFlags))
+	}
+
+	if cfg.LatencyUnit != "" && cfg.LatencyUnit != "ms" && cfg.LatencyUnit != "us" && cfg.LatencyUnit != "s" {
+		err = multierr.Append(err, fmt.Errorf("invalid latency_unit %q, must be one of: ms, us, s", cfg.LatencyUnit))
+	}
+
+	if cfg.AttributeConvention != "" && cfg.AttributeConvention != "ztrace" && cfg.AttributeConvention != "semconv" {
+		err = multierr.Append(err, fmt.Errorf("invalid attribute_convention %q, must be one of: ztrace, semconv", cfg.AttributeConvention))
+	}
+
+	if cfg.ServiceName != "" && strings.TrimSpace(cfg.ServiceName) == "" {
+		err = multierr.Append(err, errors.New("service_name must not be blank"))
+	}
+	if cfg.ScopeName != "" && strings.TrimSpace(cfg.ScopeName) == "" {
+		err = multierr.Append(err, errors.New("scope_name must not be blank"))
+	}
+
+	if cfg.Source != "" && cfg.Source != "network" && cfg.Source != "file" {
+		err = multierr.Append(err, fmt.Errorf("invalid source %q, must be one of: network, file", cfg.Source))
+	}
+
+	if cfg.Source == "file" && cfg.SourcePath == "" {
+		err = multierr.Append(err, errors.New("source_path must be specified for file source"))
+	}
+
+	if cfg.SpanTopology != "" && cfg.SpanTopology != "star" && cfg.SpanTopology != "chain" {
+		err = multierr.Append(err, fmt.Errorf("invalid span_topology %q, must be one of: star, chain", cfg.SpanTopology))
+	}
+
+	if cfg.SpanMode != "" && cfg.SpanMode != "span_per_hop" && cfg.SpanMode != "events" {
+		err = multierr.Append(err, fmt.Errorf("invalid span_mode %q, must be one of: span_per_hop, events", cfg.SpanMode))
+	}
+
+	if cfg.LatencyWindow < 0 {
+		err = multierr.Append(err, errors.New("latency_window must be non-negative"))
+	}
+
+	if cfg.LatencySpikeFactor < 0 {
+		err = multierr.Append(err, errors.New("latency_spike_factor must be non-negative"))
+	}
+
+	if cfg.LatencySpikeFactor > 0 && cfg.LatencyWindow == 0 {
+		err = multierr.Append(err, errors.New("latency_spike_factor requires latency_window"))
+	}
+
+	if cfg.TotalLatencyHistogram && len(cfg.TotalLatencyBuckets) == 0 {
+		err = multierr.Append(err, errors.New("total_latency_histogram requires total_latency_buckets"))
+	}
+	for i, bucket := range cfg.TotalLatencyBuckets {
+		switch {
+		case bucket <= 0:
+			err = multierr.Append(err, fmt.Errorf("total_latency_buckets[%d]: %s must be positive", i, bucket))
+		case i > 0 && bucket <= cfg.TotalLatencyBuckets[i-1]:
+			err = multierr.Append(err, fmt.Errorf("total_latency_buckets[%d]: %s must be greater than the previous bucket", i, bucket))
+		}
+	}
+
+	if cfg.ExportRetry.MaxRetries < 0 {
+		err = multierr.Append(err, errors.New("export_retry.max_retries must be non-negative"))
+	}
+	if cfg.ExportRetry.MaxRetries > 0 && cfg.ExportRetry.InitialBackoff <= 0 {
+		err = multierr.Append(err, errors.New("export_retry.initial_backoff must be positive to retry"))
+	}
+	if cfg.ExportRetry.MaxBackoff < cfg.ExportRetry.InitialBackoff {
+		err = multierr.Append(err, errors.New("export_retry.max_backoff must not be shorter than initial_backoff"))
+	}
+	if cfg.ExportRetry.QueueSize < 0 {
+		err = multierr.Append(err, errors.New("export_retry.queue_size must be non-negative"))
+	}
+
+	if len(cfg.MetricAttributes.Include) > 0 && len(cfg.MetricAttributes.Exclude) > 0 {
+		err = multierr.Append(err, errors.New("metric_attributes.include and metric_attributes.exclude are mutually exclusive"))
+	}
+	for i, name := range cfg.MetricAttributes.Include {
+		if name == "" {
+			err = multierr.Append(err, fmt.Errorf("metric_attributes.include[%d] must not be empty", i))
+		} else if !slices.Contains(hopMetricAttributes, name) {
+			err = multierr.Append(err, fmt.Errorf("metric_attributes.include[%d] %q must be one of %s", i, name, strings.Join(hopMetricAttributes, ", ")))
+		}
+	}
+	for i, name := range cfg.MetricAttributes.Exclude {
+		if name == "" {
+			err = multierr.Append(err, fmt.Errorf("metric_attributes.exclude[%d] must not be empty", i))
+		} else if !slices.Contains(hopMetricAttributes, name) {
+			err = multierr.Append(err, fmt.Errorf("metric_attributes.exclude[%d] %q must be one of %s", i, name, strings.Join(hopMetricAttributes, ", ")))
+		}
+	}
+
+	if cfg.DeltaLatencyThreshold < 0 {
+		err = multierr.Append(err, errors.New("delta_latency_threshold must be non-negative"))
+	}
+
+	if cfg.MaxTraceDuration < 0 {
+		err = multierr.Append(err, errors.New("max_trace_duration must be non-negative"))
+	} else if cfg.MaxTraceDuration > 0 && cfg.Timeout > 0 && cfg.MaxTraceDuration >= cfg.Timeout {
+		err = multierr.Append(err, fmt.Errorf("max_trace_duration %s must be shorter than timeout %s, which fails the trace first", cfg.MaxTraceDuration, cfg.Timeout))
+	}
+
+	if cfg.MaxDataPoints < 0 {
+		err = multierr.Append(err, errors.New("max_data_points must be non-negative"))
+	} else if cfg.MaxDataPoints > 0 && cfg.MaxDataPoints < minDataPoints {
+		err = multierr.Append(err, fmt.Errorf("max_data_points must be 0 or at least %d", minDataPoints))
+	}
+
+	if cfg.DNSCacheTTL < 0 {
+		err = multierr.Append(err, errors.New("dns_cache_ttl must be non-negative"))
+	}
+
+	if cfg.LookupCacheSize < 0 {
+		err = multierr.Append(err, errors.New("lookup_cache_size must be non-negative"))
+	}
+
+	if cfg.LookupCacheTTL < 0 {
+		err = multierr.Append(err, errors.New("lookup_cache_ttl must be non-negative"))
+	}
+
+	if len(cfg.SourceAddresses) > 0 && cfg.Proxy == "" {
+		err = multierr.Append(err, errors.New("source_addresses requires a proxy, only the proxy connection is made from a source address"))
+	}
+	seenSources := make(map[string]bool, len(cfg.SourceAddresses))
+	for i, source := range cfg.SourceAddresses {
+		ip := net.ParseIP(source.Address)
+		switch {
+		case ip == nil:
+			err = multierr.Append(err, fmt.Errorf("source_addresses[%d]: invalid address %q, must be an IP address", i, source.Address))
+		case ip.IsUnspecified():
+			err = multierr.Append(err, fmt.Errorf("source_addresses[%d]: address %s is unspecified", i, source.Address))
+		case seenSources[ip.String()]:
+			err = multierr.Append(err, fmt.Errorf("source_addresses[%d]: duplicate address %s", i, source.Address))
+		}
+		if ip != nil {
+			seenSources[ip.String()] = true
+		}
+		if source.Weight < 0 {
+			err = multierr.Append(err, fmt.Errorf("source_addresses[%d]: weight must be non-negative", i))
+		}
+	}
+
+	if cfg.InterProbeDelay < 0 {
+		err = multierr.Append(err, errors.New("inter_probe_delay must be non-negative"))
+	}
+	if cfg.ProbeRateLimit < 0 {
+		err = multierr.Append(err, errors.New("probe_rate_limit must be non-negative"))
+	}
+
+	// The gaps between the retries of a full trace must leave time for the probes
+	if cfg.InterProbeDelay > 0 && cfg.Timeout > 0 && cfg.Retries > 0 && cfg.MaxHops > 0 {
+		gaps := cfg.Retries
+		if !cfg.PingOnly && cfg.Proxy == "" {
+			gaps *= cfg.MaxHops
+		}
+		if total := time.Duration(gaps) * cfg.InterProbeDelay; total >= cfg.Timeout {
+			err = multierr.Append(err, fmt.Errorf("inter_probe_delay %s between %d retries (%s) does not fit in timeout %s", cfg.InterProbeDelay, gaps, total, cfg.Timeout))
+		}
+	}
+
+	if cfg.ProbeTimeout < 0 {
+		err = multierr.Append(err, errors.New("probe_timeout must be non-negative"))
+	}
+
+	// A trace that times out on every probe must still complete within the timeout
+	if cfg.ProbeTimeout > 0 && cfg.Timeout > 0 && cfg.Retries >= 0 && cfg.MaxHops > 0 {
+		probes := cfg.Retries + 1
+		if !cfg.PingOnly && cfg.Proxy == "" {
+			probes *= cfg.MaxHops
+		}
+		if worst := time.Duration(probes) * cfg.ProbeTimeout; worst > cfg.Timeout {
+			err = multierr.Append(err, fmt.Errorf("timeout %s is shorter than %d probes with probe_timeout %s (%s), lower retries or probe_timeout", cfg.Timeout, probes, cfg.ProbeTimeout, worst))
+		}
+	}
+
+	return multierr.Append(err, cfg.validateProbing())
+}
+
+// validateProbing checks the settings shared with the standalone Traceroute function
+func (cfg *Config) validateProbing() error {
+	var err error
+
+	if !validProtocol(cfg.Protocol) {
+		err = multierr.Append(err, fmt.Errorf("invalid protocol %q, must be one of: udp, icmp, tcp, udp-icmp", cfg.Protocol))
 	}
 
 	if cfg.MaxHops <= 0 || cfg.MaxHops > 64 {
-		return errors.New("max_hops must be between 1 and 64")
+		err = multierr.Append(err, errors.New("max_hops must be between 1 and 64"))
 	}
 
 	if cfg.PacketSize <= 0 || cfg.PacketSize > 65535 {
-		return errors.New("packet_size must be between 1 and 65535")
+		err = multierr.Append(err, errors.New("packet_size must be between 1 and 65535"))
 	}
 
 	if cfg.Retries < 0 {
-		return errors.New("retries must be non-negative")
+		err = multierr.Append(err, errors.New("retries must be non-negative"))
 	}
 
-	return nil
+	if cfg.MTU < 0 {
+		err = multierr.Append(err, errors.New("mtu must be non-negative"))
+	}
+
+	if cfg.MTU > 0 && cfg.PacketSize > cfg.MTU {
+		err = multierr.Append(err, fmt.Errorf("packet_size %d exceeds mtu %d", cfg.PacketSize, cfg.MTU))
+	}
+
+	return err
 }
 
 var _ component.Config = (*Config)(nil)
\ No newline at end of file
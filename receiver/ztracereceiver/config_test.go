@@ -69,6 +69,45 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: "at least one target must be specified",
 		},
+		{
+			name: "targets file without targets",
+			config: &Config{
+				TargetsFile:        "/etc/ztrace/targets/*.json",
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+		},
+		{
+			name: "invalid targets file pattern",
+			config: &Config{
+				TargetsFile:        "/etc/ztrace/[targets.json",
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: `invalid targets_file pattern "/etc/ztrace/[targets.json": syntax error in pattern`,
+		},
+		{
+			name: "negative targets reload interval",
+			config: &Config{
+				TargetsFile:           "/etc/ztrace/targets.json",
+				TargetsReloadInterval: -time.Minute,
+				CollectionInterval:    30 * time.Second,
+				Timeout:               10 * time.Second,
+				Protocol:              "udp",
+				MaxHops:               30,
+				PacketSize:            56,
+				Retries:               3,
+			},
+			wantErr: "targets_reload_interval must be non-negative",
+		},
 		{
 			name: "empty endpoint",
 			config: &Config{
@@ -104,6 +143,74 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: "target[0]: port must be specified for udp protocol",
 		},
+		{
+			name: "disabled target without port",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+					{
+						Endpoint: "example.org",
+						Enabled:  new(bool),
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+		},
+		{
+			name: "duplicate target",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+					{
+						Endpoint: "example.com",
+						Port:     443,
+					},
+					{
+						Endpoint: "Example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: `target[2]: duplicate of target[0] (endpoint "Example.com", port 80, protocol tcp)`,
+		},
+		{
+			name: "duplicate ICMP target with different port",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+					},
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "icmp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: `target[1]: duplicate of target[0] (endpoint "example.com", port 80, protocol icmp)`,
+		},
 		{
 			name: "invalid protocol",
 			config: &Config{
@@ -120,7 +227,7 @@ func TestValidateConfig(t *testing.T) {
 				PacketSize:         56,
 				Retries:            3,
 			},
-			wantErr: `invalid protocol "invalid", must be one of: udp, icmp, tcp`,
+			wantErr: `invalid protocol "invalid", must be one of: udp, icmp, tcp, udp-icmp`,
 		},
 		{
 			name: "invalid collection interval",
@@ -212,17 +319,1000 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: "retries must be non-negative",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := tt.config.Validate()
-			if tt.wantErr != "" {
-				require.Error(t, err)
-				assert.Equal(t, tt.wantErr, err.Error())
-			} else {
-				require.NoError(t, err)
-			}
-		})
-	}
-}
\ No newline at end of file
+		{
+			name: "invalid packet loss event threshold",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval:       30 * time.Second,
+				Timeout:                  10 * time.Second,
+				Protocol:                 "udp",
+				MaxHops:                  30,
+				PacketSize:               56,
+				Retries:                  3,
+				PacketLossEventThreshold: 150,
+			},
+			wantErr: "packet_loss_event_threshold must be between 0 and 100",
+		},
+		{
+			name: "file source without path",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				Source:             "file",
+			},
+			wantErr: "source_path must be specified for file source",
+		},
+		{
+			name: "invalid span topology",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SpanTopology:       "tree",
+			},
+			wantErr: `invalid span_topology "tree", must be one of: star, chain`,
+		},
+		{
+			name: "invalid span mode",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SpanMode:           "links",
+			},
+			wantErr: `invalid span_mode "links", must be one of: span_per_hop, events`,
+		},
+		{
+			name: "negative latency window",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				LatencyWindow:      -1,
+			},
+			wantErr: "latency_window must be non-negative",
+		},
+		{
+			name: "latency spike factor without window",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				LatencySpikeFactor: 3,
+			},
+			wantErr: "latency_spike_factor requires latency_window",
+		},
+		{
+			name: "negative dns cache ttl",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				DNSCacheTTL:        -time.Second,
+			},
+			wantErr: "dns_cache_ttl must be non-negative",
+		},
+		{
+			name: "probe timeout exceeding timeout",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ProbeTimeout:       100 * time.Millisecond,
+			},
+			wantErr: "timeout 10s is shorter than 120 probes with probe_timeout 100ms (12s), lower retries or probe_timeout",
+		},
+		{
+			name: "probe timeout with ping only",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ProbeTimeout:       time.Second,
+				PingOnly:           true,
+			},
+		},
+		{
+			name: "negative probe timeout",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ProbeTimeout:       -time.Second,
+			},
+			wantErr: "probe_timeout must be non-negative",
+		},
+		{
+			name: "packet size exceeding mtu",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         1600,
+				Retries:            3,
+				MTU:                1500,
+			},
+			wantErr: "packet_size 1600 exceeds mtu 1500",
+		},
+		{
+			name: "multiple errors",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "",
+						Port:     80,
+					},
+				},
+				Timeout:    10 * time.Second,
+				Protocol:   "udp",
+				PacketSize: 56,
+				Retries:    3,
+			},
+			wantErr: "target[0]: endpoint cannot be empty; collection_interval must be positive; max_hops must be between 1 and 64",
+		},
+		{
+			name: "tcp ack probes",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				TCPFlags:           "ack",
+			},
+		},
+		{
+			name: "tcp ack probes with udp",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				TCPFlags:           "ack",
+			},
+			wantErr: "tcp_flags ack requires the tcp protocol",
+		},
+		{
+			name: "invalid tcp flags",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				TCPFlags:           "fin",
+			},
+			wantErr: `invalid tcp_flags "fin", must be one of: syn, ack`,
+		},
+		{
+			name: "port sweep",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Ports:    []int{22, 80, 443},
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+		},
+		{
+			name: "port sweep with udp",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Ports:    []int{22, 80, 443},
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target[0]: ports requires the tcp protocol",
+		},
+		{
+			name: "port sweep with invalid port",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Ports:    []int{22, 65536},
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target[0]: port 65536 in ports must be between 1 and 65535",
+		},
+		{
+			name: "port sweep with duplicate port",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Ports:    []int{22, 80, 22},
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target[0]: port 22 is listed twice in ports",
+		},
+		{
+			name: "port sweep with too many ports",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Ports:    make([]int, 65),
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target[0]: at most 64 ports can be probed, got 65",
+		},
+		{
+			name: "invalid latency unit",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				LatencyUnit:        "ns",
+			},
+			wantErr: `invalid latency_unit "ns", must be one of: ms, us, s`,
+		},
+		{
+			name: "inter probe delay exceeding timeout",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				InterProbeDelay:    200 * time.Millisecond,
+			},
+			wantErr: "inter_probe_delay 200ms between 90 retries (18s) does not fit in timeout 10s",
+		},
+		{
+			name: "negative inter probe delay",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				InterProbeDelay:    -time.Millisecond,
+			},
+			wantErr: "inter_probe_delay must be non-negative",
+		},
+		{
+			name: "valid target group",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Group:    "edge",
+					},
+				},
+				TargetGroups: map[string]TargetGroupConfig{
+					"edge": {Protocol: "icmp", Tags: map[string]string{"tier": "edge"}},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+		},
+		{
+			name: "undefined target group",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Group:    "edge",
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target[0]: undefined group \"edge\"",
+		},
+		{
+			name: "invalid target group protocol",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+						Group:    "edge",
+					},
+				},
+				TargetGroups: map[string]TargetGroupConfig{
+					"edge": {Protocol: "sctp"},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target_groups[edge]: invalid protocol \"sctp\", must be one of: udp, icmp, tcp, udp-icmp",
+		},
+		{
+			name: "target group protocol requires port",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Group:    "web",
+					},
+				},
+				TargetGroups: map[string]TargetGroupConfig{
+					"web": {Protocol: "tcp"},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+			},
+			wantErr: "target[0]: port must be specified for tcp protocol",
+		},
+		{
+			name: "negative lookup cache size",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				LookupCacheSize:    -1,
+			},
+			wantErr: "lookup_cache_size must be non-negative",
+		},
+		{
+			name: "invalid attribute convention",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval:  30 * time.Second,
+				Timeout:             10 * time.Second,
+				Protocol:            "udp",
+				MaxHops:             30,
+				PacketSize:          56,
+				Retries:             3,
+				AttributeConvention: "ecs",
+			},
+			wantErr: "invalid attribute_convention \"ecs\", must be one of: ztrace, semconv",
+		},
+		{
+			name: "invalid source address",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				Proxy:              "socks5://127.0.0.1:1080",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SourceAddresses: []SourceAddressConfig{
+					{Address: "192.0.2.10", Weight: 2},
+					{Address: "eth0"},
+				},
+			},
+			wantErr: "source_addresses[1]: invalid address \"eth0\", must be an IP address",
+		},
+		{
+			name: "duplicate source address",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				Proxy:              "socks5://127.0.0.1:1080",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SourceAddresses: []SourceAddressConfig{
+					{Address: "192.0.2.10"},
+					{Address: "192.0.2.10"},
+				},
+			},
+			wantErr: "source_addresses[1]: duplicate address 192.0.2.10",
+		},
+		{
+			name: "unspecified source address",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				Proxy:              "socks5://127.0.0.1:1080",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SourceAddresses: []SourceAddressConfig{
+					{Address: "::"},
+				},
+			},
+			wantErr: "source_addresses[0]: address :: is unspecified",
+		},
+		{
+			name: "negative source address weight",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				Proxy:              "socks5://127.0.0.1:1080",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SourceAddresses: []SourceAddressConfig{
+					{Address: "192.0.2.10", Weight: -1},
+				},
+			},
+			wantErr: "source_addresses[0]: weight must be non-negative",
+		},
+		{
+			name: "source addresses without proxy",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				SourceAddresses: []SourceAddressConfig{
+					{Address: "192.0.2.10"},
+				},
+			},
+			wantErr: "source_addresses requires a proxy, only the proxy connection is made from a source address",
+		},
+		{
+			name: "export retry without backoff",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ExportRetry:        ExportRetryConfig{MaxRetries: 3},
+			},
+			wantErr: "export_retry.initial_backoff must be positive to retry",
+		},
+		{
+			name: "export retry max backoff shorter than initial",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ExportRetry:        ExportRetryConfig{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Millisecond},
+			},
+			wantErr: "export_retry.max_backoff must not be shorter than initial_backoff",
+		},
+		{
+			name: "negative export queue size",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ExportRetry:        ExportRetryConfig{QueueSize: -1},
+			},
+			wantErr: "export_retry.queue_size must be non-negative",
+		},
+		{
+			name: "negative probe rate limit",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ProbeRateLimit:     -1,
+			},
+			wantErr: "probe_rate_limit must be non-negative",
+		},
+		{
+			name: "total latency histogram without buckets",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval:    30 * time.Second,
+				Timeout:               10 * time.Second,
+				Protocol:              "udp",
+				MaxHops:               30,
+				PacketSize:            56,
+				Retries:               3,
+				TotalLatencyHistogram: true,
+			},
+			wantErr: "total_latency_histogram requires total_latency_buckets",
+		},
+		{
+			name: "unordered total latency buckets",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval:    30 * time.Second,
+				Timeout:               10 * time.Second,
+				Protocol:              "udp",
+				MaxHops:               30,
+				PacketSize:            56,
+				Retries:               3,
+				TotalLatencyHistogram: true,
+				TotalLatencyBuckets:   []time.Duration{0, 100 * time.Millisecond, 50 * time.Millisecond},
+			},
+			wantErr: "total_latency_buckets[0]: 0s must be positive; total_latency_buckets[2]: 50ms must be greater than the previous bucket",
+		},
+		{
+			name: "blank service and scope names",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				ServiceName:        " ",
+				ScopeName:          "\t",
+			},
+			wantErr: "service_name must not be blank; scope_name must not be blank",
+		},
+		{
+			name: "metric attributes include and exclude",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				MetricAttributes: MetricAttributesConfig{
+					Include: []string{"ttl"},
+					Exclude: []string{"hostname", ""},
+				},
+			},
+			wantErr: "metric_attributes.include and metric_attributes.exclude are mutually exclusive; " +
+				`metric_attributes.include[0] "ttl" must be one of ip, hostname, city, country, asn, provider, ztrace.hop.scope, ztrace.hop.reply_protocol; ` +
+				"metric_attributes.exclude[1] must not be empty",
+		},
+		{
+			name: "negative delta latency threshold",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval:    30 * time.Second,
+				Timeout:               10 * time.Second,
+				Protocol:              "udp",
+				MaxHops:               30,
+				PacketSize:            56,
+				Retries:               3,
+				DeltaMode:             true,
+				DeltaLatencyThreshold: -time.Millisecond,
+			},
+			wantErr: "delta_latency_threshold must be non-negative",
+		},
+		{
+			name: "max data points below the trace summary",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				MaxDataPoints:      20,
+			},
+			wantErr: "max_data_points must be 0 or at least 100",
+		},
+		{
+			name: "negative max trace duration",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				MaxTraceDuration:   -time.Second,
+			},
+			wantErr: "max_trace_duration must be non-negative",
+		},
+		{
+			name: "max trace duration not shorter than timeout",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				MaxTraceDuration:   10 * time.Second,
+			},
+			wantErr: "max_trace_duration 10s must be shorter than timeout 10s, which fails the trace first",
+		},
+		{
+			name: "proxy without tcp",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "udp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				Proxy:              "socks5://proxy.example.com:1080",
+			},
+			wantErr: "proxy requires the tcp protocol",
+		},
+		{
+			name: "unsupported proxy scheme",
+			config: &Config{
+				Targets: []TargetConfig{
+					{
+						Endpoint: "example.com",
+						Port:     80,
+					},
+				},
+				CollectionInterval: 30 * time.Second,
+				Timeout:            10 * time.Second,
+				Protocol:           "tcp",
+				MaxHops:            30,
+				PacketSize:         56,
+				Retries:            3,
+				Proxy:              "http://proxy.example.com:3128",
+			},
+			wantErr: "unsupported proxy scheme \"http\", must be socks5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	enabled, disabled := true, false
+	cfg := &Config{
+		Protocol:           "udp",
+		CollectionInterval: time.Minute,
+		EnableGeolocation:  true,
+		EnableASNLookup:    true,
+		TargetGroups: map[string]TargetGroupConfig{
+			"edge": {
+				Protocol:           "icmp",
+				CollectionInterval: 10 * time.Second,
+				Tags:               map[string]string{"tier": "edge", "team": "network"},
+				EnableGeolocation:  &disabled,
+			},
+		},
+	}
+
+	// Group settings apply unless the target overrides them
+	target := cfg.resolveTarget(TargetConfig{
+		Endpoint:        "example.com",
+		Group:           "edge",
+		Tags:            map[string]string{"team": "web"},
+		EnableASNLookup: &disabled,
+	})
+	assert.Equal(t, "icmp", target.Protocol)
+	assert.Equal(t, 10*time.Second, target.CollectionInterval)
+	assert.Equal(t, map[string]string{"tier": "edge", "team": "web"}, target.Tags)
+	settings := cfg.forTarget(target)
+	assert.Equal(t, "icmp", settings.Protocol)
+	assert.Equal(t, 10*time.Second, settings.CollectionInterval)
+	assert.False(t, settings.EnableGeolocation)
+	assert.False(t, settings.EnableASNLookup)
+
+	// Targets without a group keep the receiver settings
+	target = cfg.resolveTarget(TargetConfig{Endpoint: "example.org", Port: 53, EnableGeolocation: &enabled})
+	settings = cfg.forTarget(target)
+	assert.Equal(t, "udp", settings.Protocol)
+	assert.Equal(t, time.Minute, settings.CollectionInterval)
+	assert.True(t, settings.EnableGeolocation)
+	assert.True(t, settings.EnableASNLookup)
+
+	// The receiver settings are left untouched
+	assert.Equal(t, "udp", cfg.Protocol)
+	assert.True(t, cfg.EnableGeolocation)
+}
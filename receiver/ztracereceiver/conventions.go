@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+// attributeNames are the names of the attributes that differ between the attribute conventions
+type attributeNames struct {
+	target     string // endpoint of the target
+	port       string // port of the target
+	targetIP   string // address the target resolved to
+	protocol   string // probe protocol
+	sourceHost string // hostname of the probing host
+	sourceIP   string // local address the probes were sent from
+	hopIP      string // address of the hop that answered, on hop spans
+}
+
+// ztraceAttributes are the names of the ztrace convention, the default
+var ztraceAttributes = attributeNames{
+	target:     "ztrace.target",
+	port:       "ztrace.port",
+	targetIP:   "ztrace.target.ip",
+	protocol:   "ztrace.protocol",
+	sourceHost: "ztrace.source.host",
+	sourceIP:   "ztrace.source.address",
+	hopIP:      "ip",
+}
+
+// semconvAttributes are the names of the OpenTelemetry semantic conventions, so the telemetry
+// joins with other network telemetry about the same destination
+var semconvAttributes = attributeNames{
+	target:     "destination.address",
+	port:       "destination.port",
+	targetIP:   "network.peer.address",
+	protocol:   "network.transport",
+	sourceHost: "host.name",
+	sourceIP:   "network.local.address",
+	hopIP:      "network.peer.address",
+}
+
+// attributeNames returns the attribute names of the configured attribute_convention
+func (r *ztraceReceiver) attributeNames() attributeNames {
+	if r.config.AttributeConvention == "semconv" {
+		return semconvAttributes
+	}
+	return ztraceAttributes
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"math"
+	"sync"
+)
+
+// hopDeltas keeps the last emitted state of every hop per target for delta_mode. Hops are compared
+// with what was last emitted rather than with the previous cycle, so a latency drifting slowly
+// below the threshold every cycle is still emitted once it moved by the threshold in total.
+type hopDeltas struct {
+	threshold float64 // in milliseconds
+
+	mu   sync.Mutex
+	hops map[string]map[int]Hop // by target key and TTL
+}
+
+func newHopDeltas(threshold float64) *hopDeltas {
+	return &hopDeltas{
+		threshold: threshold,
+		hops:      make(map[string]map[int]Hop),
+	}
+}
+
+// changed reports whether the hop differs from the one last emitted at its TTL, in which case it
+// is remembered as emitted. A hop changes when it is new, answers from another address, changes
+// state, or its latency moved by at least the threshold.
+func (d *hopDeltas) changed(target string, hop Hop) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hops, ok := d.hops[target]
+	if !ok {
+		hops = make(map[int]Hop)
+		d.hops[target] = hops
+	}
+	last, ok := hops[hop.TTL]
+	if ok && last.IP == hop.IP && hopState(last) == hopState(hop) &&
+		math.Abs(hop.Latency-last.Latency) < d.threshold {
+		return false
+	}
+	hops[hop.TTL] = hop
+	return true
+}
+
+// retain forgets the hops of the target missing from its latest trace, e.g. after the path got
+// shorter, so they are emitted again when they reappear
+func (d *hopDeltas) retain(target string, current []Hop) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ttls := make(map[int]bool, len(current))
+	for _, hop := range current {
+		ttls[hop.TTL] = true
+	}
+	for ttl := range d.hops[target] {
+		if !ttls[ttl] {
+			delete(d.hops[target], ttl)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestHopDeltas(t *testing.T) {
+	d := newHopDeltas(5)
+	gateway := Hop{TTL: 1, IP: "192.168.1.1", Latency: 1, RTTs: []float64{1}}
+
+	assert.True(t, d.changed("example.com", gateway), "new hop")
+	assert.False(t, d.changed("example.com", gateway))
+	assert.True(t, d.changed("example.org", gateway), "targets are compared on their own")
+
+	// Small moves accumulate against the last emitted latency
+	gateway.Latency = 4
+	assert.False(t, d.changed("example.com", gateway))
+	gateway.Latency = 6
+	assert.True(t, d.changed("example.com", gateway))
+	gateway.Latency = 3
+	assert.False(t, d.changed("example.com", gateway))
+
+	rerouted := gateway
+	rerouted.IP = "192.168.1.2"
+	assert.True(t, d.changed("example.com", rerouted), "new address")
+	timeout := Hop{TTL: 1, TimedOut: true, PacketLoss: 100}
+	assert.True(t, d.changed("example.com", timeout), "new state")
+	assert.False(t, d.changed("example.com", timeout))
+
+	// A hop that leaves the path is emitted again when it comes back
+	isp := Hop{TTL: 2, IP: "10.0.0.1", Latency: 10, RTTs: []float64{10}}
+	assert.True(t, d.changed("example.com", isp))
+	d.retain("example.com", []Hop{timeout})
+	assert.True(t, d.changed("example.com", isp))
+	assert.False(t, d.changed("example.com", timeout))
+}
+
+func TestConvertDeltaMode(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DeltaMode = true
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		deltas:   newHopDeltas(float64(cfg.DeltaLatencyThreshold) / float64(time.Millisecond)),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 1, RTTs: []float64{1}},
+			{TTL: 2, IP: "10.0.0.1", Latency: 10, RTTs: []float64{10}},
+		},
+		TotalLatency:  10,
+		TargetReached: true,
+	}
+
+	// emitted returns the TTLs with a latency data point and whether the summary was emitted
+	emitted := func() ([]int64, bool) {
+		var ttls []int64
+		summary := false
+		metrics := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			switch metric := metrics.At(i); metric.Name() {
+			case "ztrace.hop.latency":
+				ttl, _ := metric.Gauge().DataPoints().At(0).Attributes().Get("ttl")
+				ttls = append(ttls, ttl.Int())
+			case "ztrace.hop_count":
+				summary = metric.Gauge().DataPoints().At(0).IntValue() == int64(len(result.Hops))
+			}
+		}
+		return ttls, summary
+	}
+
+	ttls, summary := emitted()
+	assert.Equal(t, []int64{1, 2}, ttls)
+	assert.True(t, summary)
+
+	// A stable path only emits the summary
+	result.Hops[1].Latency = 12
+	ttls, summary = emitted()
+	assert.Empty(t, ttls)
+	assert.True(t, summary)
+
+	result.Hops[1].Latency = 20
+	ttls, _ = emitted()
+	assert.Equal(t, []int64{2}, ttls)
+
+	result.Hops = append(result.Hops, Hop{TTL: 3, IP: "203.0.113.1", Latency: 21, RTTs: []float64{21}})
+	ttls, summary = emitted()
+	assert.Equal(t, []int64{3}, ttls)
+	assert.True(t, summary)
+}
+
+func TestConvertDeltaModeMaxDataPoints(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.DeltaMode = true
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		deltas:   newHopDeltas(float64(cfg.DeltaLatencyThreshold) / float64(time.Millisecond)),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 1, RTTs: []float64{1}},
+			{TTL: 2, IP: "10.0.0.1", Latency: 10, RTTs: []float64{10}},
+		},
+		TotalLatency:  10,
+		TargetReached: true,
+	}
+
+	emitted := func() []int64 {
+		var ttls []int64
+		metrics := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			if metric := metrics.At(i); metric.Name() == "ztrace.hop.latency" {
+				ttl, _ := metric.Gauge().DataPoints().At(0).Attributes().Get("ttl")
+				ttls = append(ttls, ttl.Int())
+			}
+		}
+		return ttls
+	}
+
+	assert.Equal(t, []int64{1, 2}, emitted())
+
+	// Both hops changed, the 96 summary data points leave room for the first one only
+	cfg.MaxDataPoints = 100
+	result.Hops[0].Latency = 50
+	result.Hops[1].Latency = 60
+	for port := 1; port <= 92; port++ {
+		result.Ports = append(result.Ports, Port{Port: port})
+	}
+	assert.Equal(t, []int64{1}, emitted())
+
+	// The dropped hop is emitted again once it fits, although it did not change since
+	cfg.MaxDataPoints = 0
+	assert.Equal(t, []int64{2}, emitted())
+	assert.Empty(t, emitted())
+}
+
+func TestConvertDeltaModeSharedEndpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "tcp"
+	cfg.DeltaMode = true
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		deltas:   newHopDeltas(float64(cfg.DeltaLatencyThreshold) / float64(time.Millisecond)),
+	}
+	result := &Result{
+		Hops:          []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 1, RTTs: []float64{1}}},
+		TotalLatency:  1,
+		TargetReached: true,
+	}
+	hopMetrics := func(target TargetConfig) int {
+		count := 0
+		metrics := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			if metrics.At(i).Name() == "ztrace.hop.latency" {
+				count++
+			}
+		}
+		return count
+	}
+
+	// Targets on the same endpoint keep their own state, the second one emits its first hops too
+	assert.Equal(t, 1, hopMetrics(TargetConfig{Endpoint: "example.com", Port: 443}))
+	assert.Equal(t, 1, hopMetrics(TargetConfig{Endpoint: "example.com", Port: 80}))
+	assert.Zero(t, hopMetrics(TargetConfig{Endpoint: "example.com", Port: 443}))
+}
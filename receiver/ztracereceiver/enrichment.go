@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"net"
+
+	"go.uber.org/zap"
+)
+
+// Values of the type attribute of ztrace.enrichment.errors
+const (
+	enrichmentGeo = "geo"
+	enrichmentASN = "asn"
+)
+
+// geoProvider looks up the location of a public hop address, e.g. in a GeoIP database or over HTTP
+type geoProvider interface {
+	Geolocate(ip net.IP) (city, country string, err error)
+}
+
+// asnProvider looks up the autonomous system a public hop address is announced by
+type asnProvider interface {
+	LookupASN(ip net.IP) (asn, provider string, err error)
+}
+
+// enrichmentCounts are the outcomes of the lookups of a trace, or their totals for a target
+type enrichmentCounts struct {
+	geoErrors   int64
+	asnErrors   int64
+	cacheHits   int64
+	cacheMisses int64
+}
+
+// enrich looks up the location and autonomous system of the public hops with the configured
+// providers, answers cached by an earlier lookup are reused. Without a provider the hops keep
+// what the prober answered with. A failed lookup leaves the hop without the attributes.
+func (t *tracer) enrich(hops []Hop, config *Config) enrichmentCounts {
+	var counts enrichmentCounts
+	for i := range hops {
+		hop := &hops[i]
+		if ipScope(hop.IP) != scopePublic {
+			continue
+		}
+		if config.EnableGeolocation && t.geo != nil {
+			var ok bool
+			hop.City, hop.Country, ok = t.lookup(enrichmentGeo, hop.IP, t.geo.Geolocate, &counts)
+			if !ok {
+				counts.geoErrors++
+			}
+		}
+		if config.EnableASNLookup && t.asn != nil {
+			var ok bool
+			hop.ASN, hop.Provider, ok = t.lookup(enrichmentASN, hop.IP, t.asn.LookupASN, &counts)
+			if !ok {
+				counts.asnErrors++
+			}
+		}
+	}
+	return counts
+}
+
+// lookup answers one kind of lookup from the cache, or from the provider when it is not cached.
+// It reports false when the provider failed.
+func (t *tracer) lookup(kind, ip string, provider func(net.IP) (string, string, error), counts *enrichmentCounts) (string, string, bool) {
+	if t.cache != nil {
+		if name, detail, ok := t.cache.get(kind, ip, t.clock.Now()); ok {
+			counts.cacheHits++
+			return name, detail, true
+		}
+		counts.cacheMisses++
+	}
+	name, detail, err := provider(net.ParseIP(ip))
+	if err != nil {
+		t.logger.Debug("Enrichment lookup failed", zap.String("type", kind), zap.String("ip", ip), zap.Error(err))
+		return "", "", false
+	}
+	if t.cache != nil {
+		t.cache.put(kind, ip, name, detail, t.clock.Now())
+	}
+	return name, detail, true
+}
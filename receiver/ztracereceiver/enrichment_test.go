@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// failingProvider fails every lookup, like a provider whose database misses or whose HTTP API is down
+type failingProvider struct {
+	lookups int
+}
+
+func (p *failingProvider) Geolocate(net.IP) (string, string, error) {
+	p.lookups++
+	return "", "", errors.New("geo database miss")
+}
+
+func (p *failingProvider) LookupASN(net.IP) (string, string, error) {
+	p.lookups++
+	return "", "", errors.New("asn lookup: 503 Service Unavailable")
+}
+
+func TestTraceEnrichmentErrors(t *testing.T) {
+	p := &scriptedProber{
+		answers: map[int][]Hop{
+			1: {{IP: "192.168.1.1", RTTs: []float64{1}}},
+			2: {{IP: "80.81.192.1", City: "Berlin", Country: "Germany", ASN: "AS64501", RTTs: []float64{10}}},
+			3: {{IP: "127.0.0.1", RTTs: []float64{20}}},
+		},
+	}
+	tr := newScriptedTracer(p)
+	provider := &failingProvider{}
+	tr.geo = provider
+	tr.asn = provider
+	cfg := &Config{
+		Protocol:          "icmp",
+		MaxHops:           30,
+		EnableGeolocation: true,
+		EnableASNLookup:   true,
+	}
+
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	require.Len(t, result.Hops, 3)
+
+	// Only the public hop is looked up, the failed lookups drop what the prober answered with
+	assert.Equal(t, 2, provider.lookups)
+	assert.Empty(t, result.Hops[1].City)
+	assert.Empty(t, result.Hops[1].ASN)
+	assert.Equal(t, int64(1), result.GeoLookupErrors)
+	assert.Equal(t, int64(1), result.ASNLookupErrors)
+
+	// The counters are cumulative per target
+	p.calls = nil
+	result, err = tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.GeoLookupErrors)
+	assert.Equal(t, int64(2), result.ASNLookupErrors)
+}
+
+func TestConvertToMetricsEnrichmentErrors(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.EnableGeolocation = true
+	cfg.EnableASNLookup = true
+	r := &ztraceReceiver{config: cfg, settings: receivertest.NewNopSettings()}
+	result := &Result{
+		Target:          "example.com",
+		Timestamp:       time.Now(),
+		Hops:            []Hop{{TTL: 1, IP: "80.81.192.1", Latency: 10}},
+		ProbesSent:      4,
+		ProbesReceived:  4,
+		ProbesStart:     time.Now().Add(-time.Minute),
+		GeoLookupErrors: 3,
+		ASNLookupErrors: 1,
+	}
+
+	errorsByType := func(md pmetric.Metrics) map[string]int64 {
+		values := map[string]int64{}
+		ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < ms.Len(); i++ {
+			if ms.At(i).Name() != "ztrace.enrichment.errors" {
+				continue
+			}
+			sum := ms.At(i).Sum()
+			assert.True(t, sum.IsMonotonic())
+			assert.Equal(t, pmetric.AggregationTemporalityCumulative, sum.AggregationTemporality())
+			for j := 0; j < sum.DataPoints().Len(); j++ {
+				dp := sum.DataPoints().At(j)
+				kind, ok := dp.Attributes().Get("type")
+				require.True(t, ok)
+				values[kind.Str()] = dp.IntValue()
+			}
+		}
+		return values
+	}
+
+	target := TargetConfig{Endpoint: "example.com", Port: 80}
+	assert.Equal(t, map[string]int64{"geo": 3, "asn": 1}, errorsByType(r.convertToMetrics(result, target)))
+
+	// Only enabled lookups are reported
+	cfg.EnableGeolocation = false
+	assert.Equal(t, map[string]int64{"asn": 1}, errorsByType(r.convertToMetrics(result, target)))
+	cfg.EnableASNLookup = false
+	assert.Empty(t, errorsByType(r.convertToMetrics(result, target)))
+}
+
+// countingProvider answers every lookup and counts them
+type countingProvider struct {
+	lookups int
+}
+
+func (p *countingProvider) Geolocate(net.IP) (string, string, error) {
+	p.lookups++
+	return "Berlin", "Germany", nil
+}
+
+func (p *countingProvider) LookupASN(net.IP) (string, string, error) {
+	p.lookups++
+	return "AS64501", "Example ISP", nil
+}
+
+func TestTraceLookupCache(t *testing.T) {
+	p := &scriptedProber{}
+	tr := newScriptedTracer(p)
+	provider := &countingProvider{}
+	tr.geo = provider
+	tr.asn = provider
+	tr.cache = newLookupCache(16, time.Hour)
+	cfg := &Config{
+		Protocol:          "icmp",
+		MaxHops:           30,
+		EnableGeolocation: true,
+		EnableASNLookup:   true,
+		LookupCacheSize:   16,
+	}
+	route := func(target string) map[int][]Hop {
+		return map[int][]Hop{
+			1: {{IP: "80.81.192.1", RTTs: []float64{10}}},
+			2: {{IP: target, RTTs: []float64{20}}},
+		}
+	}
+
+	// The backbone hop is shared by both targets and only looked up once
+	p.answers = route("127.0.0.1")
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "Berlin", result.Hops[0].City)
+	assert.Equal(t, "AS64501", result.Hops[0].ASN)
+	assert.Zero(t, result.LookupCacheHits)
+	assert.Equal(t, int64(2), result.LookupCacheMisses)
+
+	p.answers, p.calls = route("127.0.0.2"), nil
+	result, err = tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.2"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "Berlin", result.Hops[0].City)
+	assert.Equal(t, "AS64501", result.Hops[0].ASN)
+	assert.Equal(t, int64(2), result.LookupCacheHits)
+	assert.Zero(t, result.LookupCacheMisses)
+	assert.Equal(t, 2, provider.lookups)
+}
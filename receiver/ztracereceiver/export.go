@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Signals a trace result is pushed to the pipeline as
+const (
+	signalMetrics = "metrics"
+	signalTraces  = "traces"
+	signalLogs    = "logs"
+)
+
+// exportDurationBounds are the explicit bucket boundaries, in milliseconds, of the ztrace.export.duration histogram
+var exportDurationBounds = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000}
+
+// exportKey identifies the pushes of one signal of a target
+type exportKey struct {
+	target string
+	signal string
+}
+
+// exportCounts are the pushes of one signal of a target since the first one
+type exportCounts struct {
+	start   time.Time
+	errors  int64
+	dropped int64
+	count   uint64
+	sum     float64 // in milliseconds
+	min     float64
+	max     float64
+	buckets []uint64
+}
+
+// exportStats counts the pushes to the next consumers, to tell export problems from collection problems
+type exportStats struct {
+	mu     sync.Mutex
+	counts map[exportKey]*exportCounts
+}
+
+// record adds a push of the signal of a target that took duration and failed when err is set
+func (s *exportStats) record(target, signal string, start time.Time, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts == nil {
+		s.counts = make(map[exportKey]*exportCounts)
+	}
+	key := exportKey{target: target, signal: signal}
+	counts, ok := s.counts[key]
+	if !ok {
+		counts = &exportCounts{start: start, buckets: make([]uint64, len(exportDurationBounds)+1)}
+		s.counts[key] = counts
+	}
+
+	ms := float64(duration) / float64(time.Millisecond)
+	bucket := sort.SearchFloat64s(exportDurationBounds, ms)
+	counts.buckets[bucket]++
+	if counts.count == 0 || ms < counts.min {
+		counts.min = ms
+	}
+	if counts.count == 0 || ms > counts.max {
+		counts.max = ms
+	}
+	counts.count++
+	counts.sum += ms
+	if err != nil {
+		counts.errors++
+	}
+}
+
+// drop counts a batch of the signal of a target dropped from the full export queue
+func (s *exportStats) drop(target, signal string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if counts, ok := s.counts[exportKey{target: target, signal: signal}]; ok {
+		counts.dropped++
+	}
+}
+
+// export pushes a signal of the target with push and records its duration and outcome
+func (r *ztraceReceiver) export(target TargetConfig, signal string, push func() error) error {
+	start := r.now()
+	err := push()
+	r.exports.record(r.config.targetKey(target), signal, start, r.now().Sub(start), err)
+	return err
+}
+
+// appendExportMetrics adds the export counters of the target's signals pushed so far. They are
+// reported with the next trace, the push of the current one is not done yet.
+func (r *ztraceReceiver) appendExportMetrics(sm pmetric.ScopeMetrics, target TargetConfig, timestamp pcommon.Timestamp) {
+	r.exports.mu.Lock()
+	defer r.exports.mu.Unlock()
+
+	targetKey := r.config.targetKey(target)
+	var signals []string
+	for key := range r.exports.counts {
+		if key.target == targetKey {
+			signals = append(signals, key.signal)
+		}
+	}
+	if len(signals) == 0 {
+		return
+	}
+	sort.Strings(signals)
+	unit, scale := r.config.latencyScale()
+
+	errorsMetric := sm.Metrics().AppendEmpty()
+	errorsMetric.SetName("ztrace.export.errors")
+	errorsMetric.SetDescription("Total number of trace results the next consumer rejected, per signal")
+	errorsMetric.SetUnit("{error}")
+	errorsSum := errorsMetric.SetEmptySum()
+	errorsSum.SetIsMonotonic(true)
+	errorsSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	durationMetric := sm.Metrics().AppendEmpty()
+	durationMetric.SetName("ztrace.export.duration")
+	durationMetric.SetDescription("Time the next consumer took to accept or reject each trace result, per signal")
+	durationMetric.SetUnit(unit)
+	durationHistogram := durationMetric.SetEmptyHistogram()
+	durationHistogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	// Only metrics are queued
+	queued := r.config.ExportRetry.QueueSize > 0 && slices.Contains(signals, signalMetrics)
+	var droppedSum pmetric.Sum
+	if queued {
+		droppedMetric := sm.Metrics().AppendEmpty()
+		droppedMetric.SetName("ztrace.export.dropped")
+		droppedMetric.SetDescription("Total number of metric batches of the target dropped because the export queue was full")
+		droppedMetric.SetUnit("{batch}")
+		droppedSum = droppedMetric.SetEmptySum()
+		droppedSum.SetIsMonotonic(true)
+		droppedSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	}
+
+	bounds := make([]float64, len(exportDurationBounds))
+	for i, bound := range exportDurationBounds {
+		bounds[i] = bound * scale
+	}
+	for _, signal := range signals {
+		counts := r.exports.counts[exportKey{target: targetKey, signal: signal}]
+		start := pcommon.NewTimestampFromTime(counts.start)
+
+		errorsDp := errorsSum.DataPoints().AppendEmpty()
+		errorsDp.SetStartTimestamp(start)
+		errorsDp.SetTimestamp(timestamp)
+		errorsDp.SetIntValue(counts.errors)
+		errorsDp.Attributes().PutStr("signal", signal)
+
+		durationDp := durationHistogram.DataPoints().AppendEmpty()
+		durationDp.SetStartTimestamp(start)
+		durationDp.SetTimestamp(timestamp)
+		durationDp.SetCount(counts.count)
+		durationDp.SetSum(counts.sum * scale)
+		durationDp.SetMin(counts.min * scale)
+		durationDp.SetMax(counts.max * scale)
+		durationDp.ExplicitBounds().FromRaw(bounds)
+		durationDp.BucketCounts().FromRaw(slices.Clone(counts.buckets))
+		durationDp.Attributes().PutStr("signal", signal)
+
+		if queued && signal == signalMetrics {
+			droppedDp := droppedSum.DataPoints().AppendEmpty()
+			droppedDp.SetStartTimestamp(start)
+			droppedDp.SetTimestamp(timestamp)
+			droppedDp.SetIntValue(counts.dropped)
+		}
+	}
+}
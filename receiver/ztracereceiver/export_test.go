@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestExportStats(t *testing.T) {
+	var s exportStats
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.record("example.com", signalMetrics, start, 3*time.Millisecond, nil)
+	s.record("example.com", signalMetrics, start.Add(time.Minute), 700*time.Millisecond, errors.New("queue is full"))
+	s.record("example.com", signalMetrics, start.Add(2*time.Minute), 10*time.Second, nil)
+
+	counts := s.counts[exportKey{target: "example.com", signal: signalMetrics}]
+	assert.Equal(t, start, counts.start)
+	assert.Equal(t, int64(1), counts.errors)
+	assert.Equal(t, uint64(3), counts.count)
+	assert.InDelta(t, 10703.0, counts.sum, 1e-9)
+	assert.InDelta(t, 3.0, counts.min, 1e-9)
+	assert.InDelta(t, 10000.0, counts.max, 1e-9)
+	assert.Equal(t, []uint64{0, 1, 0, 0, 0, 0, 0, 0, 1, 0, 1}, counts.buckets)
+}
+
+func TestRunTraceExportMetrics(t *testing.T) {
+	p := &scriptedProber{answers: map[int][]Hop{1: {{IP: "127.0.0.1", RTTs: []float64{1}}}}}
+	cfg := &Config{Protocol: "icmp", MaxHops: 30, Timeout: 5 * time.Second}
+	sink := new(consumertest.MetricsSink)
+	r := &ztraceReceiver{
+		config:        cfg,
+		settings:      receivertest.NewNopSettings(),
+		consumer:      sink,
+		traceConsumer: consumertest.NewErr(errors.New("exporter queue is full")),
+		tracer:        newScriptedTracer(p),
+	}
+	target := TargetConfig{Endpoint: "127.0.0.1"}
+
+	// The pushes of a trace are reported with the next one
+	r.runTrace(context.Background(), target)
+	p.calls = nil
+	r.runTrace(context.Background(), target)
+	require.Len(t, sink.AllMetrics(), 2)
+
+	_, ok := firstMetric(sink.AllMetrics()[0], "ztrace.export.errors")
+	assert.False(t, ok)
+
+	errorsMetric, ok := firstMetric(sink.AllMetrics()[1], "ztrace.export.errors")
+	require.True(t, ok)
+	exportErrors := map[string]int64{}
+	dps := errorsMetric.Sum().DataPoints()
+	for i := 0; i < dps.Len(); i++ {
+		signal, _ := dps.At(i).Attributes().Get("signal")
+		exportErrors[signal.Str()] = dps.At(i).IntValue()
+	}
+	assert.Equal(t, map[string]int64{"metrics": 0, "traces": 1}, exportErrors)
+
+	durationMetric, ok := firstMetric(sink.AllMetrics()[1], "ztrace.export.duration")
+	require.True(t, ok)
+	durations := durationMetric.Histogram()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, durations.AggregationTemporality())
+	require.Equal(t, 2, durations.DataPoints().Len())
+	for i := 0; i < durations.DataPoints().Len(); i++ {
+		assert.Equal(t, uint64(1), durations.DataPoints().At(i).Count())
+	}
+}
+
+// firstMetric returns the metric of the first resource with the name
+func firstMetric(md pmetric.Metrics, name string) (pmetric.Metric, bool) {
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i), true
+		}
+	}
+	return pmetric.Metric{}, false
+}
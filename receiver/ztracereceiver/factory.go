@@ -5,13 +5,14 @@ package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver/internal/metadata"
 )
 
@@ -22,22 +23,42 @@ func NewFactory() receiver.Factory {
 		createDefaultConfig,
 		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
 		receiver.WithTraces(createTracesReceiver, metadata.TracesStability),
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability),
 	)
 }
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		ServerConfig: confighttp.ServerConfig{
-			Endpoint: "0.0.0.0:8888",
+		CollectionInterval:       60 * time.Second,
+		Timeout:                  10 * time.Second,
+		Protocol:                 "udp",
+		MaxHops:                  30,
+		PacketSize:               56,
+		Retries:                  3,
+		TCPFlags:                 "syn",
+		EnableGeolocation:        true,
+		EnableASNLookup:          true,
+		LookupCacheSize:          4096,
+		LookupCacheTTL:           time.Hour,
+		Source:                   "network",
+		PacketLossEventThreshold: 50,
+		TargetsReloadInterval:    time.Minute,
+		SpanTopology:             "star",
+		SpanMode:                 "span_per_hop",
+		LatencyWindow:            10,
+		LatencySpikeFactor:       3,
+		DNSCacheTTL:              5 * time.Minute,
+		LatencyUnit:              "ms",
+		DeltaLatencyThreshold:    5 * time.Millisecond,
+		MaxDataPoints:            1000,
+		AttributeConvention:      "ztrace",
+		ServiceName:              "ztrace",
+		ScopeName:                "ztrace",
+		ExportRetry: ExportRetryConfig{
+			InitialBackoff: time.Second,
+			MaxBackoff:     5 * time.Second,
 		},
-		CollectionInterval: 60 * time.Second,
-		Timeout:            10 * time.Second,
-		Protocol:           "udp",
-		MaxHops:            30,
-		PacketSize:         56,
-		Retries:            3,
-		EnableGeolocation:  true,
-		EnableASNLookup:    true,
+		TotalLatencyBuckets: slices.Clone(defaultTotalLatencyBuckets),
 	}
 }
 
@@ -47,12 +68,8 @@ func createMetricsReceiver(
 	cfg component.Config,
 	consumer consumer.Metrics,
 ) (receiver.Metrics, error) {
-	zCfg := cfg.(*Config)
-	r := &ztraceReceiver{
-		config:   zCfg,
-		settings: params,
-		consumer: consumer,
-	}
+	r := getOrAddReceiver(cfg.(*Config), params)
+	r.Unwrap().(*ztraceReceiver).consumer = consumer
 	return r, nil
 }
 
@@ -62,11 +79,32 @@ func createTracesReceiver(
 	cfg component.Config,
 	consumer consumer.Traces,
 ) (receiver.Traces, error) {
-	zCfg := cfg.(*Config)
-	r := &ztraceReceiver{
-		config:        zCfg,
-		settings:      params,
-		traceConsumer: consumer,
-	}
+	r := getOrAddReceiver(cfg.(*Config), params)
+	r.Unwrap().(*ztraceReceiver).traceConsumer = consumer
+	return r, nil
+}
+
+func createLogsReceiver(
+	ctx context.Context,
+	params receiver.Settings,
+	cfg component.Config,
+	consumer consumer.Logs,
+) (receiver.Logs, error) {
+	r := getOrAddReceiver(cfg.(*Config), params)
+	r.Unwrap().(*ztraceReceiver).logsConsumer = consumer
 	return r, nil
-}
\ No newline at end of file
+}
+
+// This is the map of already created ztrace receivers for particular configurations.
+// All pipelines of one configuration share a receiver, so every trace runs once and
+// feeds the metrics, traces and logs consumers alike.
+var receivers = sharedcomponent.NewSharedComponents()
+
+func getOrAddReceiver(cfg *Config, params receiver.Settings) *sharedcomponent.SharedComponent {
+	return receivers.GetOrAdd(cfg, func() component.Component {
+		return &ztraceReceiver{
+			config:   cfg,
+			settings: params,
+		}
+	})
+}
@@ -23,7 +23,7 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
 
 	zCfg := cfg.(*Config)
-	assert.Equal(t, "0.0.0.0:8888", zCfg.Endpoint)
+	assert.Empty(t, zCfg.Endpoint, "the status server is opt-in")
 	assert.Equal(t, 60*time.Second, zCfg.CollectionInterval)
 	assert.Equal(t, 10*time.Second, zCfg.Timeout)
 	assert.Equal(t, "udp", zCfg.Protocol)
@@ -32,6 +32,30 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.Equal(t, 3, zCfg.Retries)
 	assert.True(t, zCfg.EnableGeolocation)
 	assert.True(t, zCfg.EnableASNLookup)
+	assert.Equal(t, 50.0, zCfg.PacketLossEventThreshold)
+	assert.Equal(t, "network", zCfg.Source)
+	assert.Equal(t, "star", zCfg.SpanTopology)
+	assert.Equal(t, 10, zCfg.LatencyWindow)
+	assert.Equal(t, 3.0, zCfg.LatencySpikeFactor)
+	assert.Equal(t, 5*time.Minute, zCfg.DNSCacheTTL)
+	assert.Equal(t, "syn", zCfg.TCPFlags)
+	assert.Equal(t, "ms", zCfg.LatencyUnit)
+	assert.Equal(t, 4096, zCfg.LookupCacheSize)
+	assert.Equal(t, time.Hour, zCfg.LookupCacheTTL)
+	assert.Empty(t, zCfg.TargetsFile)
+	assert.Equal(t, time.Minute, zCfg.TargetsReloadInterval)
+	assert.Equal(t, ExportRetryConfig{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}, zCfg.ExportRetry)
+	assert.False(t, zCfg.DeltaMode)
+	assert.Equal(t, 5*time.Millisecond, zCfg.DeltaLatencyThreshold)
+	assert.Zero(t, zCfg.MaxTraceDuration)
+	assert.Zero(t, zCfg.ProbeRateLimit)
+	assert.False(t, zCfg.TotalLatencyHistogram)
+	assert.Equal(t, defaultTotalLatencyBuckets, zCfg.TotalLatencyBuckets)
+	assert.Equal(t, 1000, zCfg.MaxDataPoints)
+	assert.Equal(t, "ztrace", zCfg.AttributeConvention)
+	assert.Equal(t, "ztrace", zCfg.ServiceName)
+	assert.Equal(t, "span_per_hop", zCfg.SpanMode)
+	assert.Equal(t, "ztrace", zCfg.ScopeName)
 }
 
 func TestCreateMetricsReceiver(t *testing.T) {
@@ -86,6 +110,61 @@ func TestCreateTracesReceiver(t *testing.T) {
 	assert.NotNil(t, tReceiver)
 }
 
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := &Config{
+		ServerConfig: confighttp.ServerConfig{
+			Endpoint: "localhost:8080",
+		},
+		Targets: []TargetConfig{
+			{
+				Endpoint: "example.com",
+				Port:     80,
+			},
+		},
+		CollectionInterval: 30 * time.Second,
+		Timeout:            10 * time.Second,
+		Protocol:           "udp",
+		MaxHops:            30,
+		PacketSize:         56,
+		Retries:            3,
+	}
+
+	factory := NewFactory()
+	set := receivertest.NewNopSettings()
+	lReceiver, err := factory.CreateLogs(context.Background(), set, cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, lReceiver)
+}
+
+func TestReceiverSharedAcrossSignals(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Hour
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}}
+
+	factory := NewFactory()
+	set := receivertest.NewNopSettings()
+	metricsSink := new(consumertest.MetricsSink)
+	tracesSink := new(consumertest.TracesSink)
+	mReceiver, err := factory.CreateMetrics(context.Background(), set, cfg, metricsSink)
+	require.NoError(t, err)
+	tReceiver, err := factory.CreateTraces(context.Background(), set, cfg, tracesSink)
+	require.NoError(t, err)
+	assert.Same(t, mReceiver, tReceiver)
+
+	// Both pipelines start and stop the same receiver, which traces the target once
+	require.NoError(t, mReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(metricsSink.AllMetrics()) == 1 && len(tracesSink.AllTraces()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, mReceiver.Shutdown(context.Background()))
+	require.NoError(t, tReceiver.Shutdown(context.Background()))
+
+	assert.Len(t, metricsSink.AllMetrics(), 1)
+	assert.Len(t, tracesSink.AllTraces(), 1)
+}
+
 func TestCreateReceiverWithInvalidConfig(t *testing.T) {
 	cfg := &Config{
 		ServerConfig: confighttp.ServerConfig{
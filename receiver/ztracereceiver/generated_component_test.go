@@ -29,6 +29,13 @@ func TestComponentLifecycle(t *testing.T) {
 				return factory.CreateTraces(ctx, ctx.Setting, cfg, ctx.Sink)
 			},
 		},
+		{
+			name: "logs",
+			createFn: func(ctx componenttest.TestContext) (component.Component, error) {
+				cfg := factory.CreateDefaultConfig()
+				return factory.CreateLogs(ctx, ctx.Setting, cfg, ctx.Sink)
+			},
+		},
 	}
 
 	for _, test := range tests {
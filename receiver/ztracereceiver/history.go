@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"math"
+	"slices"
+	"sync"
+)
+
+// minBaselineCycles is the number of previous cycles a hop needs before it is compared to its baseline
+const minBaselineCycles = 3
+
+// hopKey identifies a hop of a target across collection cycles
+type hopKey struct {
+	target string
+	ttl    int
+	ip     string
+}
+
+// hopSamples are the latencies of one hop over the last cycles, oldest first
+type hopSamples struct {
+	latencies []float64
+	lastCycle int
+}
+
+// hopHistory keeps the latency of every hop over the last size cycles, to compare hops against their own past
+type hopHistory struct {
+	size int
+
+	mu     sync.Mutex
+	cycles map[string]int
+	hops   map[hopKey]*hopSamples
+}
+
+func newHopHistory(size int) *hopHistory {
+	return &hopHistory{
+		size:   size,
+		cycles: make(map[string]int),
+		hops:   make(map[hopKey]*hopSamples),
+	}
+}
+
+// record adds the latency of every answering hop of a trace and forgets the hops of
+// the target that have not answered within the window
+func (h *hopHistory) record(target string, result *Result) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cycles[target]++
+	cycle := h.cycles[target]
+	for _, hop := range result.Hops {
+		if hop.IP == "" {
+			continue
+		}
+		key := hopKey{target: target, ttl: hop.TTL, ip: hop.IP}
+		samples, ok := h.hops[key]
+		if !ok {
+			samples = &hopSamples{}
+			h.hops[key] = samples
+		}
+		samples.latencies = append(samples.latencies, hop.Latency)
+		if len(samples.latencies) > h.size {
+			samples.latencies = samples.latencies[len(samples.latencies)-h.size:]
+		}
+		samples.lastCycle = cycle
+	}
+
+	for key, samples := range h.hops {
+		if key.target == target && cycle-samples.lastCycle >= h.size {
+			delete(h.hops, key)
+		}
+	}
+}
+
+// latencies returns a copy of the recorded latencies of a hop, oldest first
+func (h *hopHistory) latencies(key hopKey) []float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	samples, ok := h.hops[key]
+	if !ok {
+		return nil
+	}
+	return append([]float64(nil), samples.latencies...)
+}
+
+// median returns the median of values
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// stddev returns the population standard deviation of values
+func stddev(values []float64) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
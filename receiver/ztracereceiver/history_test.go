@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHopHistory(t *testing.T) {
+	h := newHopHistory(3)
+	gateway := hopKey{target: "example.com", ttl: 1, ip: "192.168.1.1"}
+	isp := hopKey{target: "example.com", ttl: 2, ip: "10.0.0.1"}
+
+	for _, latency := range []float64{1, 2, 3, 4} {
+		h.record("example.com", &Result{Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: latency},
+			{TTL: 2}, // timeout
+		}})
+	}
+	assert.Equal(t, []float64{2, 3, 4}, h.latencies(gateway))
+	assert.Nil(t, h.latencies(hopKey{target: "example.com", ttl: 2}))
+
+	// A hop that stops answering is forgotten once it left the window
+	h.record("example.com", &Result{Hops: []Hop{{TTL: 2, IP: "10.0.0.1", Latency: 10}}})
+	h.record("example.com", &Result{Hops: []Hop{{TTL: 2, IP: "10.0.0.1", Latency: 10}}})
+	assert.Equal(t, []float64{2, 3, 4}, h.latencies(gateway))
+	h.record("example.com", &Result{Hops: []Hop{{TTL: 2, IP: "10.0.0.1", Latency: 10}}})
+	assert.Nil(t, h.latencies(gateway))
+	assert.Equal(t, []float64{10, 10, 10}, h.latencies(isp))
+}
+
+func TestStddev(t *testing.T) {
+	assert.InDelta(t, 2.0, stddev([]float64{2, 4, 4, 4, 5, 5, 7, 9}), 1e-9)
+	assert.Zero(t, stddev([]float64{3, 3}))
+}
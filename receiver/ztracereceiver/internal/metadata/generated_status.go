@@ -10,4 +10,5 @@ var (
 	Type               = component.MustNewType("ztrace")
 	TracesStability    = component.StabilityLevelAlpha
 	MetricsStability   = component.StabilityLevelAlpha
+	LogsStability      = component.StabilityLevelAlpha
 )
\ No newline at end of file
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lookupAnswer is a cached answer of a geolocation or ASN provider
+type lookupAnswer struct {
+	name    string // city or ASN
+	detail  string // country or provider
+	cached  bool
+	expires time.Time
+}
+
+// lookupEntry holds the answers of both providers for one address
+type lookupEntry struct {
+	ip  string
+	geo lookupAnswer
+	asn lookupAnswer
+}
+
+// lookupCache is a least recently used cache of enrichment answers by hop address, shared by the
+// geolocation and ASN providers and by every target. Backbone routers show up on the paths to many
+// targets, caching them saves lookups and provider quota. Failed lookups are not cached.
+type lookupCache struct {
+	size int
+	ttl  time.Duration // 0 keeps answers until they are evicted
+
+	mu      sync.Mutex
+	order   *list.List // of *lookupEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+func newLookupCache(size int, ttl time.Duration) *lookupCache {
+	return &lookupCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached answer of the kind of lookup for ip while it has not expired at now
+func (c *lookupCache) get(kind, ip string, now time.Time) (string, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[ip]
+	if !ok {
+		return "", "", false
+	}
+	answer := c.answer(element.Value.(*lookupEntry), kind)
+	if !answer.cached || (c.ttl > 0 && !now.Before(answer.expires)) {
+		return "", "", false
+	}
+	c.order.MoveToFront(element)
+	return answer.name, answer.detail, true
+}
+
+// put caches the answer of the kind of lookup for ip, evicting the least recently used address when full
+func (c *lookupCache) put(kind, ip, name, detail string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[ip]
+	if ok {
+		c.order.MoveToFront(element)
+	} else {
+		if c.order.Len() >= c.size {
+			oldest := c.order.Back()
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*lookupEntry).ip)
+		}
+		element = c.order.PushFront(&lookupEntry{ip: ip})
+		c.entries[ip] = element
+	}
+	answer := c.answer(element.Value.(*lookupEntry), kind)
+	*answer = lookupAnswer{name: name, detail: detail, cached: true}
+	if c.ttl > 0 {
+		answer.expires = now.Add(c.ttl)
+	}
+}
+
+func (c *lookupCache) answer(entry *lookupEntry, kind string) *lookupAnswer {
+	if kind == enrichmentGeo {
+		return &entry.geo
+	}
+	return &entry.asn
+}
@@ -0,0 +1,48 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCacheEviction(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newLookupCache(2, 0)
+	c.put(enrichmentGeo, "198.51.100.1", "Berlin", "Germany", now)
+	c.put(enrichmentASN, "198.51.100.1", "AS64501", "Example ISP", now)
+	c.put(enrichmentGeo, "198.51.100.2", "Paris", "France", now)
+
+	// Both lookups of an address share its entry, using it makes it the most recent one
+	city, country, ok := c.get(enrichmentGeo, "198.51.100.1", now)
+	assert.True(t, ok)
+	assert.Equal(t, "Berlin", city)
+	assert.Equal(t, "Germany", country)
+	_, _, ok = c.get(enrichmentASN, "198.51.100.2", now)
+	assert.False(t, ok, "only the geolocation of the address was cached")
+
+	// The least recently used address is evicted
+	c.put(enrichmentGeo, "198.51.100.3", "Madrid", "Spain", now)
+	_, _, ok = c.get(enrichmentGeo, "198.51.100.2", now)
+	assert.False(t, ok)
+	asn, provider, ok := c.get(enrichmentASN, "198.51.100.1", now)
+	assert.True(t, ok)
+	assert.Equal(t, "AS64501", asn)
+	assert.Equal(t, "Example ISP", provider)
+	assert.Equal(t, 2, c.order.Len())
+}
+
+func TestLookupCacheTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newLookupCache(16, time.Hour)
+	c.put(enrichmentASN, "198.51.100.1", "AS64501", "Example ISP", now)
+
+	_, _, ok := c.get(enrichmentASN, "198.51.100.1", now.Add(59*time.Minute))
+	assert.True(t, ok)
+	_, _, ok = c.get(enrichmentASN, "198.51.100.1", now.Add(time.Hour))
+	assert.False(t, ok, "expired answers are looked up again")
+}
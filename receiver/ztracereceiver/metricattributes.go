@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// hopMetricAttributes are the hop attributes metric_attributes selects, the ones describing the
+// router that answered. The other data point attributes, e.g. ttl, port or signal, tell the
+// series apart and are always kept.
+var hopMetricAttributes = []string{
+	"ip", "hostname", "city", "country", "asn", "provider", "ztrace.hop.scope", "ztrace.hop.reply_protocol",
+}
+
+// filter removes the hop attributes the config does not keep from every metric. The resource
+// attributes identify the target and are always kept.
+func (c MetricAttributesConfig) filter(md pmetric.Metrics) {
+	if len(c.Include) == 0 && len(c.Exclude) == 0 {
+		return
+	}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				c.filterMetric(metrics.At(k))
+			}
+		}
+	}
+}
+
+// filterMetric removes the attributes of the data points of the metric types the receiver emits
+func (c MetricAttributesConfig) filterMetric(metric pmetric.Metric) {
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			c.filterAttributes(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			c.filterAttributes(dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			c.filterAttributes(dps.At(i).Attributes())
+		}
+	}
+}
+
+func (c MetricAttributesConfig) filterAttributes(attrs pcommon.Map) {
+	attrs.RemoveIf(func(name string, _ pcommon.Value) bool {
+		if !slices.Contains(hopMetricAttributes, name) {
+			return false
+		}
+		if len(c.Include) > 0 {
+			return !slices.Contains(c.Include, name)
+		}
+		return slices.Contains(c.Exclude, name)
+	})
+}
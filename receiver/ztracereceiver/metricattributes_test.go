@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestMetricAttributes(t *testing.T) {
+	r := &ztraceReceiver{
+		config: &Config{
+			Protocol:            "udp",
+			EnableGeolocation:   true,
+			EnableASNLookup:     true,
+			HopLatencyHistogram: true,
+			MetricAttributes:    MetricAttributesConfig{Exclude: []string{"hostname", "ip"}},
+		},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops: []Hop{{
+			TTL: 1, IP: "8.8.8.8", Hostname: "dns.google", Latency: 12.5, RTTs: []float64{12.5},
+			City: "Mountain View", Country: "United States", ASN: "AS15169", Provider: "Google LLC",
+		}},
+		TotalLatency:  12.5,
+		TargetReached: true,
+		ResolvedIP:    "8.8.8.8",
+	}
+	target := TargetConfig{Endpoint: "8.8.8.8"}
+
+	// Denied attributes are dropped from every data point, the others and the resource are kept
+	md := r.convertToMetrics(result, target)
+	latency, ok := firstMetric(md, "ztrace.hop.latency")
+	require.True(t, ok)
+	attrs := latency.Gauge().DataPoints().At(0).Attributes()
+	for _, name := range []string{"hostname", "ip"} {
+		_, ok = attrs.Get(name)
+		assert.False(t, ok, name)
+	}
+	for _, name := range []string{"ttl", "city", "asn"} {
+		_, ok = attrs.Get(name)
+		assert.True(t, ok, name)
+	}
+	rtt, ok := firstMetric(md, "ztrace.hop.rtt")
+	require.True(t, ok)
+	_, ok = rtt.Histogram().DataPoints().At(0).Attributes().Get("ip")
+	assert.False(t, ok)
+	_, ok = md.ResourceMetrics().At(0).Resource().Attributes().Get("ztrace.target")
+	assert.True(t, ok)
+
+	// Traces keep every attribute
+	hopSpan := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1)
+	_, ok = hopSpan.Attributes().Get("hostname")
+	assert.True(t, ok)
+
+	// An allow-list keeps only the listed hop attributes and every other attribute
+	r.config.MetricAttributes = MetricAttributesConfig{Include: []string{"asn"}}
+	md = r.convertToMetrics(result, target)
+	latency, ok = firstMetric(md, "ztrace.hop.latency")
+	require.True(t, ok)
+	var names []string
+	for name := range latency.Gauge().DataPoints().At(0).Attributes().AsRaw() {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"ttl", "asn"}, names)
+	state, ok := firstMetric(md, "ztrace.hop.state")
+	require.True(t, ok)
+	assert.Equal(t, map[string]any{"ttl": int64(1)}, state.Gauge().DataPoints().At(0).Attributes().AsRaw())
+}
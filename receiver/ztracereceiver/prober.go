@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// prober sends a single probe with the given TTL towards dst and reports the hop that answered it.
+// A probe that times out is not an error, it yields a Hop without IP.
+// Probers holding network resources also implement io.Closer, closing them aborts pending probes.
+type prober interface {
+	ProbeHop(ttl int, dst net.IP) (Hop, error)
+}
+
+// portProber is implemented by probers whose probes are addressed to a port. forPort returns a
+// prober sending the same probes to port, e.g. to sweep the ports of a target.
+type portProber interface {
+	forPort(port int) prober
+}
+
+// Values of Hop.ReplyProtocol, the kind of reply a probe elicited
+const (
+	replyICMPTimeExceeded    = "icmp_time_exceeded"    // from a router the TTL expired at
+	replyICMPEchoReply       = "icmp_echo_reply"       // from the target of an ICMP probe
+	replyICMPPortUnreachable = "icmp_port_unreachable" // from the target of a UDP probe
+	replyTCPSynAck           = "tcp_syn_ack"           // from the target of a TCP SYN probe to an open port
+	replyTCPRst              = "tcp_rst"               // from the target of a TCP ACK probe, it has no such connection
+	replyTCPConnect          = "tcp_connect"           // a connection through a proxy, the reply itself is not seen
+)
+
+// newProber returns the prober for the given protocol, tcp probes are sent with tcpFlags
+func newProber(protocol, tcpFlags string) (prober, error) {
+	switch protocol {
+	case "icmp", "udp", "udp-icmp":
+		return &simulatedProber{protocol: protocol}, nil
+	case "tcp":
+		return &simulatedProber{protocol: protocol, tcpFlags: tcpFlags}, nil
+	default:
+		return nil, fmt.Errorf("unsupported protocol: %s", protocol)
+	}
+}
+
+// simulatedProber answers probes from a fixed route instead of the network
+type simulatedProber struct {
+	protocol string
+	tcpFlags string // "syn" or "ack", empty is "syn"
+	port     int    // destination port of tcp and udp probes, 0 for the protocol default
+}
+
+func (p *simulatedProber) forPort(port int) prober {
+	return &simulatedProber{protocol: p.protocol, tcpFlags: p.tcpFlags, port: port}
+}
+
+// targetReply is the kind of reply the target answers a probe of the protocol with
+func (p *simulatedProber) targetReply() string {
+	switch p.protocol {
+	case "icmp":
+		return replyICMPEchoReply
+	case "tcp":
+		if p.tcpFlags == "ack" {
+			return replyTCPRst
+		}
+		return replyTCPSynAck
+	default:
+		return replyICMPPortUnreachable
+	}
+}
+
+func (p *simulatedProber) ProbeHop(ttl int, dst net.IP) (Hop, error) {
+	// This is a simplified simulation
+	// In a real implementation, this would send a packet with the TTL set
+	// and listen for the ICMP Time Exceeded message
+	hop := Hop{
+		TTL: ttl,
+	}
+
+	var rtt float64
+	switch {
+	case ttl <= 3:
+		// Local network hops
+		hop.IP = fmt.Sprintf("192.168.1.%d", ttl)
+		rtt = float64(rand.Intn(5) + 1)
+		hop.Hostname = fmt.Sprintf("router-%d.local", ttl)
+	case ttl <= 8:
+		// ISP hops
+		hop.IP = fmt.Sprintf("10.%d.%d.1", ttl, ttl*10)
+		rtt = float64(rand.Intn(20) + 5)
+		hop.Hostname = fmt.Sprintf("isp-router-%d.example.net", ttl)
+		hop.ASN = fmt.Sprintf("AS%d", 64500+ttl)
+		hop.Provider = "Example ISP"
+	case ttl <= 12:
+		// Internet backbone
+		hop.IP = fmt.Sprintf("203.0.%d.1", ttl)
+		rtt = float64(rand.Intn(50) + 20)
+		hop.City = "San Francisco"
+		hop.Country = "United States"
+		hop.ASN = fmt.Sprintf("AS%d", 15169) // Google's ASN
+		hop.Provider = "Google LLC"
+	case ttl >= 15:
+		// Target
+		hop.IP = dst.String()
+		rtt = float64(rand.Intn(100) + 50)
+		hop.Hostname = "target.example.com"
+		hop.City = "Mountain View"
+		hop.Country = "United States"
+		hop.ReplyProtocol = p.targetReply()
+	default:
+		// Timeout
+		return hop, nil
+	}
+	if hop.ReplyProtocol == "" {
+		hop.ReplyProtocol = replyICMPTimeExceeded
+	}
+
+	rtt += rand.Float64() * rtt * 0.2
+	hop.Latency = rtt
+	// Replies come back over the same path, from hosts starting at TTL 64
+	hop.ReplyTTL = 64 - ttl + 1
+	hop.RTTs = []float64{rtt}
+	return hop, nil
+}
+
+// connectProber measures the TCP connect latency to one address through a SOCKS5 proxy.
+// TTLs are not honored through a proxy, every probe reaches the destination or fails.
+type connectProber struct {
+	ctx     context.Context
+	dialer  *socks5Dialer
+	source  net.IP // local address the proxy is connected from, nil lets the OS choose
+	address string
+	timeout time.Duration // bounds each probe when positive
+	clock   clock         // times the connect
+}
+
+func (p *connectProber) ProbeHop(ttl int, dst net.IP) (Hop, error) {
+	hop := Hop{
+		TTL: ttl,
+	}
+
+	ctx := p.ctx
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	start := p.clock.Now()
+	conn, err := p.dialer.dialFrom(ctx, p.source, p.address)
+	var replyErr *socks5ReplyError
+	if errors.As(err, &replyErr) {
+		// The destination did not accept the connection
+		return hop, nil
+	}
+	var netErr net.Error
+	if err != nil && p.ctx.Err() == nil && (ctx.Err() != nil || errors.As(err, &netErr) && netErr.Timeout()) {
+		// Only this probe timed out, the trace goes on
+		return hop, nil
+	}
+	if err != nil {
+		return hop, err
+	}
+	rtt := float64(p.clock.Now().Sub(start).Microseconds()) / 1000
+	conn.Close()
+
+	hop.IP = dst.String()
+	hop.Latency = rtt
+	hop.RTTs = []float64{rtt}
+	hop.ReplyProtocol = replyTCPConnect
+	return hop, nil
+}
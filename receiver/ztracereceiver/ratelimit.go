@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"sync"
+	"time"
+)
+
+// probeRateWindow is the period the ztrace.probe.rate gauge averages the sent probes over
+const probeRateWindow = 10 * time.Second
+
+// probeLimiter spaces the probes of all targets evenly to stay under probe_rate_limit. It is a
+// token bucket holding a single token, a probe reserves the next free slot and waits for it.
+type probeLimiter struct {
+	interval time.Duration // between two probes
+
+	mu   sync.Mutex
+	next time.Time // earliest time the next probe may be sent
+
+	// Probes sent in the current window, and the rate of the last full one
+	windowStart  time.Time
+	windowProbes int64
+	lastRate     float64
+	fullWindow   bool
+}
+
+// newProbeLimiter returns a limiter sending at most limit probes per second
+func newProbeLimiter(limit float64) *probeLimiter {
+	return &probeLimiter{interval: time.Duration(float64(time.Second) / limit)}
+}
+
+// reserve takes the next free slot for a probe and returns how long to wait from now before sending it
+func (l *probeLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+	l.next = at.Add(l.interval)
+
+	if l.windowStart.IsZero() {
+		l.windowStart = at
+	}
+	l.roll(at)
+	l.windowProbes++
+	return at.Sub(now)
+}
+
+// rate returns the probes sent per second over the last full window, or over the current one
+// until a window is full
+func (l *probeLimiter) rate(now time.Time) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.roll(now)
+	if l.fullWindow {
+		return l.lastRate
+	}
+	elapsed := now.Sub(l.windowStart)
+	if l.windowStart.IsZero() || elapsed <= 0 {
+		return 0
+	}
+	return float64(l.windowProbes) / elapsed.Seconds()
+}
+
+// roll starts a new window when the current one is full
+func (l *probeLimiter) roll(now time.Time) {
+	if l.windowStart.IsZero() {
+		return
+	}
+	if elapsed := now.Sub(l.windowStart); elapsed >= probeRateWindow {
+		l.lastRate = float64(l.windowProbes) / elapsed.Seconds()
+		l.fullWindow = true
+		l.windowStart = now
+		l.windowProbes = 0
+	}
+}
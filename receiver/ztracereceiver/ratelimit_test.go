@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestProbeLimiterReserve(t *testing.T) {
+	l := newProbeLimiter(4)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Probes of concurrent traces queue up behind each other
+	assert.Zero(t, l.reserve(start))
+	assert.Equal(t, 250*time.Millisecond, l.reserve(start))
+	assert.Equal(t, 500*time.Millisecond, l.reserve(start))
+
+	// A probe after a pause does not make up for the unused slots
+	assert.Zero(t, l.reserve(start.Add(5*time.Second)))
+	assert.Equal(t, 250*time.Millisecond, l.reserve(start.Add(5*time.Second)))
+}
+
+func TestProbeLimiterRate(t *testing.T) {
+	l := newProbeLimiter(10)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Zero(t, l.rate(start))
+
+	// Until the window is full the rate is averaged over the elapsed time
+	for i := 0; i < 10; i++ {
+		l.reserve(start.Add(time.Duration(i) * 100 * time.Millisecond))
+	}
+	assert.InDelta(t, 5.0, l.rate(start.Add(2*time.Second)), 1e-9)
+
+	// Then over the last full window
+	for i := 0; i < 20; i++ {
+		l.reserve(start.Add(5*time.Second + time.Duration(i)*100*time.Millisecond))
+	}
+	assert.InDelta(t, 3.0, l.rate(start.Add(probeRateWindow)), 1e-9)
+	assert.InDelta(t, 3.0, l.rate(start.Add(probeRateWindow+time.Second)), 1e-9)
+
+	// An idle window brings the rate down
+	assert.Zero(t, l.rate(start.Add(2*probeRateWindow)))
+}
+
+// notifyingProber reports each probed TTL before answering it like a scriptedProber
+type notifyingProber struct {
+	scriptedProber
+	probed chan int
+}
+
+func (p *notifyingProber) ProbeHop(ttl int, dst net.IP) (Hop, error) {
+	p.probed <- ttl
+	return p.scriptedProber.ProbeHop(ttl, dst)
+}
+
+func TestTraceProbeRateLimit(t *testing.T) {
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	p := &notifyingProber{
+		scriptedProber: scriptedProber{answers: map[int][]Hop{
+			1: {{IP: "192.168.1.1", RTTs: []float64{1}}},
+			2: {{IP: "127.0.0.1", RTTs: []float64{2}}},
+		}},
+		probed: make(chan int, 2),
+	}
+	tr := newScriptedTracer(p)
+	tr.clock = clk
+	tr.limiter = newProbeLimiter(1)
+	cfg := &Config{Protocol: "icmp", MaxHops: 30, ProbeRateLimit: 1}
+
+	done := make(chan *Result)
+	go func() {
+		result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+		assert.NoError(t, err)
+		done <- result
+	}()
+
+	// The second probe waits a second for its slot
+	assert.Equal(t, 1, <-p.probed)
+	require.Eventually(t, func() bool { return clk.tickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Empty(t, p.probed)
+	clk.Advance(time.Second)
+	assert.Equal(t, 2, <-p.probed)
+
+	result := <-done
+	assert.True(t, result.TargetReached)
+	assert.InDelta(t, 2.0, result.ProbeRate, 1e-9)
+}
+
+func TestConvertProbeRate(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", ProbeRateLimit: 50},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{ProbeRate: 42.5}
+
+	rate, ok := firstMetric(r.convertToMetrics(result, TargetConfig{Endpoint: "127.0.0.1"}), "ztrace.probe.rate")
+	require.True(t, ok)
+	assert.InDelta(t, 42.5, rate.Gauge().DataPoints().At(0).DoubleValue(), 1e-9)
+
+	// Without the limit the rate is not tracked
+	r.config.ProbeRateLimit = 0
+	_, ok = firstMetric(r.convertToMetrics(result, TargetConfig{Endpoint: "127.0.0.1"}), "ztrace.probe.rate")
+	assert.False(t, ok)
+}
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rawWriter appends every trace result as one JSON line to a file
+type rawWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// newRawWriter opens path for appending, creating it if needed
+func newRawWriter(path string) (*rawWriter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raw output file: %w", err)
+	}
+	return &rawWriter{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// write appends the result, concurrent traces of different targets are written as separate lines
+func (w *rawWriter) write(result *Result) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.encoder.Encode(result)
+}
+
+func (w *rawWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
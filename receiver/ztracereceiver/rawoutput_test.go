@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestRawOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Hour
+	cfg.RawOutputPath = path
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}, {Endpoint: "127.0.0.2"}}
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && strings.Count(string(data), "\n") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	targets := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var result Result
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &result))
+		targets[result.Target] = result.TargetReached
+		assert.NotEmpty(t, result.Hops)
+		assert.False(t, result.Timestamp.IsZero())
+	}
+	assert.Equal(t, map[string]bool{"127.0.0.1": true, "127.0.0.2": true}, targets)
+}
+
+func TestRawOutputNotWritable(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.RawOutputPath = filepath.Join(t.TempDir(), "missing", "results.ndjson")
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+
+	assert.ErrorContains(t, r.Start(context.Background(), componenttest.NewNopHost()), "failed to open raw output file")
+}
@@ -5,13 +5,20 @@ package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver"
@@ -23,86 +30,330 @@ type ztraceReceiver struct {
 	settings      receiver.Settings
 	consumer      consumer.Metrics
 	traceConsumer consumer.Traces
-	stopCh        chan struct{}
-	stopOnce      sync.Once
+	logsConsumer  consumer.Logs
 	wg            sync.WaitGroup
 	tracer        *tracer
+
+	// targetsFile loads the targets of targets_file, nil without it
+	targetsFile *targetsFile
+
+	// running holds the cancel func stopping the collect loop of every target being traced by key,
+	// fileTargets the targets last loaded from targets_file. Once stopped no target is started.
+	runningMu   sync.Mutex
+	running     map[string]context.CancelFunc
+	stopWatch   context.CancelFunc
+	stopped     bool
+	fileTargets []TargetConfig
+
+	// cancel releases the context derived from the Start context the collect loops run with
+	cancel context.CancelFunc
+
+	// clock defaults to the system clock, tests inject a fake
+	clock clock
+
+	// asPaths holds the last AS path seen per target, to report path changes in logs
+	asPathsMu sync.Mutex
+	asPaths   map[string]string
+
+	// history holds the recent latencies of every hop, nil when latency_window is 0
+	history *hopHistory
+
+	// deltas holds the last emitted hops of every target, nil without delta_mode
+	deltas *hopDeltas
+
+	// latencies accumulates the total latency of every target, nil without total_latency_histogram
+	latencies *totalLatencies
+
+	// anycast holds the final hop of every target, to flag targets answered from changing locations
+	anycast *anycastDetector
+
+	// exports counts the pushes of the trace results to the next consumers
+	exports exportStats
+
+	// queue holds the metrics the next consumer rejected per target key, for export_retry
+	queueMu sync.Mutex
+	queue   map[string][]pmetric.Metrics
+
+	// sourceHost is the hostname of the probing host, set when include_source_host is enabled
+	sourceHost string
+
+	// rawOutput receives every trace result, nil without raw_output_path
+	rawOutput *rawWriter
+
+	// server serves the health and status endpoints, nil without endpoint
+	server   *http.Server
+	listener net.Listener
+
+	// results holds the last result per target for the status endpoints
+	resultsMu sync.Mutex
+	results   map[string]*Result
 }
 
 func (r *ztraceReceiver) Start(ctx context.Context, host component.Host) error {
-	r.stopCh = make(chan struct{})
+	if r.clock == nil {
+		r.clock = realClock{}
+	}
 	
 	// Initialize the tracer with the configured protocol
 	var err error
-	r.tracer, err = newTracer(r.config.Protocol, r.settings.Logger)
+	r.tracer, err = newTracer(r.config, r.settings.Logger)
 	if err != nil {
 		return fmt.Errorf("failed to create tracer: %w", err)
 	}
+	// Cumulative counts start with the receiver, on the receiver clock
+	r.tracer.startTime = r.clock.Now()
+	r.tracer.setClock(r.clock)
+	if r.config.LatencyWindow > 0 {
+		r.history = newHopHistory(r.config.LatencyWindow)
+	}
+	if r.config.DeltaMode {
+		r.deltas = newHopDeltas(float64(r.config.DeltaLatencyThreshold) / float64(time.Millisecond))
+	}
+	if r.config.TotalLatencyHistogram {
+		r.latencies = newTotalLatencies(r.config.TotalLatencyBuckets)
+	}
+	r.anycast = newAnycastDetector()
+	if r.config.IncludeSourceHost {
+		r.sourceHost, err = os.Hostname()
+		if err != nil {
+			return fmt.Errorf("failed to get source hostname: %w", err)
+		}
+	}
+	if r.config.RawOutputPath != "" {
+		r.rawOutput, err = newRawWriter(r.config.RawOutputPath)
+		if err != nil {
+			return err
+		}
+	}
+	if r.config.Endpoint != "" {
+		if err := r.startServer(ctx, host); err != nil {
+			return err
+		}
+	}
 
-	// Start collection goroutines for each target
-	for _, target := range r.config.Targets {
+	// Probing stops when the host cancels the Start context, as well as on Shutdown
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	// Start collection goroutines for each enabled target
+	targets := r.config.Targets
+	if r.config.TargetsFile != "" {
+		r.targetsFile = newTargetsFile(r.config, r.settings.Logger)
+		fileTargets := r.targetsFile.load()
+		r.setFileTargets(fileTargets)
+		r.addFileTargetProbers(fileTargets)
+		targets = append(slices.Clone(targets), fileTargets...)
+	}
+	enabled, _ := r.reconcileTargets(ctx, targets)
+	if r.targetsFile != nil {
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		r.runningMu.Lock()
+		r.stopWatch = stopWatch
+		r.runningMu.Unlock()
 		r.wg.Add(1)
-		go r.collect(target)
+		go r.watchTargetsFile(ctx, watchCtx.Done())
 	}
 
 	r.settings.Logger.Info("ztrace receiver started",
-		zap.Int("targets", len(r.config.Targets)),
+		zap.Int("targets", enabled),
 		zap.String("protocol", r.config.Protocol))
 
 	return nil
 }
 
+// reconcileTargets makes the running collection goroutines match the enabled targets: targets
+// not traced yet are started and traced targets missing from targets are stopped. It returns the
+// number of targets started and stopped. Every collect loop has its own context, cancelled to stop it
+// along with its trace in progress.
+func (r *ztraceReceiver) reconcileTargets(ctx context.Context, targets []TargetConfig) (started, stopped int) {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	if r.stopped {
+		return 0, 0
+	}
+	if r.running == nil {
+		r.running = make(map[string]context.CancelFunc)
+	}
+
+	desired := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		if !target.enabled() {
+			r.settings.Logger.Debug("Skipping disabled target", zap.String("target", target.Endpoint))
+			continue
+		}
+		key := r.config.targetKey(target)
+		desired[key] = true
+		if _, ok := r.running[key]; ok {
+			continue
+		}
+		targetCtx, cancel := context.WithCancel(ctx)
+		r.running[key] = cancel
+		started++
+		r.wg.Add(1)
+		go r.collect(targetCtx, r.config.resolveTarget(target))
+	}
+
+	for key, cancel := range r.running {
+		if desired[key] {
+			continue
+		}
+		r.settings.Logger.Debug("Stopping removed target", zap.String("target", key))
+		cancel()
+		delete(r.running, key)
+		stopped++
+	}
+	return started, stopped
+}
+
+// stopTargets stops the collect loops of all targets and the targets_file watcher. Traces in
+// progress are cancelled, the loops return once they did.
+func (r *ztraceReceiver) stopTargets() {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	r.stopped = true
+	for key, cancel := range r.running {
+		cancel()
+		delete(r.running, key)
+	}
+	if r.stopWatch != nil {
+		r.stopWatch()
+	}
+}
+
 func (r *ztraceReceiver) Shutdown(ctx context.Context) error {
-	r.stopOnce.Do(func() {
-		close(r.stopCh)
-	})
-	r.wg.Wait()
-	
-	if r.tracer != nil {
-		r.tracer.close()
+	r.stopTargets()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	// A hung probe must not block the collector shutdown, closing the tracer aborts it
+	select {
+	case <-done:
+	case <-ctx.Done():
+		r.closeResources()
+		r.settings.Logger.Warn("ztrace receiver stopped before in-flight traces completed")
+		return ctx.Err()
 	}
+
+	r.closeResources()
 	
 	r.settings.Logger.Info("ztrace receiver stopped")
 	return nil
 }
 
-func (r *ztraceReceiver) collect(target TargetConfig) {
+func (r *ztraceReceiver) closeResources() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.tracer != nil {
+		r.tracer.close()
+	}
+	if r.rawOutput != nil {
+		if err := r.rawOutput.close(); err != nil {
+			r.settings.Logger.Warn("Failed to close raw output file", zap.Error(err))
+		}
+	}
+	if r.server != nil {
+		if err := r.server.Close(); err != nil {
+			r.settings.Logger.Warn("Failed to close HTTP server", zap.Error(err))
+		}
+	}
+}
+
+// collect traces the target every collection interval until shutdown or until stop is closed
+func (r *ztraceReceiver) collect(ctx context.Context, target TargetConfig) {
 	defer r.wg.Done()
 
-	ticker := time.NewTicker(r.config.CollectionInterval)
+	ticker := r.clock.NewTicker(r.config.forTarget(target).CollectionInterval)
 	defer ticker.Stop()
 
 	// Run immediately on start
-	r.runTrace(target)
+	r.runTrace(ctx, target)
 
 	for {
 		select {
-		case <-ticker.C:
-			r.runTrace(target)
-		case <-r.stopCh:
+		case <-ticker.C():
+			r.runTrace(ctx, target)
+		case <-ctx.Done():
+			r.settings.Logger.Debug("Stopping collection", zap.String("target", target.Endpoint))
 			return
 		}
 	}
 }
 
-func (r *ztraceReceiver) runTrace(target TargetConfig) {
-	ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+func (r *ztraceReceiver) runTrace(ctx context.Context, target TargetConfig) {
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
 	defer cancel()
 
 	r.settings.Logger.Debug("Running trace", zap.String("target", target.Endpoint))
 
-	result, err := r.tracer.trace(ctx, target, r.config)
+	// A panic while tracing one target must not stop probing it or the other targets
+	defer func() {
+		if p := recover(); p != nil {
+			r.settings.Logger.Error("Trace panicked",
+				zap.String("target", target.Endpoint),
+				zap.Any("panic", p),
+				zap.Stack("stack"))
+			if r.consumer != nil {
+				metrics := r.convertPanicToMetrics(target, r.tracer.countPanic(r.config.targetKey(target)))
+				if err := r.pushMetrics(ctx, target, metrics); err != nil {
+					r.settings.Logger.Error("Failed to consume metrics", zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	result, err := r.tracer.trace(ctx, target, r.config.forTarget(target))
+	if errors.Is(err, context.Canceled) {
+		r.settings.Logger.Debug("Trace cancelled, the target was stopped", zap.String("target", target.Endpoint))
+		return
+	}
 	if err != nil {
 		r.settings.Logger.Error("Failed to trace target",
 			zap.String("target", target.Endpoint),
 			zap.Error(err))
+
+		// Report DNS outages as an unreachable target, the next tick resolves again
+		var resolveErr *resolveError
+		if errors.As(err, &resolveErr) && r.consumer != nil {
+			metrics := r.convertResolveErrorToMetrics(target, r.tracer.countResolveError(r.config.targetKey(target)))
+			if err := r.pushMetrics(ctx, target, metrics); err != nil {
+				r.settings.Logger.Error("Failed to consume metrics", zap.Error(err))
+			}
+		}
 		return
 	}
 
+	// Generate the span IDs and compare with the previous cycle before the result is shared with the status endpoints
+	result.ids()
+	if r.anycast != nil {
+		result.AnycastSuspected = r.anycast.check(r.config.targetKey(target), result)
+		if result.AnycastSuspected {
+			r.settings.Logger.Debug("Target answered from another location than in the previous trace, it may be anycast",
+				zap.String("target", target.Endpoint),
+				zap.String("ip", result.ResolvedIP))
+		}
+	}
+	r.storeResult(target, result)
+	if r.history != nil {
+		r.history.record(r.config.targetKey(target), result)
+	}
+	if r.latencies != nil {
+		r.latencies.record(r.config.targetKey(target), result)
+	}
+
+	if r.rawOutput != nil {
+		if err := r.rawOutput.write(result); err != nil {
+			r.settings.Logger.Error("Failed to write raw output", zap.Error(err))
+		}
+	}
+
 	// Convert trace result to metrics
 	if r.consumer != nil {
 		metrics := r.convertToMetrics(result, target)
-		if err := r.consumer.ConsumeMetrics(ctx, metrics); err != nil {
+		if err := r.pushMetrics(ctx, target, metrics); err != nil {
 			r.settings.Logger.Error("Failed to consume metrics", zap.Error(err))
 		}
 	}
@@ -110,64 +361,190 @@ func (r *ztraceReceiver) runTrace(target TargetConfig) {
 	// Convert trace result to traces
 	if r.traceConsumer != nil {
 		traces := r.convertToTraces(result, target)
-		if err := r.traceConsumer.ConsumeTraces(ctx, traces); err != nil {
+		if err := r.export(target, signalTraces, func() error { return r.traceConsumer.ConsumeTraces(ctx, traces) }); err != nil {
 			r.settings.Logger.Error("Failed to consume traces", zap.Error(err))
 		}
 	}
+
+	// Summarize the trace as a log record
+	if r.logsConsumer != nil {
+		previousASPath := r.swapASPath(target, asPath(result))
+		logs := r.convertToLogs(result, target, previousASPath)
+		if err := r.export(target, signalLogs, func() error { return r.logsConsumer.ConsumeLogs(ctx, logs) }); err != nil {
+			r.settings.Logger.Error("Failed to consume logs", zap.Error(err))
+		}
+	}
+}
+
+// putSourceAttributes identifies the vantage point the targets were traced from
+func (r *ztraceReceiver) putSourceAttributes(attrs pcommon.Map) {
+	if r.sourceHost != "" {
+		attrs.PutStr(r.attributeNames().sourceHost, r.sourceHost)
+	}
+	if r.config.SourceRegion != "" {
+		attrs.PutStr("ztrace.source.region", r.config.SourceRegion)
+	}
+	if r.config.SourceSite != "" {
+		attrs.PutStr("ztrace.source.site", r.config.SourceSite)
+	}
+}
+
+// swapASPath stores the latest AS path of a target and returns the previous one
+func (r *ztraceReceiver) swapASPath(target TargetConfig, path string) string {
+	r.asPathsMu.Lock()
+	defer r.asPathsMu.Unlock()
+	if r.asPaths == nil {
+		r.asPaths = make(map[string]string)
+	}
+	key := r.config.targetKey(target)
+	previous := r.asPaths[key]
+	r.asPaths[key] = path
+	return previous
+}
+
+// asPath returns the ordered autonomous systems the trace traversed, e.g. "AS64501 AS15169"
+func asPath(result *Result) string {
+	var path []string
+	for _, hop := range result.Hops {
+		if hop.ASN == "" || (len(path) > 0 && path[len(path)-1] == hop.ASN) {
+			continue
+		}
+		path = append(path, hop.ASN)
+	}
+	return strings.Join(path, " ")
 }
 
-func (r *ztraceReceiver) convertToMetrics(result *traceResult, target TargetConfig) pmetric.Metrics {
+func (r *ztraceReceiver) convertToMetrics(result *Result, target TargetConfig) pmetric.Metrics {
+	cfg := r.config.forTarget(target)
+	names := r.attributeNames()
 	md := pmetric.NewMetrics()
 	rm := md.ResourceMetrics().AppendEmpty()
 	
 	// Set resource attributes
 	resource := rm.Resource()
-	resource.Attributes().PutStr("ztrace.target", target.Endpoint)
-	resource.Attributes().PutStr("ztrace.protocol", r.config.Protocol)
+	resource.Attributes().PutStr(names.target, target.Endpoint)
+	resource.Attributes().PutStr(names.protocol, cfg.Protocol)
 	if target.Port > 0 {
-		resource.Attributes().PutInt("ztrace.port", int64(target.Port))
+		resource.Attributes().PutInt(names.port, int64(target.Port))
+	}
+	if result.ResolvedIP != "" {
+		resource.Attributes().PutStr(names.targetIP, result.ResolvedIP)
+	}
+	if result.SourceAddress != "" {
+		resource.Attributes().PutStr(names.sourceIP, result.SourceAddress)
 	}
 	
+	r.putSourceAttributes(resource.Attributes())
+
 	// Add custom tags
 	for k, v := range target.Tags {
 		resource.Attributes().PutStr(k, v)
 	}
 
 	sm := rm.ScopeMetrics().AppendEmpty()
-	sm.Scope().SetName("ztrace")
+	sm.Scope().SetName(r.config.scopeName())
 	sm.Scope().SetVersion("1.0.0")
 
-	timestamp := pcommon.NewTimestampFromTime(time.Now())
+	timestamp := pcommon.NewTimestampFromTime(r.now())
+	unit, scale := r.config.latencyScale()
 
-	// Create metrics for each hop
-	for _, hop := range result.hops {
-		// Latency metric
-		latencyMetric := sm.Metrics().AppendEmpty()
-		latencyMetric.SetName("ztrace.hop.latency")
-		latencyMetric.SetDescription("Latency for each hop in the trace")
-		latencyMetric.SetUnit("ms")
-		
-		gauge := latencyMetric.SetEmptyGauge()
-		dp := gauge.DataPoints().AppendEmpty()
-		dp.SetTimestamp(timestamp)
-		dp.SetDoubleValue(hop.latency)
-		dp.Attributes().PutInt("ttl", int64(hop.ttl))
-		dp.Attributes().PutStr("ip", hop.ip)
-		if hop.hostname != "" {
-			dp.Attributes().PutStr("hostname", hop.hostname)
+	// Create metrics for each hop, ping_only traces only probe the destination
+	hops := result.Hops
+	if r.config.PingOnly {
+		hops = nil
+	}
+	// The metrics of each hop are built aside and only the hops that fit max_data_points next to
+	// the trace summary are kept. Oversized traces, e.g. replayed from a file, stop being built
+	// once their hops alone fill it.
+	var hopMetrics []pmetric.MetricSlice
+	hopPoints := 0
+	for i, hop := range hops {
+		if r.config.MaxDataPoints > 0 && hopPoints >= r.config.MaxDataPoints {
+			break
 		}
-		if r.config.EnableGeolocation && hop.city != "" {
-			dp.Attributes().PutStr("city", hop.city)
-			dp.Attributes().PutStr("country", hop.country)
+		metrics := pmetric.NewMetricSlice()
+		hopMetrics = append(hopMetrics, metrics)
+		// Stable hops were already emitted by a previous cycle
+		if r.deltas != nil && !r.deltas.changed(r.config.targetKey(target), hop) {
+			continue
 		}
-		if r.config.EnableASNLookup && hop.asn != "" {
-			dp.Attributes().PutStr("asn", hop.asn)
-			dp.Attributes().PutStr("provider", hop.provider)
+
+		// Latency metric, a hop no probe answered has no latency to report
+		if !hop.TimedOut {
+			latencyMetric := metrics.AppendEmpty()
+			latencyMetric.SetName("ztrace.hop.latency")
+			latencyMetric.SetDescription("Latency for each hop in the trace")
+			latencyMetric.SetUnit(unit)
+		
+			gauge := latencyMetric.SetEmptyGauge()
+			dp := gauge.DataPoints().AppendEmpty()
+			dp.SetTimestamp(timestamp)
+			dp.SetDoubleValue(hop.Latency * scale)
+			if r.traceConsumer != nil {
+				// Link the latency to the span of the hop, both are converted from the same result
+				exemplar := dp.Exemplars().AppendEmpty()
+				exemplar.SetTimestamp(timestamp)
+				exemplar.SetDoubleValue(hop.Latency * scale)
+				exemplar.SetTraceID(result.ids().traceID)
+				spanID := result.ids().hops[i]
+				if r.config.SpanMode == "events" {
+					// The hop is an event of the root span
+					spanID = result.ids().root
+				}
+				exemplar.SetSpanID(spanID)
+			}
+			dp.Attributes().PutInt("ttl", int64(hop.TTL))
+			dp.Attributes().PutStr("ip", hop.IP)
+			if hop.Hostname != "" {
+				dp.Attributes().PutStr("hostname", hop.Hostname)
+			}
+			if scope := ipScope(hop.IP); scope != "" {
+				dp.Attributes().PutStr("ztrace.hop.scope", scope)
+			}
+			if hop.ReplyProtocol != "" {
+				dp.Attributes().PutStr("ztrace.hop.reply_protocol", hop.ReplyProtocol)
+			}
+			if cfg.EnableGeolocation && hop.City != "" {
+				dp.Attributes().PutStr("city", hop.City)
+				dp.Attributes().PutStr("country", hop.Country)
+			}
+			if cfg.EnableASNLookup && hop.ASN != "" {
+				dp.Attributes().PutStr("asn", hop.ASN)
+				dp.Attributes().PutStr("provider", hop.Provider)
+			}
+		}
+
+		// Outcome of the hop, also for hops that did not answer at all
+		stateMetric := metrics.AppendEmpty()
+		stateMetric.SetName("ztrace.hop.state")
+		stateMetric.SetDescription("Outcome of the probes to each hop: 0 ok, 1 partial loss, 2 timeout")
+		stateMetric.SetUnit("1")
+
+		stateDp := stateMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+		stateDp.SetTimestamp(timestamp)
+		stateDp.SetIntValue(hopState(hop))
+		stateDp.Attributes().PutInt("ttl", int64(hop.TTL))
+		stateDp.Attributes().PutStr("ip", hop.IP)
+
+		// RTT distribution across all probes of the hop
+		if r.config.HopLatencyHistogram && len(hop.RTTs) > 0 {
+			rttMetric := metrics.AppendEmpty()
+			rttMetric.SetName("ztrace.hop.rtt")
+			rttMetric.SetDescription("Distribution of probe round trip times for each hop")
+			rttMetric.SetUnit(unit)
+
+			histogram := rttMetric.SetEmptyHistogram()
+			histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+			rttDp := histogram.DataPoints().AppendEmpty()
+			rttDp.SetTimestamp(timestamp)
+			fillHistogram(rttDp, hop.RTTs, scale)
+			rttDp.Attributes().PutInt("ttl", int64(hop.TTL))
+			rttDp.Attributes().PutStr("ip", hop.IP)
 		}
 
 		// Packet loss metric
-		if hop.packetLoss > 0 {
-			lossMetric := sm.Metrics().AppendEmpty()
+		if hop.PacketLoss > 0 {
+			lossMetric := metrics.AppendEmpty()
 			lossMetric.SetName("ztrace.hop.packet_loss")
 			lossMetric.SetDescription("Packet loss percentage for each hop")
 			lossMetric.SetUnit("%")
@@ -175,73 +552,473 @@ func (r *ztraceReceiver) convertToMetrics(result *traceResult, target TargetConf
 			lossGauge := lossMetric.SetEmptyGauge()
 			lossDp := lossGauge.DataPoints().AppendEmpty()
 			lossDp.SetTimestamp(timestamp)
-			lossDp.SetDoubleValue(hop.packetLoss)
-			lossDp.Attributes().PutInt("ttl", int64(hop.ttl))
-			lossDp.Attributes().PutStr("ip", hop.ip)
+			lossDp.SetDoubleValue(hop.PacketLoss)
+			lossDp.Attributes().PutInt("ttl", int64(hop.TTL))
+			lossDp.Attributes().PutStr("ip", hop.IP)
+
+			// Mirrors the high_packet_loss span event for metric-only pipelines
+			highLossMetric := metrics.AppendEmpty()
+			highLossMetric.SetName("ztrace.hop.high_packet_loss")
+			highLossMetric.SetDescription("Whether the hop packet loss exceeds packet_loss_event_threshold (1) or not (0)")
+			highLossMetric.SetUnit("1")
+
+			highLossDp := highLossMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+			highLossDp.SetTimestamp(timestamp)
+			if hop.PacketLoss > r.config.PacketLossEventThreshold {
+				highLossDp.SetIntValue(1)
+			} else {
+				highLossDp.SetIntValue(0)
+			}
+			highLossDp.Attributes().PutInt("ttl", int64(hop.TTL))
+			highLossDp.Attributes().PutStr("ip", hop.IP)
 		}
 
 		// Jitter metric
-		if hop.jitter > 0 {
-			jitterMetric := sm.Metrics().AppendEmpty()
+		if hop.Jitter > 0 {
+			jitterMetric := metrics.AppendEmpty()
 			jitterMetric.SetName("ztrace.hop.jitter")
 			jitterMetric.SetDescription("Jitter for each hop in the trace")
-			jitterMetric.SetUnit("ms")
+			jitterMetric.SetUnit(unit)
 			
 			jitterGauge := jitterMetric.SetEmptyGauge()
 			jitterDp := jitterGauge.DataPoints().AppendEmpty()
 			jitterDp.SetTimestamp(timestamp)
-			jitterDp.SetDoubleValue(hop.jitter)
-			jitterDp.Attributes().PutInt("ttl", int64(hop.ttl))
-			jitterDp.Attributes().PutStr("ip", hop.ip)
+			jitterDp.SetDoubleValue(hop.Jitter * scale)
+			jitterDp.Attributes().PutInt("ttl", int64(hop.TTL))
+			jitterDp.Attributes().PutStr("ip", hop.IP)
+		}
+
+		// Latency variation of the hop across the recent cycles
+		if r.history != nil && !hop.TimedOut {
+			latencies := r.history.latencies(hopKey{target: r.config.targetKey(target), ttl: hop.TTL, ip: hop.IP})
+			if len(latencies) > 1 {
+				stddevMetric := metrics.AppendEmpty()
+				stddevMetric.SetName("ztrace.hop.latency.stddev")
+				stddevMetric.SetDescription("Standard deviation of the hop latency over the recent collection cycles")
+				stddevMetric.SetUnit(unit)
+
+				stddevDp := stddevMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+				stddevDp.SetTimestamp(timestamp)
+				stddevDp.SetDoubleValue(stddev(latencies) * scale)
+				stddevDp.Attributes().PutInt("ttl", int64(hop.TTL))
+				stddevDp.Attributes().PutStr("ip", hop.IP)
+			}
 		}
+		hopPoints += dataPointCount(metrics)
 	}
 
-	// Overall trace metrics
-	if result.totalLatency > 0 {
-		totalLatencyMetric := sm.Metrics().AppendEmpty()
+	// Overall trace metrics, built aside to size the hops against max_data_points
+	summary := pmetric.NewScopeMetrics()
+	if result.TotalLatency > 0 {
+		totalLatencyMetric := summary.Metrics().AppendEmpty()
 		totalLatencyMetric.SetName("ztrace.total_latency")
 		totalLatencyMetric.SetDescription("Total latency to reach the target")
-		totalLatencyMetric.SetUnit("ms")
+		totalLatencyMetric.SetUnit(unit)
 		
 		totalGauge := totalLatencyMetric.SetEmptyGauge()
 		totalDp := totalGauge.DataPoints().AppendEmpty()
 		totalDp.SetTimestamp(timestamp)
-		totalDp.SetDoubleValue(result.totalLatency)
+		totalDp.SetDoubleValue(result.TotalLatency * scale)
+	}
+	if r.latencies != nil {
+		r.appendTotalLatencyHistogram(summary, target, timestamp, unit, scale)
 	}
 
-	hopCountMetric := sm.Metrics().AppendEmpty()
-	hopCountMetric.SetName("ztrace.hop_count")
-	hopCountMetric.SetDescription("Number of hops to reach the target")
-	hopCountMetric.SetUnit("1")
-	
-	hopGauge := hopCountMetric.SetEmptyGauge()
-	hopDp := hopGauge.DataPoints().AppendEmpty()
-	hopDp.SetTimestamp(timestamp)
-	hopDp.SetIntValue(int64(len(result.hops)))
+	// Probe counters, to bound the network footprint of the receiver
+	if result.ProbesSent > 0 {
+		probesStart := pcommon.NewTimestampFromTime(result.ProbesStart)
+		for _, probes := range []struct {
+			name        string
+			description string
+			value       int64
+		}{
+			{"ztrace.probes.sent", "Total number of probe packets sent to the target", result.ProbesSent},
+			{"ztrace.probes.received", "Total number of probe packets answered on the way to the target", result.ProbesReceived},
+		} {
+			probesMetric := summary.Metrics().AppendEmpty()
+			probesMetric.SetName(probes.name)
+			probesMetric.SetDescription(probes.description)
+			probesMetric.SetUnit("{probe}")
+
+			probesSum := probesMetric.SetEmptySum()
+			probesSum.SetIsMonotonic(true)
+			probesSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			probesDp := probesSum.DataPoints().AppendEmpty()
+			probesDp.SetStartTimestamp(probesStart)
+			probesDp.SetTimestamp(timestamp)
+			probesDp.SetIntValue(probes.value)
+			probesDp.Attributes().PutStr("protocol", cfg.Protocol)
+			probesDp.Attributes().PutStr("target", target.Endpoint)
+		}
+
+		// Failed lookups leave hops without location or AS attributes, tell that apart from hops that have none
+		if cfg.EnableGeolocation || cfg.EnableASNLookup {
+			errorsMetric := summary.Metrics().AppendEmpty()
+			errorsMetric.SetName("ztrace.enrichment.errors")
+			errorsMetric.SetDescription("Total number of failed geolocation and ASN lookups of the target's hops")
+			errorsMetric.SetUnit("{error}")
+
+			errorsSum := errorsMetric.SetEmptySum()
+			errorsSum.SetIsMonotonic(true)
+			errorsSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			for _, lookup := range []struct {
+				kind    string
+				enabled bool
+				value   int64
+			}{
+				{enrichmentGeo, cfg.EnableGeolocation, result.GeoLookupErrors},
+				{enrichmentASN, cfg.EnableASNLookup, result.ASNLookupErrors},
+			} {
+				if !lookup.enabled {
+					continue
+				}
+				errorsDp := errorsSum.DataPoints().AppendEmpty()
+				errorsDp.SetStartTimestamp(probesStart)
+				errorsDp.SetTimestamp(timestamp)
+				errorsDp.SetIntValue(lookup.value)
+				errorsDp.Attributes().PutStr("type", lookup.kind)
+			}
+		}
+
+		// Effectiveness of the lookup cache shared by all targets, counted per target
+		if (cfg.EnableGeolocation || cfg.EnableASNLookup) && cfg.LookupCacheSize > 0 {
+			for _, lookups := range []struct {
+				name        string
+				description string
+				value       int64
+			}{
+				{"ztrace.enrichment.cache.hits", "Total number of hop lookups of the target answered from the lookup cache", result.LookupCacheHits},
+				{"ztrace.enrichment.cache.misses", "Total number of hop lookups of the target sent to the providers", result.LookupCacheMisses},
+			} {
+				lookupsMetric := summary.Metrics().AppendEmpty()
+				lookupsMetric.SetName(lookups.name)
+				lookupsMetric.SetDescription(lookups.description)
+				lookupsMetric.SetUnit("{lookup}")
+
+				lookupsSum := lookupsMetric.SetEmptySum()
+				lookupsSum.SetIsMonotonic(true)
+				lookupsSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				lookupsDp := lookupsSum.DataPoints().AppendEmpty()
+				lookupsDp.SetStartTimestamp(probesStart)
+				lookupsDp.SetTimestamp(timestamp)
+				lookupsDp.SetIntValue(lookups.value)
+			}
+		}
+	}
+
+	reachableMetric := summary.Metrics().AppendEmpty()
+	reachableMetric.SetName("ztrace.target.reachable")
+	reachableMetric.SetDescription("Whether the trace reached the target (1) or not (0)")
+	reachableMetric.SetUnit("1")
+
+	reachableDp := reachableMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+	reachableDp.SetTimestamp(timestamp)
+	if result.TargetReached {
+		reachableDp.SetIntValue(1)
+	} else {
+		reachableDp.SetIntValue(0)
+	}
+	for _, port := range result.Ports {
+		portDp := reachableMetric.Gauge().DataPoints().AppendEmpty()
+		portDp.SetTimestamp(timestamp)
+		portDp.Attributes().PutInt("port", int64(port.Port))
+		if port.Reachable {
+			portDp.SetIntValue(1)
+		} else {
+			portDp.SetIntValue(0)
+		}
+	}
+
+	if !r.config.PingOnly {
+		hopCountMetric := summary.Metrics().AppendEmpty()
+		hopCountMetric.SetName("ztrace.hop_count")
+		hopCountMetric.SetDescription("Number of hops to reach the target")
+		hopCountMetric.SetUnit("1")
+
+		hopGauge := hopCountMetric.SetEmptyGauge()
+		hopDp := hopGauge.DataPoints().AppendEmpty()
+		hopDp.SetTimestamp(timestamp)
+		hopDp.SetIntValue(int64(len(result.Hops)))
+
+		// Hops where every probe timed out, shown as "* * *" by traceroute
+		var unresponsive int64
+		for _, hop := range result.Hops {
+			if hop.TimedOut {
+				unresponsive++
+			}
+		}
+		unresponsiveMetric := summary.Metrics().AppendEmpty()
+		unresponsiveMetric.SetName("ztrace.hops.unresponsive")
+		unresponsiveMetric.SetDescription("Number of hops on the path where no probe was answered")
+		unresponsiveMetric.SetUnit("{hop}")
+
+		unresponsiveDp := unresponsiveMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+		unresponsiveDp.SetTimestamp(timestamp)
+		unresponsiveDp.SetIntValue(unresponsive)
+	}
+
+	if cfg.MaxTraceDuration > 0 {
+		var partial int64
+		if result.Truncated {
+			partial = 1
+		}
+		partialMetric := summary.Metrics().AppendEmpty()
+		partialMetric.SetName("ztrace.trace.truncated")
+		partialMetric.SetDescription("Whether the trace was cut short by max_trace_duration (1) or walked every hop (0)")
+		partialMetric.SetUnit("1")
+
+		partialDp := partialMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+		partialDp.SetTimestamp(timestamp)
+		partialDp.SetIntValue(partial)
+	}
+
+	if cfg.ProbeRateLimit > 0 {
+		rateMetric := summary.Metrics().AppendEmpty()
+		rateMetric.SetName("ztrace.probe.rate")
+		rateMetric.SetDescription("Probes sent per second across all targets, averaged over the last 10s")
+		rateMetric.SetUnit("{probe}/s")
+
+		rateDp := rateMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+		rateDp.SetTimestamp(timestamp)
+		rateDp.SetDoubleValue(result.ProbeRate)
+	}
+
+	if reverse, _, ok := result.reversePath(); ok {
+		reverseMetric := summary.Metrics().AppendEmpty()
+		reverseMetric.SetName("ztrace.reverse_hop_count")
+		reverseMetric.SetDescription("Estimated number of hops of the path back from the target, from the TTL of its replies")
+		reverseMetric.SetUnit("{hop}")
+
+		reverseDp := reverseMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+		reverseDp.SetTimestamp(timestamp)
+		reverseDp.SetIntValue(int64(reverse))
+	}
+
+	r.appendExportMetrics(summary, target, timestamp)
+
+	// Keep the hops that fit next to the summary, leaving room for ztrace.hops.truncated
+	kept := len(hopMetrics)
+	summaryPoints := dataPointCount(summary.Metrics())
+	if limit := r.config.MaxDataPoints; limit > 0 && (kept < len(hops) || summaryPoints+hopPoints > limit) {
+		budget := limit - summaryPoints - 1
+		points := 0
+		for kept = 0; kept < len(hopMetrics); kept++ {
+			points += dataPointCount(hopMetrics[kept])
+			if points > budget {
+				break
+			}
+		}
+	}
+	for _, metrics := range hopMetrics[:kept] {
+		metrics.MoveAndAppendTo(sm.Metrics())
+	}
+	if r.deltas != nil {
+		// Hops dropped by max_data_points were not emitted, they are emitted again once they fit
+		r.deltas.retain(r.config.targetKey(target), hops[:kept])
+	}
+
+	if truncated := len(hops) - kept; truncated > 0 {
+		r.settings.Logger.Warn("Dropped the metrics of hops beyond max_data_points",
+			zap.String("target", target.Endpoint),
+			zap.Int("max_data_points", r.config.MaxDataPoints),
+			zap.Int("dropped_hops", truncated))
+
+		truncatedMetric := summary.Metrics().AppendEmpty()
+		truncatedMetric.SetName("ztrace.hops.truncated")
+		truncatedMetric.SetDescription("Number of hops whose metrics were dropped because the trace exceeded max_data_points")
+		truncatedMetric.SetUnit("{hop}")
+
+		truncatedDp := truncatedMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+		truncatedDp.SetTimestamp(timestamp)
+		truncatedDp.SetIntValue(int64(truncated))
+	}
+	summary.Metrics().MoveAndAppendTo(sm.Metrics())
+	r.config.MetricAttributes.filter(md)
+
+	return md
+}
+
+// convertResolveErrorToMetrics reports a target whose endpoint could not be resolved
+func (r *ztraceReceiver) convertResolveErrorToMetrics(target TargetConfig, resolveErrors int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	sm := r.appendTargetScopeMetrics(md, target)
+
+	timestamp := pcommon.NewTimestampFromTime(r.now())
+
+	resolveMetric := sm.Metrics().AppendEmpty()
+	resolveMetric.SetName("ztrace.dns.resolve_error")
+	resolveMetric.SetDescription("Total number of traces that failed to resolve the target")
+	resolveMetric.SetUnit("{error}")
+
+	resolveSum := resolveMetric.SetEmptySum()
+	resolveSum.SetIsMonotonic(true)
+	resolveSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	resolveDp := resolveSum.DataPoints().AppendEmpty()
+	resolveDp.SetStartTimestamp(pcommon.NewTimestampFromTime(r.tracer.startTime))
+	resolveDp.SetTimestamp(timestamp)
+	resolveDp.SetIntValue(resolveErrors)
+	resolveDp.Attributes().PutStr("target", target.Endpoint)
+
+	reachableMetric := sm.Metrics().AppendEmpty()
+	reachableMetric.SetName("ztrace.target.reachable")
+	reachableMetric.SetDescription("Whether the trace reached the target (1) or not (0)")
+	reachableMetric.SetUnit("1")
+
+	reachableDp := reachableMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+	reachableDp.SetTimestamp(timestamp)
+	reachableDp.SetIntValue(0)
+
+	return md
+}
+
+// convertPanicToMetrics reports a trace that panicked before producing a result
+func (r *ztraceReceiver) convertPanicToMetrics(target TargetConfig, panics int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	sm := r.appendTargetScopeMetrics(md, target)
+
+	panicMetric := sm.Metrics().AppendEmpty()
+	panicMetric.SetName("ztrace.panic")
+	panicMetric.SetDescription("Total number of traces of the target that panicked")
+	panicMetric.SetUnit("{panic}")
+
+	panicSum := panicMetric.SetEmptySum()
+	panicSum.SetIsMonotonic(true)
+	panicSum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	panicDp := panicSum.DataPoints().AppendEmpty()
+	panicDp.SetStartTimestamp(pcommon.NewTimestampFromTime(r.tracer.startTime))
+	panicDp.SetTimestamp(pcommon.NewTimestampFromTime(r.now()))
+	panicDp.SetIntValue(panics)
+	panicDp.Attributes().PutStr("target", target.Endpoint)
 
 	return md
 }
 
-func (r *ztraceReceiver) convertToTraces(result *traceResult, target TargetConfig) ptrace.Traces {
+// appendTargetScopeMetrics adds the resource of a target to md and returns its ztrace scope
+func (r *ztraceReceiver) appendTargetScopeMetrics(md pmetric.Metrics, target TargetConfig) pmetric.ScopeMetrics {
+	cfg := r.config.forTarget(target)
+	names := r.attributeNames()
+	rm := md.ResourceMetrics().AppendEmpty()
+
+	resource := rm.Resource()
+	resource.Attributes().PutStr(names.target, target.Endpoint)
+	resource.Attributes().PutStr(names.protocol, cfg.Protocol)
+	if target.Port > 0 {
+		resource.Attributes().PutInt(names.port, int64(target.Port))
+	}
+	r.putSourceAttributes(resource.Attributes())
+	for k, v := range target.Tags {
+		resource.Attributes().PutStr(k, v)
+	}
+
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(r.config.scopeName())
+	sm.Scope().SetVersion("1.0.0")
+	return sm
+}
+
+// Values of the ztrace.hop.state metric
+const (
+	hopStateOK          int64 = 0
+	hopStatePartialLoss int64 = 1
+	hopStateTimeout     int64 = 2
+)
+
+// hopState maps the outcome of the probes to a hop to its ztrace.hop.state value
+func hopState(hop Hop) int64 {
+	switch {
+	case hop.TimedOut:
+		return hopStateTimeout
+	case hop.PacketLoss > 0:
+		return hopStatePartialLoss
+	default:
+		return hopStateOK
+	}
+}
+
+// dataPointCount returns the number of data points of the metric types the receiver emits
+func dataPointCount(metrics pmetric.MetricSlice) int {
+	count := 0
+	for i := 0; i < metrics.Len(); i++ {
+		switch metric := metrics.At(i); metric.Type() {
+		case pmetric.MetricTypeGauge:
+			count += metric.Gauge().DataPoints().Len()
+		case pmetric.MetricTypeSum:
+			count += metric.Sum().DataPoints().Len()
+		case pmetric.MetricTypeHistogram:
+			count += metric.Histogram().DataPoints().Len()
+		}
+	}
+	return count
+}
+
+// hopRTTBounds are the explicit bucket boundaries, in milliseconds, of the ztrace.hop.rtt histogram
+var hopRTTBounds = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+// fillHistogram records the given RTTs, in milliseconds, into an explicit-bucket histogram data point
+// reported in milliseconds times scale
+func fillHistogram(dp pmetric.HistogramDataPoint, rtts []float64, scale float64) {
+	counts := make([]uint64, len(hopRTTBounds)+1)
+	var sum float64
+	for i, ms := range rtts {
+		bucket := len(hopRTTBounds)
+		for b, bound := range hopRTTBounds {
+			if ms <= bound {
+				bucket = b
+				break
+			}
+		}
+		counts[bucket]++
+
+		rtt := ms * scale
+		sum += rtt
+		if i == 0 || rtt < dp.Min() {
+			dp.SetMin(rtt)
+		}
+		if i == 0 || rtt > dp.Max() {
+			dp.SetMax(rtt)
+		}
+	}
+
+	bounds := make([]float64, len(hopRTTBounds))
+	for i, bound := range hopRTTBounds {
+		bounds[i] = bound * scale
+	}
+	dp.SetCount(uint64(len(rtts)))
+	dp.SetSum(sum)
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(counts)
+}
+
+func (r *ztraceReceiver) convertToTraces(result *Result, target TargetConfig) ptrace.Traces {
+	cfg := r.config.forTarget(target)
+	names := r.attributeNames()
 	td := ptrace.NewTraces()
 	rs := td.ResourceSpans().AppendEmpty()
 	
 	// Set resource attributes
 	resource := rs.Resource()
-	resource.Attributes().PutStr("ztrace.target", target.Endpoint)
-	resource.Attributes().PutStr("ztrace.protocol", r.config.Protocol)
-	resource.Attributes().PutStr("service.name", "ztrace")
+	resource.Attributes().PutStr(names.target, target.Endpoint)
+	resource.Attributes().PutStr(names.protocol, cfg.Protocol)
+	resource.Attributes().PutStr("service.name", r.config.serviceName())
 	if target.Port > 0 {
-		resource.Attributes().PutInt("ztrace.port", int64(target.Port))
+		resource.Attributes().PutInt(names.port, int64(target.Port))
+	}
+	if result.ResolvedIP != "" {
+		resource.Attributes().PutStr(names.targetIP, result.ResolvedIP)
+	}
+	if result.SourceAddress != "" {
+		resource.Attributes().PutStr(names.sourceIP, result.SourceAddress)
 	}
 	
+	r.putSourceAttributes(resource.Attributes())
+
 	// Add custom tags
 	for k, v := range target.Tags {
 		resource.Attributes().PutStr(k, v)
 	}
 
 	ss := rs.ScopeSpans().AppendEmpty()
-	ss.Scope().SetName("ztrace")
+	ss.Scope().SetName(r.config.scopeName())
 	ss.Scope().SetVersion("1.0.0")
 
 	// Create a root span for the entire trace
@@ -249,66 +1026,231 @@ func (r *ztraceReceiver) convertToTraces(result *traceResult, target TargetConfi
 	rootSpan.SetName(fmt.Sprintf("traceroute to %s", target.Endpoint))
 	rootSpan.SetKind(ptrace.SpanKindClient)
 	
-	traceID := pcommon.TraceID([16]byte{}) // Generate proper trace ID
-	rootSpanID := pcommon.SpanID([8]byte{}) // Generate proper span ID
+	ids := result.ids()
+	traceID := ids.traceID
+	rootSpanID := ids.root
 	rootSpan.SetTraceID(traceID)
 	rootSpan.SetSpanID(rootSpanID)
 	
-	startTime := pcommon.NewTimestampFromTime(time.Now().Add(-time.Duration(result.totalLatency) * time.Millisecond))
-	endTime := pcommon.NewTimestampFromTime(time.Now())
+	now := r.now()
+	startTime := pcommon.NewTimestampFromTime(now.Add(-time.Duration(result.TotalLatency) * time.Millisecond))
+	endTime := pcommon.NewTimestampFromTime(now)
 	rootSpan.SetStartTimestamp(startTime)
 	rootSpan.SetEndTimestamp(endTime)
 	
-	rootSpan.Attributes().PutInt("hop.count", int64(len(result.hops)))
-	rootSpan.Attributes().PutDouble("total.latency.ms", result.totalLatency)
+	rootSpan.Attributes().PutInt("hop.count", int64(len(result.Hops)))
+	if result.Truncated {
+		rootSpan.Attributes().PutBool("trace.truncated", true)
+	}
+	if reverse, asymmetric, ok := result.reversePath(); ok {
+		rootSpan.Attributes().PutInt("reverse.hop.count", int64(reverse))
+		rootSpan.Attributes().PutBool("path.asymmetric", asymmetric)
+	}
+	unit, scale := r.config.latencyScale()
+	rootSpan.Attributes().PutDouble("total.latency."+unit, result.TotalLatency*scale)
+	if result.TargetReached {
+		rootSpan.Attributes().PutBool("ztrace.target.anycast_suspected", result.AnycastSuspected)
+	} else {
+		rootSpan.Status().SetCode(ptrace.StatusCodeError)
+		rootSpan.Status().SetMessage(fmt.Sprintf("target not reached within %d hops", len(result.Hops)))
+	}
+
+	// Create child spans for each hop, the chain topology parents every hop to the previous one.
+	// In events mode the hops are events of the root span instead.
+	parentSpanID := rootSpanID
+	for i, hop := range result.Hops {
+		// Every hop span counts against the data point budget, like the root span
+		if r.config.MaxDataPoints > 0 && i+1 >= r.config.MaxDataPoints {
+			dropped := len(result.Hops) - i
+			rootSpan.Attributes().PutInt("hops.truncated", int64(dropped))
+			r.settings.Logger.Warn("Dropped the spans of hops beyond max_data_points",
+				zap.String("target", target.Endpoint),
+				zap.Int("max_data_points", r.config.MaxDataPoints),
+				zap.Int("dropped_hops", dropped))
+			break
+		}
+		hopEndTime := pcommon.NewTimestampFromTime(startTime.AsTime().Add(time.Duration(hop.Latency) * time.Millisecond))
+		baseline, spike := r.latencySpike(target, hop)
+
+		if r.config.SpanMode == "events" {
+			event := rootSpan.Events().AppendEmpty()
+			event.SetName("hop")
+			event.SetTimestamp(hopEndTime)
+			r.putHopAttributes(event.Attributes(), hop, cfg, names, unit, scale)
+			if hop.IP == "" {
+				event.Attributes().PutBool("timed_out", true)
+			}
+			if hop.PacketLoss > r.config.PacketLossEventThreshold {
+				event.Attributes().PutBool("high_packet_loss", true)
+			}
+			if spike {
+				event.Attributes().PutDouble("baseline."+unit, baseline*scale)
+			}
+			continue
+		}
 
-	// Create child spans for each hop
-	for _, hop := range result.hops {
 		hopSpan := ss.Spans().AppendEmpty()
-		hopSpan.SetName(fmt.Sprintf("hop %d: %s", hop.ttl, hop.ip))
+		hopIP := hop.IP
+		if hopIP == "" {
+			// Placeholder of a hop that did not answer, like traceroute
+			hopIP = "*"
+		}
+		hopSpan.SetName(fmt.Sprintf("hop %d: %s", hop.TTL, hopIP))
 		hopSpan.SetKind(ptrace.SpanKindClient)
 		hopSpan.SetTraceID(traceID)
 		
-		hopSpanID := pcommon.SpanID([8]byte{byte(hop.ttl)}) // Generate proper span ID
+		hopSpanID := ids.hops[i]
 		hopSpan.SetSpanID(hopSpanID)
-		hopSpan.SetParentSpanID(rootSpanID)
+		hopSpan.SetParentSpanID(parentSpanID)
+		if r.config.SpanTopology == "chain" {
+			if parentSpanID != rootSpanID {
+				rootLink := hopSpan.Links().AppendEmpty()
+				rootLink.SetTraceID(traceID)
+				rootLink.SetSpanID(rootSpanID)
+			}
+			parentSpanID = hopSpanID
+		}
 		
-		hopStartTime := startTime
-		hopEndTime := pcommon.NewTimestampFromTime(startTime.AsTime().Add(time.Duration(hop.latency) * time.Millisecond))
-		hopSpan.SetStartTimestamp(hopStartTime)
+		hopSpan.SetStartTimestamp(startTime)
 		hopSpan.SetEndTimestamp(hopEndTime)
 		
 		// Set hop attributes
-		hopSpan.Attributes().PutInt("ttl", int64(hop.ttl))
-		hopSpan.Attributes().PutStr("ip", hop.ip)
-		hopSpan.Attributes().PutDouble("latency.ms", hop.latency)
-		
-		if hop.hostname != "" {
-			hopSpan.Attributes().PutStr("hostname", hop.hostname)
-		}
-		if hop.packetLoss > 0 {
-			hopSpan.Attributes().PutDouble("packet_loss.percent", hop.packetLoss)
-		}
-		if hop.jitter > 0 {
-			hopSpan.Attributes().PutDouble("jitter.ms", hop.jitter)
-		}
-		if r.config.EnableGeolocation && hop.city != "" {
-			hopSpan.Attributes().PutStr("geo.city", hop.city)
-			hopSpan.Attributes().PutStr("geo.country", hop.country)
-		}
-		if r.config.EnableASNLookup && hop.asn != "" {
-			hopSpan.Attributes().PutStr("network.asn", hop.asn)
-			hopSpan.Attributes().PutStr("network.provider", hop.provider)
+		r.putHopAttributes(hopSpan.Attributes(), hop, cfg, names, unit, scale)
+		if hop.IP == "" {
+			hopSpan.Status().SetCode(ptrace.StatusCodeError)
+			hopSpan.Status().SetMessage("no probe answered")
 		}
 		
 		// Add events for significant issues
-		if hop.packetLoss > 50 {
+		if hop.PacketLoss > r.config.PacketLossEventThreshold {
 			event := hopSpan.Events().AppendEmpty()
 			event.SetName("high_packet_loss")
 			event.SetTimestamp(hopEndTime)
-			event.Attributes().PutDouble("packet_loss.percent", hop.packetLoss)
+			event.Attributes().PutDouble("packet_loss.percent", hop.PacketLoss)
+		}
+		if spike {
+			event := hopSpan.Events().AppendEmpty()
+			event.SetName("latency_spike")
+			event.SetTimestamp(hopEndTime)
+			event.Attributes().PutDouble("latency."+unit, hop.Latency*scale)
+			event.Attributes().PutDouble("baseline."+unit, baseline*scale)
 		}
 	}
 
 	return td
-}
\ No newline at end of file
+}
+
+// putHopAttributes sets the attributes of a hop on its span, or on its event of the root span
+func (r *ztraceReceiver) putHopAttributes(attrs pcommon.Map, hop Hop, cfg *Config, names attributeNames, unit string, scale float64) {
+	attrs.PutInt("ttl", int64(hop.TTL))
+	attrs.PutStr(names.hopIP, hop.IP)
+	attrs.PutDouble("latency."+unit, hop.Latency*scale)
+
+	if hop.Hostname != "" {
+		attrs.PutStr("hostname", hop.Hostname)
+	}
+	if scope := ipScope(hop.IP); scope != "" {
+		attrs.PutStr("ztrace.hop.scope", scope)
+	}
+	if hop.ReplyProtocol != "" {
+		attrs.PutStr("ztrace.hop.reply_protocol", hop.ReplyProtocol)
+	}
+	if hop.PacketLoss > 0 {
+		attrs.PutDouble("packet_loss.percent", hop.PacketLoss)
+	}
+	if hop.Jitter > 0 {
+		attrs.PutDouble("jitter."+unit, hop.Jitter*scale)
+	}
+	if cfg.EnableGeolocation && hop.City != "" {
+		attrs.PutStr("geo.city", hop.City)
+		attrs.PutStr("geo.country", hop.Country)
+	}
+	if cfg.EnableASNLookup && hop.ASN != "" {
+		attrs.PutStr("network.asn", hop.ASN)
+		attrs.PutStr("network.provider", hop.Provider)
+	}
+}
+
+// latencySpike compares the hop with its latency over the previous cycles and returns the baseline
+// it exceeds by latency_spike_factor
+func (r *ztraceReceiver) latencySpike(target TargetConfig, hop Hop) (float64, bool) {
+	if r.history == nil || r.config.LatencySpikeFactor <= 0 || hop.IP == "" {
+		return 0, false
+	}
+	latencies := r.history.latencies(hopKey{target: r.config.targetKey(target), ttl: hop.TTL, ip: hop.IP})
+	if len(latencies) <= minBaselineCycles {
+		return 0, false
+	}
+	baseline := median(latencies[:len(latencies)-1])
+	return baseline, baseline > 0 && hop.Latency > baseline*r.config.LatencySpikeFactor
+}
+
+func (r *ztraceReceiver) convertToLogs(result *Result, target TargetConfig, previousASPath string) plog.Logs {
+	cfg := r.config.forTarget(target)
+	names := r.attributeNames()
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+
+	// Set resource attributes
+	resource := rl.Resource()
+	resource.Attributes().PutStr(names.target, target.Endpoint)
+	resource.Attributes().PutStr(names.protocol, cfg.Protocol)
+	if target.Port > 0 {
+		resource.Attributes().PutInt(names.port, int64(target.Port))
+	}
+	if result.ResolvedIP != "" {
+		resource.Attributes().PutStr(names.targetIP, result.ResolvedIP)
+	}
+	if result.SourceAddress != "" {
+		resource.Attributes().PutStr(names.sourceIP, result.SourceAddress)
+	}
+
+	r.putSourceAttributes(resource.Attributes())
+
+	// Add custom tags
+	for k, v := range target.Tags {
+		resource.Attributes().PutStr(k, v)
+	}
+
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(r.config.scopeName())
+	sl.Scope().SetVersion("1.0.0")
+
+	timestamp := pcommon.NewTimestampFromTime(r.now())
+
+	record := sl.LogRecords().AppendEmpty()
+	record.SetTimestamp(timestamp)
+	record.SetObservedTimestamp(timestamp)
+	record.SetSeverityNumber(plog.SeverityNumberInfo)
+	record.SetSeverityText("INFO")
+	record.Body().SetStr(fmt.Sprintf("traceroute to %s completed in %d hops", target.Endpoint, len(result.Hops)))
+
+	// Summary of the trace
+	record.Attributes().PutInt("hop.count", int64(len(result.Hops)))
+	unit, scale := r.config.latencyScale()
+	record.Attributes().PutDouble("total.latency."+unit, result.TotalLatency*scale)
+	record.Attributes().PutBool("target.reached", result.TargetReached)
+	if reverse, asymmetric, ok := result.reversePath(); ok {
+		record.Attributes().PutInt("reverse.hop.count", int64(reverse))
+		record.Attributes().PutBool("path.asymmetric", asymmetric)
+		if asymmetric {
+			record.SetSeverityNumber(plog.SeverityNumberWarn)
+			record.SetSeverityText("WARN")
+		}
+	}
+
+	// AS path, only comparable across traces when ASN lookup is enabled
+	if cfg.EnableASNLookup {
+		path := asPath(result)
+		changed := previousASPath != "" && previousASPath != path
+		record.Attributes().PutStr("network.as_path", path)
+		record.Attributes().PutBool("network.as_path.changed", changed)
+		if changed {
+			record.Attributes().PutStr("network.as_path.previous", previousASPath)
+			record.SetSeverityNumber(plog.SeverityNumberWarn)
+			record.SetSeverityText("WARN")
+		}
+	}
+
+	return ld
+}
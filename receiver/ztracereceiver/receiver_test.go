@@ -5,6 +5,11 @@ package ztracereceiver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,13 +18,18 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
 )
 
 func TestReceiverLifecycle(t *testing.T) {
 	cfg := &Config{
 		ServerConfig: confighttp.ServerConfig{
-			Endpoint: "localhost:8080",
+			Endpoint: "localhost:0",
 		},
 		Targets: []TargetConfig{
 			{
@@ -47,7 +57,7 @@ func TestReceiverLifecycle(t *testing.T) {
 	ctx := context.Background()
 	err := r.Start(ctx, componenttest.NewNopHost())
 	require.NoError(t, err)
-	assert.NotNil(t, r.stopCh)
+	assert.Len(t, r.running, 1)
 	assert.NotNil(t, r.tracer)
 
 	err = r.Shutdown(ctx)
@@ -56,9 +66,10 @@ func TestReceiverLifecycle(t *testing.T) {
 
 func TestConvertToMetrics(t *testing.T) {
 	cfg := &Config{
-		Protocol:          "udp",
-		EnableGeolocation: true,
-		EnableASNLookup:   true,
+		Protocol:                 "udp",
+		EnableGeolocation:        true,
+		EnableASNLookup:          true,
+		PacketLossEventThreshold: 50,
 	}
 
 	r := &ztraceReceiver{
@@ -66,31 +77,32 @@ func TestConvertToMetrics(t *testing.T) {
 		settings: receivertest.NewNopSettings(),
 	}
 
-	result := &traceResult{
-		hops: []hopInfo{
+	result := &Result{
+		Hops: []Hop{
 			{
-				ttl:        1,
-				ip:         "192.168.1.1",
-				hostname:   "router.local",
-				latency:    2.5,
-				packetLoss: 0,
-				jitter:     0.5,
-				city:       "San Francisco",
-				country:    "US",
-				asn:        "AS15169",
-				provider:   "Google",
+				TTL:        1,
+				IP:         "192.168.1.1",
+				Hostname:   "router.local",
+				Latency:    2.5,
+				PacketLoss: 0,
+				Jitter:     0.5,
+				City:       "San Francisco",
+				Country:    "US",
+				ASN:        "AS15169",
+				Provider:   "Google",
 			},
 			{
-				ttl:        2,
-				ip:         "10.0.0.1",
-				hostname:   "gateway.isp.net",
-				latency:    10.2,
-				packetLoss: 5.0,
-				jitter:     1.2,
+				TTL:        2,
+				IP:         "10.0.0.1",
+				Hostname:   "gateway.isp.net",
+				Latency:    10.2,
+				PacketLoss: 5.0,
+				Jitter:     1.2,
 			},
 		},
-		totalLatency:  12.7,
-		targetReached: true,
+		TotalLatency:  12.7,
+		TargetReached: true,
+		ResolvedIP:    "93.184.216.34",
 	}
 
 	target := TargetConfig{
@@ -115,6 +127,10 @@ func TestConvertToMetrics(t *testing.T) {
 	val, ok = attrs.Get("ztrace.protocol")
 	assert.True(t, ok)
 	assert.Equal(t, "udp", val.Str())
+
+	val, ok = attrs.Get("ztrace.target.ip")
+	assert.True(t, ok)
+	assert.Equal(t, "93.184.216.34", val.Str())
 	
 	val, ok = attrs.Get("env")
 	assert.True(t, ok)
@@ -139,17 +155,165 @@ func TestConvertToMetrics(t *testing.T) {
 			gauge := metric.Gauge()
 			assert.Equal(t, 1, gauge.DataPoints().Len())
 			assert.Equal(t, int64(2), gauge.DataPoints().At(0).IntValue())
+		case "ztrace.target.reachable":
+			assert.Equal(t, int64(1), metric.Gauge().DataPoints().At(0).IntValue())
+		case "ztrace.hop.high_packet_loss":
+			// 5% loss at hop 2 is below the threshold
+			assert.Equal(t, int64(0), metric.Gauge().DataPoints().At(0).IntValue())
 		}
 	}
 	assert.True(t, foundLatency, "latency metric not found")
 	assert.True(t, foundHopCount, "hop count metric not found")
 }
 
+func TestConvertToMetricsUnresponsiveHops(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "udp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 1.5},
+			{TTL: 2, TimedOut: true, PacketLoss: 100},
+			{TTL: 3, TimedOut: true, PacketLoss: 100},
+			{TTL: 4, IP: "93.184.216.34", Latency: 20.1},
+		},
+		TotalLatency:  20.1,
+		TargetReached: true,
+	}
+
+	sm := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com", Port: 80}).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	var found bool
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.hops.unresponsive" {
+			continue
+		}
+		found = true
+		assert.Equal(t, int64(2), metric.Gauge().DataPoints().At(0).IntValue())
+	}
+	assert.True(t, found, "unresponsive hops metric not found")
+}
+
+func TestConvertToMetricsHopRTTHistogram(t *testing.T) {
+	cfg := &Config{
+		Protocol:            "udp",
+		HopLatencyHistogram: true,
+	}
+
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+
+	result := &Result{
+		Hops: []Hop{
+			{
+				TTL:     1,
+				IP:      "192.168.1.1",
+				Latency: 3.0,
+				RTTs:    []float64{1.5, 3.0, 4.5},
+			},
+			{
+				TTL: 2, // No response, no histogram
+			},
+		},
+		TotalLatency:  3.0,
+		TargetReached: true,
+	}
+
+	metrics := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com", Port: 80})
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+
+	histograms := 0
+	foundGauge := false
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		switch metric.Name() {
+		case "ztrace.hop.latency":
+			foundGauge = true
+		case "ztrace.hop.rtt":
+			histograms++
+			assert.Equal(t, "ms", metric.Unit())
+			dp := metric.Histogram().DataPoints().At(0)
+			assert.Equal(t, uint64(3), dp.Count())
+			assert.InDelta(t, 9.0, dp.Sum(), 0.001)
+			assert.InDelta(t, 1.5, dp.Min(), 0.001)
+			assert.InDelta(t, 4.5, dp.Max(), 0.001)
+			assert.Equal(t, hopRTTBounds, dp.ExplicitBounds().AsRaw())
+			// 1.5 falls in (1, 2], 3.0 and 4.5 in (2, 5]
+			assert.Equal(t, []uint64{0, 1, 2, 0, 0, 0, 0, 0, 0, 0, 0}, dp.BucketCounts().AsRaw())
+			ttl, ok := dp.Attributes().Get("ttl")
+			assert.True(t, ok)
+			assert.Equal(t, int64(1), ttl.Int())
+		}
+	}
+	assert.Equal(t, 1, histograms)
+	assert.True(t, foundGauge, "latency gauge must still be emitted")
+
+	// The histogram is opt-in
+	cfg.HopLatencyHistogram = false
+	metrics = r.convertToMetrics(result, TargetConfig{Endpoint: "example.com", Port: 80})
+	sm = metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		assert.NotEqual(t, "ztrace.hop.rtt", sm.Metrics().At(i).Name())
+	}
+}
+
+func TestTraceCountsProbes(t *testing.T) {
+	cfg := &Config{
+		Protocol: "icmp",
+		MaxHops:  30,
+		Retries:  2,
+	}
+
+	tr, err := newTracer(cfg, zap.NewNop())
+	require.NoError(t, err)
+	target := TargetConfig{Endpoint: "127.0.0.1"}
+
+	// The simulated route reaches the target at hop 15, hops 13 and 14 never answer
+	result, err := tr.trace(context.Background(), target, cfg)
+	require.NoError(t, err)
+	require.Len(t, result.Hops, 15)
+	assert.Equal(t, int64(45), result.ProbesSent)
+	assert.Equal(t, int64(39), result.ProbesReceived)
+
+	// Counters accumulate across cycles
+	result, err = tr.trace(context.Background(), target, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(90), result.ProbesSent)
+	assert.Equal(t, int64(78), result.ProbesReceived)
+
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+	metrics := r.convertToMetrics(result, target)
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+
+	probes := map[string]int64{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.probes.sent" && metric.Name() != "ztrace.probes.received" {
+			continue
+		}
+		assert.True(t, metric.Sum().IsMonotonic())
+		assert.Equal(t, pmetric.AggregationTemporalityCumulative, metric.Sum().AggregationTemporality())
+		dp := metric.Sum().DataPoints().At(0)
+		protocol, ok := dp.Attributes().Get("protocol")
+		assert.True(t, ok)
+		assert.Equal(t, "icmp", protocol.Str())
+		probes[metric.Name()] = dp.IntValue()
+	}
+	assert.Equal(t, map[string]int64{"ztrace.probes.sent": 90, "ztrace.probes.received": 78}, probes)
+}
+
 func TestConvertToTraces(t *testing.T) {
 	cfg := &Config{
-		Protocol:          "icmp",
-		EnableGeolocation: true,
-		EnableASNLookup:   true,
+		Protocol:                 "icmp",
+		EnableGeolocation:        true,
+		EnableASNLookup:          true,
+		PacketLossEventThreshold: 50,
 	}
 
 	r := &ztraceReceiver{
@@ -157,24 +321,25 @@ func TestConvertToTraces(t *testing.T) {
 		settings: receivertest.NewNopSettings(),
 	}
 
-	result := &traceResult{
-		hops: []hopInfo{
+	result := &Result{
+		Hops: []Hop{
 			{
-				ttl:      1,
-				ip:       "192.168.1.1",
-				hostname: "router.local",
-				latency:  2.5,
+				TTL:      1,
+				IP:       "192.168.1.1",
+				Hostname: "router.local",
+				Latency:  2.5,
 			},
 			{
-				ttl:        2,
-				ip:         "10.0.0.1",
-				hostname:   "gateway.isp.net",
-				latency:    10.2,
-				packetLoss: 60.0, // High packet loss to trigger event
+				TTL:        2,
+				IP:         "10.0.0.1",
+				Hostname:   "gateway.isp.net",
+				Latency:    10.2,
+				PacketLoss: 60.0, // High packet loss to trigger event
 			},
 		},
-		totalLatency:  12.7,
-		targetReached: true,
+		TotalLatency:  12.7,
+		TargetReached: true,
+		ResolvedIP:    "93.184.216.34",
 	}
 
 	target := TargetConfig{
@@ -199,6 +364,10 @@ func TestConvertToTraces(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "prod", val.Str())
 
+	val, ok = attrs.Get("ztrace.target.ip")
+	assert.True(t, ok)
+	assert.Equal(t, "93.184.216.34", val.Str())
+
 	// Check spans
 	require.Equal(t, 1, rs.ScopeSpans().Len())
 	ss := rs.ScopeSpans().At(0)
@@ -237,6 +406,136 @@ func TestConvertToTraces(t *testing.T) {
 		}
 	}
 	assert.True(t, foundHighPacketLossEvent, "high packet loss event not found")
+	for i := 0; i < ss.Spans().Len(); i++ {
+		assert.Equal(t, ptrace.StatusCodeUnset, ss.Spans().At(i).Status().Code())
+	}
+
+	// An unreachable target fails the root span, a hop without any answer fails its own span
+	result.TargetReached = false
+	result.Hops = append(result.Hops, Hop{TTL: 3})
+	ss = r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0)
+	statuses := map[string]ptrace.StatusCode{}
+	for i := 0; i < ss.Spans().Len(); i++ {
+		statuses[ss.Spans().At(i).Name()] = ss.Spans().At(i).Status().Code()
+		if ss.Spans().At(i).Name() == "traceroute to example.com" {
+			assert.Equal(t, "target not reached within 3 hops", ss.Spans().At(i).Status().Message())
+		}
+	}
+	assert.Equal(t, map[string]ptrace.StatusCode{
+		"traceroute to example.com": ptrace.StatusCodeError,
+		"hop 1: 192.168.1.1":        ptrace.StatusCodeUnset,
+		"hop 2: 10.0.0.1":           ptrace.StatusCodeUnset,
+		"hop 3: ":                   ptrace.StatusCodeError,
+	}, statuses)
+}
+
+func TestServiceAndScopeName(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "udp", ServiceName: "net-probe-eu", ScopeName: "net-probe"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops:          []Hop{{TTL: 1, IP: "93.184.216.34", Latency: 12.5, RTTs: []float64{12.5}}},
+		TotalLatency:  12.5,
+		TargetReached: true,
+		ResolvedIP:    "93.184.216.34",
+	}
+	target := TargetConfig{Endpoint: "example.com", Port: 80}
+
+	rs := r.convertToTraces(result, target).ResourceSpans().At(0)
+	serviceName, ok := rs.Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "net-probe-eu", serviceName.Str())
+	assert.Equal(t, "net-probe", rs.ScopeSpans().At(0).Scope().Name())
+	assert.Equal(t, "net-probe", r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0).Scope().Name())
+	assert.Equal(t, "net-probe", r.convertToLogs(result, target, "").ResourceLogs().At(0).ScopeLogs().At(0).Scope().Name())
+
+	// Unset names keep the defaults
+	r.config.ServiceName, r.config.ScopeName = "", ""
+	rs = r.convertToTraces(result, target).ResourceSpans().At(0)
+	serviceName, _ = rs.Resource().Attributes().Get("service.name")
+	assert.Equal(t, "ztrace", serviceName.Str())
+	assert.Equal(t, "ztrace", rs.ScopeSpans().At(0).Scope().Name())
+}
+
+func TestConvertToLogs(t *testing.T) {
+	cfg := &Config{
+		Protocol:        "udp",
+		EnableASNLookup: true,
+	}
+
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2, IP: "10.2.20.1", Latency: 8.0, ASN: "AS64502"},
+			{TTL: 3, IP: "10.3.30.1", Latency: 9.5, ASN: "AS64502"},
+			{TTL: 4, IP: "203.0.4.1", Latency: 21.0, ASN: "AS15169"},
+		},
+		TotalLatency:  21.0,
+		TargetReached: true,
+		ResolvedIP:    "93.184.216.34",
+	}
+
+	target := TargetConfig{
+		Endpoint: "example.com",
+		Port:     80,
+		Tags: map[string]string{
+			"env": "test",
+		},
+	}
+
+	// The first trace has nothing to compare against
+	assert.Equal(t, "", r.swapASPath(target, asPath(result)))
+	logs := r.convertToLogs(result, target, "")
+
+	require.Equal(t, 1, logs.ResourceLogs().Len())
+	rl := logs.ResourceLogs().At(0)
+
+	val, ok := rl.Resource().Attributes().Get("env")
+	assert.True(t, ok)
+	assert.Equal(t, "test", val.Str())
+	val, ok = rl.Resource().Attributes().Get("ztrace.target.ip")
+	assert.True(t, ok)
+	assert.Equal(t, "93.184.216.34", val.Str())
+
+	require.Equal(t, 1, rl.ScopeLogs().Len())
+	require.Equal(t, 1, rl.ScopeLogs().At(0).LogRecords().Len())
+	record := rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, plog.SeverityNumberInfo, record.SeverityNumber())
+
+	attrs := record.Attributes()
+	hopCount, ok := attrs.Get("hop.count")
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), hopCount.Int())
+	reached, ok := attrs.Get("target.reached")
+	assert.True(t, ok)
+	assert.True(t, reached.Bool())
+	path, ok := attrs.Get("network.as_path")
+	assert.True(t, ok)
+	assert.Equal(t, "AS64502 AS15169", path.Str())
+	changed, ok := attrs.Get("network.as_path.changed")
+	assert.True(t, ok)
+	assert.False(t, changed.Bool())
+
+	// A later trace through a different transit provider is flagged
+	result.Hops[3].ASN = "AS3356"
+	previous := r.swapASPath(target, asPath(result))
+	assert.Equal(t, "AS64502 AS15169", previous)
+	logs = r.convertToLogs(result, target, previous)
+
+	record = logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, plog.SeverityNumberWarn, record.SeverityNumber())
+	changed, ok = record.Attributes().Get("network.as_path.changed")
+	assert.True(t, ok)
+	assert.True(t, changed.Bool())
+	prev, ok := record.Attributes().Get("network.as_path.previous")
+	assert.True(t, ok)
+	assert.Equal(t, "AS64502 AS15169", prev.Str())
 }
 
 // Helper wrapper for span testing
@@ -247,4 +546,913 @@ type spanWrapper struct {
 			Get(string) (interface{ Int() int64 }, bool)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestRunTraceUnresolvableTarget(t *testing.T) {
+	cfg := &Config{
+		Protocol: "icmp",
+		MaxHops:  30,
+		Timeout:  5 * time.Second,
+	}
+	sink := new(consumertest.MetricsSink)
+	tr, err := newTracer(cfg, zap.NewNop())
+	require.NoError(t, err)
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		tracer:   tr,
+	}
+	target := TargetConfig{Endpoint: "unresolvable.invalid"}
+
+	r.runTrace(context.Background(), target)
+	r.runTrace(context.Background(), target)
+
+	require.Len(t, sink.AllMetrics(), 2)
+	for i, md := range sink.AllMetrics() {
+		endpoint, ok := md.ResourceMetrics().At(0).Resource().Attributes().Get("ztrace.target")
+		require.True(t, ok)
+		assert.Equal(t, "unresolvable.invalid", endpoint.Str())
+
+		values := map[string]int64{}
+		sm := md.ResourceMetrics().At(0).ScopeMetrics().At(0)
+		for j := 0; j < sm.Metrics().Len(); j++ {
+			metric := sm.Metrics().At(j)
+			switch metric.Type() {
+			case pmetric.MetricTypeSum:
+				assert.True(t, metric.Sum().IsMonotonic())
+				values[metric.Name()] = metric.Sum().DataPoints().At(0).IntValue()
+			case pmetric.MetricTypeGauge:
+				values[metric.Name()] = metric.Gauge().DataPoints().At(0).IntValue()
+			}
+		}
+		assert.Equal(t, map[string]int64{
+			"ztrace.dns.resolve_error": int64(i + 1),
+			"ztrace.target.reachable":  0,
+		}, values)
+	}
+}
+
+// panickingProber panics on every probe
+type panickingProber struct{}
+
+func (panickingProber) ProbeHop(int, net.IP) (Hop, error) {
+	panic("probe failed")
+}
+
+func TestRunTracePanicRecovery(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", CollectionInterval: time.Minute, Timeout: 5 * time.Second, MaxHops: 30},
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		tracer:   newScriptedTracer(panickingProber{}),
+		clock:    clk,
+	}
+	target := TargetConfig{Endpoint: "127.0.0.1"}
+
+	// The collect goroutine survives the panic and traces the target again on the next tick
+	r.reconcileTargets(context.Background(), []TargetConfig{target})
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 && clk.tickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	clk.Advance(time.Minute)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	r.stopTargets()
+	r.wg.Wait()
+
+	for i, md := range sink.AllMetrics() {
+		metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "ztrace.panic", metric.Name())
+		assert.True(t, metric.Sum().IsMonotonic())
+		assert.Equal(t, int64(i+1), metric.Sum().DataPoints().At(0).IntValue())
+	}
+}
+
+func TestConvertToMetricsPingOnly(t *testing.T) {
+	r := &ztraceReceiver{
+		config: &Config{
+			Protocol: "icmp",
+			PingOnly: true,
+		},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops:          []Hop{{TTL: 30, IP: "93.184.216.34", Latency: 22, RTTs: []float64{20, 24}}},
+		TotalLatency:  22,
+		TargetReached: true,
+	}
+
+	metrics := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com"})
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	var names []string
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		names = append(names, sm.Metrics().At(i).Name())
+	}
+	assert.ElementsMatch(t, []string{"ztrace.total_latency", "ztrace.target.reachable"}, names)
+}
+
+func TestConvertToMetricsPorts(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "tcp", PingOnly: true},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops:          []Hop{{TTL: 30, IP: "93.184.216.34", Latency: 22}},
+		TargetReached: true,
+		Ports:         []Port{{Port: 80, Reachable: true, Latency: 21}, {Port: 8080}},
+	}
+
+	metrics := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com", Port: 443, Ports: []int{80, 8080}})
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	values := map[int64]int64{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.target.reachable" {
+			continue
+		}
+		dps := metric.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			port, ok := dps.At(j).Attributes().Get("port")
+			if !ok {
+				values[0] = dps.At(j).IntValue()
+				continue
+			}
+			values[port.Int()] = dps.At(j).IntValue()
+		}
+	}
+	assert.Equal(t, map[int64]int64{0: 1, 80: 1, 8080: 0}, values)
+}
+
+func TestConvertLatencyUnit(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", LatencyUnit: "us", HopLatencyHistogram: true},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops:          []Hop{{TTL: 1, IP: "10.0.0.1", Latency: 0.25, RTTs: []float64{0.2, 0.3}}},
+		TotalLatency:  0.25,
+		TargetReached: true,
+	}
+	target := TargetConfig{Endpoint: "10.0.0.1"}
+
+	sm := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		switch metric.Name() {
+		case "ztrace.hop.latency", "ztrace.total_latency":
+			assert.Equal(t, "us", metric.Unit())
+			assert.InDelta(t, 250.0, metric.Gauge().DataPoints().At(0).DoubleValue(), 1e-9)
+		case "ztrace.hop.rtt":
+			assert.Equal(t, "us", metric.Unit())
+			dp := metric.Histogram().DataPoints().At(0)
+			assert.InDelta(t, 500.0, dp.Sum(), 1e-9)
+			assert.InDelta(t, 1000.0, dp.ExplicitBounds().At(0), 1e-9)
+			assert.Equal(t, uint64(2), dp.BucketCounts().At(0))
+		}
+	}
+
+	spans := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	total, ok := spans.At(0).Attributes().Get("total.latency.us")
+	require.True(t, ok)
+	assert.InDelta(t, 250.0, total.Double(), 1e-9)
+	latency, ok := spans.At(1).Attributes().Get("latency.us")
+	require.True(t, ok)
+	assert.InDelta(t, 250.0, latency.Double(), 1e-9)
+	_, ok = spans.At(1).Attributes().Get("latency.ms")
+	assert.False(t, ok)
+}
+
+func TestConvertToMetricsLatencyStddev(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", LatencyWindow: 10},
+		settings: receivertest.NewNopSettings(),
+		history:  newHopHistory(10),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+
+	var metrics pmetric.Metrics
+	for _, latency := range []float64{10, 12, 14} {
+		result := &Result{Hops: []Hop{{TTL: 1, IP: "192.168.1.1", Latency: latency}}}
+		r.history.record(r.config.targetKey(target), result)
+		metrics = r.convertToMetrics(result, target)
+	}
+
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	var found bool
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.hop.latency.stddev" {
+			continue
+		}
+		found = true
+		dp := metric.Gauge().DataPoints().At(0)
+		assert.InDelta(t, 1.632993, dp.DoubleValue(), 1e-6)
+		ip, ok := dp.Attributes().Get("ip")
+		assert.True(t, ok)
+		assert.Equal(t, "192.168.1.1", ip.Str())
+	}
+	assert.True(t, found)
+}
+
+func TestConvertToTracesLatencySpike(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", LatencyWindow: 10, LatencySpikeFactor: 3},
+		settings: receivertest.NewNopSettings(),
+		history:  newHopHistory(10),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+
+	spikes := func(latency float64) []ptrace.SpanEvent {
+		result := &Result{Hops: []Hop{{TTL: 1, IP: "192.168.1.1", Latency: latency}}}
+		r.history.record(r.config.targetKey(target), result)
+		ss := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0)
+		var events []ptrace.SpanEvent
+		for i := 0; i < ss.Spans().Len(); i++ {
+			for j := 0; j < ss.Spans().At(i).Events().Len(); j++ {
+				if event := ss.Spans().At(i).Events().At(j); event.Name() == "latency_spike" {
+					events = append(events, event)
+				}
+			}
+		}
+		return events
+	}
+
+	// Not enough history for a baseline yet
+	assert.Empty(t, spikes(10))
+	assert.Empty(t, spikes(12))
+	assert.Empty(t, spikes(40))
+
+	// Within the factor of the median of 10, 12, 40, the next baseline is the median of 10, 12, 30, 40
+	assert.Empty(t, spikes(30))
+
+	events := spikes(80)
+	require.Len(t, events, 1)
+	baseline, ok := events[0].Attributes().Get("baseline.ms")
+	assert.True(t, ok)
+	assert.InDelta(t, 21.0, baseline.Double(), 1e-9)
+	latency, ok := events[0].Attributes().Get("latency.ms")
+	assert.True(t, ok)
+	assert.InDelta(t, 80.0, latency.Double(), 1e-9)
+}
+
+func TestConvertToMetricsExemplars(t *testing.T) {
+	r := &ztraceReceiver{
+		config:        &Config{Protocol: "icmp", SpanTopology: "star"},
+		settings:      receivertest.NewNopSettings(),
+		traceConsumer: consumertest.NewNop(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2, IP: "93.184.216.34", Latency: 20.1},
+		},
+		TotalLatency:  20.1,
+		TargetReached: true,
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+
+	metrics := r.convertToMetrics(result, target)
+	spans := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 3, spans.Len())
+
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	var hop int
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.hop.latency" {
+			continue
+		}
+		dp := metric.Gauge().DataPoints().At(0)
+		require.Equal(t, 1, dp.Exemplars().Len())
+		exemplar := dp.Exemplars().At(0)
+		span := spans.At(hop + 1)
+		assert.False(t, exemplar.TraceID().IsEmpty())
+		assert.Equal(t, span.TraceID(), exemplar.TraceID())
+		assert.Equal(t, span.SpanID(), exemplar.SpanID())
+		assert.Equal(t, result.Hops[hop].Latency, exemplar.DoubleValue())
+		hop++
+	}
+	assert.Equal(t, 2, hop)
+}
+
+func TestConvertToMetricsWithoutTracesHasNoExemplars(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{Hops: []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 2.5}}}
+
+	dp := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com"}).ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0)
+	assert.Equal(t, 0, dp.Exemplars().Len())
+}
+
+func TestConvertToTracesTimedOutHop(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", SpanTopology: "star"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2, TimedOut: true},
+			{TTL: 3, IP: "93.184.216.34", Latency: 20.1},
+		},
+		TotalLatency:  20.1,
+		TargetReached: true,
+	}
+
+	spans := r.convertToTraces(result, TargetConfig{Endpoint: "example.com"}).ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 4, spans.Len())
+	placeholder := spans.At(2)
+	assert.Equal(t, "hop 2: *", placeholder.Name())
+	assert.Equal(t, ptrace.StatusCodeError, placeholder.Status().Code())
+	ttl, ok := placeholder.Attributes().Get("ttl")
+	require.True(t, ok)
+	assert.Equal(t, int64(2), ttl.Int())
+
+	sm := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com"}).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	states := map[int64]int64{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.hop.state" {
+			continue
+		}
+		dp := metric.Gauge().DataPoints().At(0)
+		ttl, _ := dp.Attributes().Get("ttl")
+		states[ttl.Int()] = dp.IntValue()
+	}
+	assert.Equal(t, map[int64]int64{1: hopStateOK, 2: hopStateTimeout, 3: hopStateOK}, states)
+}
+
+func TestConvertToMetricsTimedOutHop(t *testing.T) {
+	r := &ztraceReceiver{
+		config:        &Config{Protocol: "icmp"},
+		settings:      receivertest.NewNopSettings(),
+		traceConsumer: consumertest.NewNop(),
+		history:       newHopHistory(10),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5, RTTs: []float64{2.5}},
+			{TTL: 2, TimedOut: true, PacketLoss: 100},
+		},
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	for i := 0; i < 3; i++ {
+		r.history.record(r.config.targetKey(target), result)
+	}
+
+	// A hop no probe answered reports its state and loss, but no latency, exemplar or deviation
+	sm := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	ttls := map[string][]int64{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Type() != pmetric.MetricTypeGauge {
+			continue
+		}
+		dps := metric.Gauge().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			if ttl, ok := dps.At(j).Attributes().Get("ttl"); ok {
+				ttls[metric.Name()] = append(ttls[metric.Name()], ttl.Int())
+			}
+		}
+	}
+	assert.Equal(t, []int64{1}, ttls["ztrace.hop.latency"])
+	assert.Equal(t, []int64{1}, ttls["ztrace.hop.latency.stddev"])
+	assert.Equal(t, []int64{1, 2}, ttls["ztrace.hop.state"])
+	assert.Equal(t, []int64{2}, ttls["ztrace.hop.packet_loss"])
+}
+
+func TestConvertToTracesChainTopology(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", SpanTopology: "chain"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2, IP: "10.0.0.1", Latency: 10.2},
+			{TTL: 3, IP: "93.184.216.34", Latency: 20.1},
+		},
+		TotalLatency:  20.1,
+		TargetReached: true,
+	}
+
+	ss := r.convertToTraces(result, TargetConfig{Endpoint: "example.com"}).ResourceSpans().At(0).ScopeSpans().At(0)
+	require.Equal(t, 4, ss.Spans().Len())
+	root := ss.Spans().At(0)
+	previous := root
+	for i := 1; i < ss.Spans().Len(); i++ {
+		hop := ss.Spans().At(i)
+		assert.Equal(t, previous.SpanID(), hop.ParentSpanID())
+		if i == 1 {
+			assert.Equal(t, 0, hop.Links().Len())
+		} else {
+			require.Equal(t, 1, hop.Links().Len())
+			assert.Equal(t, root.SpanID(), hop.Links().At(0).SpanID())
+		}
+		previous = hop
+	}
+}
+
+func TestConvertToTracesEventsMode(t *testing.T) {
+	r := &ztraceReceiver{
+		config:        &Config{Protocol: "icmp", SpanMode: "events", PacketLossEventThreshold: 50},
+		settings:      receivertest.NewNopSettings(),
+		traceConsumer: consumertest.NewNop(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2},
+			{TTL: 3, IP: "10.0.0.1", Latency: 10.2, PacketLoss: 66.7},
+			{TTL: 4, IP: "93.184.216.34", Latency: 20.1},
+		},
+		TotalLatency:  20.1,
+		TargetReached: true,
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+
+	// A single span with an event per hop
+	ss := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0)
+	require.Equal(t, 1, ss.Spans().Len())
+	root := ss.Spans().At(0)
+	require.Equal(t, len(result.Hops), root.Events().Len())
+	for i := 0; i < root.Events().Len(); i++ {
+		event := root.Events().At(i)
+		assert.Equal(t, "hop", event.Name())
+		ttl, ok := event.Attributes().Get("ttl")
+		require.True(t, ok)
+		assert.Equal(t, int64(result.Hops[i].TTL), ttl.Int())
+		ip, ok := event.Attributes().Get("ip")
+		require.True(t, ok)
+		assert.Equal(t, result.Hops[i].IP, ip.Str())
+		latency, ok := event.Attributes().Get("latency.ms")
+		require.True(t, ok)
+		assert.InDelta(t, result.Hops[i].Latency, latency.Double(), 1e-9)
+	}
+	timedOut, ok := root.Events().At(1).Attributes().Get("timed_out")
+	require.True(t, ok)
+	assert.True(t, timedOut.Bool())
+	highLoss, ok := root.Events().At(2).Attributes().Get("high_packet_loss")
+	require.True(t, ok)
+	assert.True(t, highLoss.Bool())
+
+	// The latency exemplars point to the root span
+	latency, ok := firstMetric(r.convertToMetrics(result, target), "ztrace.hop.latency")
+	require.True(t, ok)
+	assert.Equal(t, result.ids().root, latency.Gauge().DataPoints().At(0).Exemplars().At(0).SpanID())
+}
+
+// blockingProber never answers a probe until it is closed
+type blockingProber struct {
+	closed chan struct{}
+}
+
+func (p *blockingProber) ProbeHop(int, net.IP) (Hop, error) {
+	<-p.closed
+	return Hop{}, errors.New("prober closed")
+}
+
+func (p *blockingProber) Close() error {
+	close(p.closed)
+	return nil
+}
+
+func TestShutdownDrainTimeout(t *testing.T) {
+	p := &blockingProber{closed: make(chan struct{})}
+	r := &ztraceReceiver{
+		config: &Config{
+			Protocol:           "icmp",
+			MaxHops:            30,
+			CollectionInterval: time.Hour,
+			Timeout:            time.Hour,
+		},
+		settings: receivertest.NewNopSettings(),
+		tracer:   newScriptedTracer(p),
+		clock:    realClock{},
+	}
+	r.reconcileTargets(context.Background(), []TargetConfig{{Endpoint: "127.0.0.1"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, r.Shutdown(ctx), context.DeadlineExceeded)
+
+	// Closing the tracer released the hung probe
+	select {
+	case <-p.closed:
+	default:
+		t.Fatal("prober was not closed")
+	}
+	r.wg.Wait()
+}
+
+func TestStartSkipsDisabledTargets(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Hour
+	cfg.Targets = []TargetConfig{
+		{Endpoint: "127.0.0.1"},
+		{Endpoint: "127.0.0.2", Enabled: new(bool)},
+	}
+	sink := new(consumertest.MetricsSink)
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+	}
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	// Only the enabled target was traced and shutdown waited for its goroutine alone
+	require.Len(t, sink.AllMetrics(), 1)
+	endpoint, ok := sink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Get("ztrace.target")
+	require.True(t, ok)
+	assert.Equal(t, "127.0.0.1", endpoint.Str())
+}
+
+func TestStartContextCancellation(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Minute
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}, {Endpoint: "127.0.0.2"}}
+	sink := new(consumertest.MetricsSink)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		clock:    clk,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 && clk.tickerCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// Cancelling the Start context stops the collect loops without Shutdown
+	cancel()
+	stopped := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("collect loops did not stop after the start context was cancelled")
+	}
+	clk.Advance(time.Minute)
+	assert.Len(t, sink.AllMetrics(), 2)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestConcurrentReconcileAndShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Minute
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}}
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: consumertest.NewNop(),
+		clock:    clk,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Targets are started and stopped while the receiver shuts down, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				targets := []TargetConfig{{Endpoint: fmt.Sprintf("127.0.0.%d", 2+(i+j)%3)}}
+				if j%2 == 0 {
+					targets = append(targets, TargetConfig{Endpoint: "127.0.0.1"})
+				}
+				r.reconcileTargets(context.Background(), targets)
+				clk.Advance(time.Minute)
+			}
+		}(i)
+	}
+	require.NoError(t, r.Shutdown(context.Background()))
+	wg.Wait()
+
+	// Once shut down no target is started again and every collect loop returned
+	started, stopped := r.reconcileTargets(context.Background(), []TargetConfig{{Endpoint: "127.0.0.5"}})
+	assert.Zero(t, started)
+	assert.Zero(t, stopped)
+	assert.Empty(t, r.running)
+	r.wg.Wait()
+}
+
+func TestSourceAttributes(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.IncludeSourceHost = true
+	cfg.SourceRegion = "eu-west-1"
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(context.Background()))
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	result := &Result{Hops: []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 2.5}}}
+	target := TargetConfig{Endpoint: "example.com"}
+	for _, attrs := range []pcommon.Map{
+		r.convertToMetrics(result, target).ResourceMetrics().At(0).Resource().Attributes(),
+		r.convertToTraces(result, target).ResourceSpans().At(0).Resource().Attributes(),
+	} {
+		host, ok := attrs.Get("ztrace.source.host")
+		assert.True(t, ok)
+		assert.Equal(t, hostname, host.Str())
+		region, ok := attrs.Get("ztrace.source.region")
+		assert.True(t, ok)
+		assert.Equal(t, "eu-west-1", region.Str())
+		_, ok = attrs.Get("ztrace.source.site")
+		assert.False(t, ok)
+	}
+}
+
+func TestConvertToMetricsHopState(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2, IP: "10.0.0.1", Latency: 10.2, PacketLoss: 25},
+			{TTL: 3, TimedOut: true, PacketLoss: 100},
+		},
+	}
+
+	metrics := r.convertToMetrics(result, TargetConfig{Endpoint: "example.com"})
+	sm := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	states := map[int64]int64{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		if metric.Name() != "ztrace.hop.state" {
+			continue
+		}
+		dp := metric.Gauge().DataPoints().At(0)
+		ttl, ok := dp.Attributes().Get("ttl")
+		require.True(t, ok)
+		states[ttl.Int()] = dp.IntValue()
+	}
+	assert.Equal(t, map[int64]int64{
+		1: hopStateOK,
+		2: hopStatePartialLoss,
+		3: hopStateTimeout,
+	}, states)
+}
+
+func TestHopScopeAttribute(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5},
+			{TTL: 2, IP: "100.64.0.1", Latency: 8.1},
+			{TTL: 3, IP: "8.8.8.8", Latency: 12.7},
+			{TTL: 4},
+		},
+	}
+	want := map[int64]string{1: "private", 2: "reserved", 3: "public"}
+
+	scopes := map[int64]string{}
+	sm := r.convertToMetrics(result, TargetConfig{Endpoint: "8.8.8.8"}).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		if sm.Metrics().At(i).Name() != "ztrace.hop.latency" {
+			continue
+		}
+		attrs := sm.Metrics().At(i).Gauge().DataPoints().At(0).Attributes()
+		ttl, _ := attrs.Get("ttl")
+		if scope, ok := attrs.Get("ztrace.hop.scope"); ok {
+			scopes[ttl.Int()] = scope.Str()
+		}
+	}
+	assert.Equal(t, want, scopes)
+
+	scopes = map[int64]string{}
+	ss := r.convertToTraces(result, TargetConfig{Endpoint: "8.8.8.8"}).ResourceSpans().At(0).ScopeSpans().At(0)
+	for i := 0; i < ss.Spans().Len(); i++ {
+		attrs := ss.Spans().At(i).Attributes()
+		ttl, _ := attrs.Get("ttl")
+		if scope, ok := attrs.Get("ztrace.hop.scope"); ok {
+			scopes[ttl.Int()] = scope.Str()
+		}
+	}
+	assert.Equal(t, want, scopes)
+}
+
+func TestHopReplyProtocolAttribute(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "udp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		TargetReached: true,
+		Hops: []Hop{
+			{TTL: 1, IP: "192.168.1.1", Latency: 2.5, ReplyProtocol: replyICMPTimeExceeded},
+			{TTL: 2},
+			{TTL: 3, IP: "8.8.8.8", Latency: 12.7, ReplyProtocol: replyICMPPortUnreachable},
+		},
+	}
+	want := map[int64]string{1: "icmp_time_exceeded", 3: "icmp_port_unreachable"}
+
+	replies := map[int64]string{}
+	sm := r.convertToMetrics(result, TargetConfig{Endpoint: "8.8.8.8"}).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		if sm.Metrics().At(i).Name() != "ztrace.hop.latency" {
+			continue
+		}
+		attrs := sm.Metrics().At(i).Gauge().DataPoints().At(0).Attributes()
+		ttl, _ := attrs.Get("ttl")
+		if reply, ok := attrs.Get("ztrace.hop.reply_protocol"); ok {
+			replies[ttl.Int()] = reply.Str()
+		}
+	}
+	assert.Equal(t, want, replies)
+
+	replies = map[int64]string{}
+	ss := r.convertToTraces(result, TargetConfig{Endpoint: "8.8.8.8"}).ResourceSpans().At(0).ScopeSpans().At(0)
+	for i := 0; i < ss.Spans().Len(); i++ {
+		attrs := ss.Spans().At(i).Attributes()
+		ttl, _ := attrs.Get("ttl")
+		if reply, ok := attrs.Get("ztrace.hop.reply_protocol"); ok {
+			replies[ttl.Int()] = reply.Str()
+		}
+	}
+	assert.Equal(t, want, replies)
+}
+
+func TestConvertMaxDataPoints(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "tcp", MaxDataPoints: 100, HopLatencyHistogram: true},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{TargetReached: true, TotalLatency: 1.5, ProbesSent: 600, ProbesReceived: 540}
+	for ttl := 1; ttl <= 150; ttl++ {
+		result.Hops = append(result.Hops, Hop{TTL: ttl, IP: "192.168.1.1", Latency: 1.5, RTTs: []float64{1, 2}, Jitter: 1, PacketLoss: 10})
+	}
+	for port := 8000; port < 8040; port++ {
+		result.Ports = append(result.Ports, Port{Port: port, Reachable: true, Latency: 1.5})
+	}
+	target := TargetConfig{Endpoint: "example.com", Port: 80}
+
+	// The hops beyond the budget are dropped and counted, the summary is kept whole
+	md := r.convertToMetrics(result, target)
+	assert.LessOrEqual(t, md.DataPointCount(), 100)
+	sm := md.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	var hops, truncated int64
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		switch metric.Name() {
+		case "ztrace.hop.latency":
+			hops++
+		case "ztrace.hops.truncated":
+			truncated = metric.Gauge().DataPoints().At(0).IntValue()
+		case "ztrace.target.reachable":
+			assert.Equal(t, 41, metric.Gauge().DataPoints().Len())
+		}
+	}
+	assert.Positive(t, hops)
+	assert.Positive(t, truncated)
+	assert.Equal(t, int64(len(result.Hops)), hops+truncated)
+
+	// Every cap leaves the summary whole and fills the rest with hops
+	for limit := 100; limit <= 400; limit += 7 {
+		r.config.MaxDataPoints = limit
+		assert.LessOrEqual(t, r.convertToMetrics(result, target).DataPointCount(), limit)
+	}
+	r.config.MaxDataPoints = 100
+
+	spans := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	assert.Equal(t, 100, spans.Len())
+	dropped, ok := spans.At(0).Attributes().Get("hops.truncated")
+	require.True(t, ok)
+	assert.Equal(t, int64(51), dropped.Int())
+
+	// Traces within the budget are complete
+	r.config.MaxDataPoints = 0
+	assert.Equal(t, 150*6+41+5, r.convertToMetrics(result, target).DataPointCount())
+	spans = r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	assert.Equal(t, 151, spans.Len())
+	_, ok = spans.At(0).Attributes().Get("hops.truncated")
+	assert.False(t, ok)
+}
+
+func TestConvertTraceTruncated(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "udp", MaxTraceDuration: 10 * time.Second},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops:      []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 1.5, RTTs: []float64{1.5}}},
+		Truncated: true,
+	}
+	target := TargetConfig{Endpoint: "example.com", Port: 80}
+
+	truncatedValue := func() (int64, bool) {
+		sm := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0)
+		for i := 0; i < sm.Metrics().Len(); i++ {
+			if metric := sm.Metrics().At(i); metric.Name() == "ztrace.trace.truncated" {
+				return metric.Gauge().DataPoints().At(0).IntValue(), true
+			}
+		}
+		return 0, false
+	}
+
+	// The hops walked before the cap are still reported
+	value, ok := truncatedValue()
+	require.True(t, ok)
+	assert.Equal(t, int64(1), value)
+	spans := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	assert.Equal(t, 2, spans.Len())
+	attr, ok := spans.At(0).Attributes().Get("trace.truncated")
+	require.True(t, ok)
+	assert.True(t, attr.Bool())
+
+	result.Truncated = false
+	value, ok = truncatedValue()
+	require.True(t, ok)
+	assert.Zero(t, value)
+
+	// Without the cap the indicator is not emitted
+	r.config.MaxTraceDuration = 0
+	_, ok = truncatedValue()
+	assert.False(t, ok)
+}
+
+func TestAttributeConvention(t *testing.T) {
+	result := &Result{
+		Hops:       []Hop{{TTL: 1, IP: "93.184.216.34", Latency: 20.1}},
+		ResolvedIP: "93.184.216.34",
+	}
+	target := TargetConfig{Endpoint: "example.com", Port: 443}
+
+	tests := []struct {
+		convention string
+		resource   map[string]any
+		other      string // target attribute of the other convention
+		hopIP      string
+	}{
+		{
+			convention: "ztrace",
+			resource: map[string]any{
+				"ztrace.target":    "example.com",
+				"ztrace.port":      int64(443),
+				"ztrace.target.ip": "93.184.216.34",
+				"ztrace.protocol":  "tcp",
+			},
+			other: "destination.address",
+			hopIP: "ip",
+		},
+		{
+			convention: "semconv",
+			resource: map[string]any{
+				"destination.address":  "example.com",
+				"destination.port":     int64(443),
+				"network.peer.address": "93.184.216.34",
+				"network.transport":    "tcp",
+			},
+			other: "ztrace.target",
+			hopIP: "network.peer.address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.convention, func(t *testing.T) {
+			r := &ztraceReceiver{
+				config:   &Config{Protocol: "tcp", AttributeConvention: tt.convention},
+				settings: receivertest.NewNopSettings(),
+			}
+			traces := r.convertToTraces(result, target)
+			for _, attrs := range []pcommon.Map{
+				r.convertToMetrics(result, target).ResourceMetrics().At(0).Resource().Attributes(),
+				traces.ResourceSpans().At(0).Resource().Attributes(),
+				r.convertToLogs(result, target, "").ResourceLogs().At(0).Resource().Attributes(),
+			} {
+				for name, want := range tt.resource {
+					got, ok := attrs.Get(name)
+					require.True(t, ok, name)
+					assert.Equal(t, want, got.AsRaw(), name)
+				}
+				_, ok := attrs.Get(tt.other)
+				assert.False(t, ok)
+			}
+
+			hopSpan := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1)
+			ip, ok := hopSpan.Attributes().Get(tt.hopIP)
+			require.True(t, ok)
+			assert.Equal(t, "93.184.216.34", ip.Str())
+		})
+	}
+}
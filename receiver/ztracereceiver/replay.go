@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// replaySource serves trace results recorded with raw_output_path instead of probing the network.
+// The results of each target are returned in recorded order and start over once exhausted.
+type replaySource struct {
+	mu       sync.Mutex
+	results  map[string][]Result
+	position map[string]int
+}
+
+// newReplaySource loads all results of the NDJSON file at path
+func newReplaySource(path string) (*replaySource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	defer file.Close()
+
+	s := &replaySource{
+		results:  make(map[string][]Result),
+		position: make(map[string]int),
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse replay file line %d: %w", line, err)
+		}
+		s.results[result.Target] = append(s.results[result.Target], result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read replay file: %w", err)
+	}
+	return s, nil
+}
+
+// next returns a copy of the next recorded result of the target
+func (s *replaySource) next(endpoint string) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := s.results[endpoint]
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no recorded results for target %s", endpoint)
+	}
+	result := results[s.position[endpoint]%len(results)]
+	s.position[endpoint]++
+	result.Hops = slices.Clone(result.Hops)
+	return &result, nil
+}
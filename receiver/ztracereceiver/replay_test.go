@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReplaySource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	w, err := newRawWriter(path)
+	require.NoError(t, err)
+	for _, result := range []*Result{
+		{Target: "example.com", Hops: []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 2, RTTs: []float64{2}}}, TotalLatency: 2},
+		{Target: "example.org", Hops: []Hop{{TTL: 1}}},
+		{Target: "example.com", Hops: []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 3, RTTs: []float64{3}}}, TotalLatency: 3, TargetReached: true},
+	} {
+		require.NoError(t, w.write(result))
+	}
+	require.NoError(t, w.close())
+
+	cfg := &Config{
+		Protocol:   "icmp",
+		Source:     "file",
+		SourcePath: path,
+	}
+	tr, err := newTracer(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	// Results are replayed in recorded order and start over at the end
+	var latencies []float64
+	for i := 0; i < 3; i++ {
+		result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "example.com"}, cfg)
+		require.NoError(t, err)
+		latencies = append(latencies, result.TotalLatency)
+	}
+	assert.Equal(t, []float64{2, 3, 2}, latencies)
+
+	// Probe counters are kept by the tracer, not taken from the recording
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "example.com"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), result.ProbesSent)
+	assert.Equal(t, int64(4), result.ProbesReceived)
+
+	_, err = tr.trace(context.Background(), TargetConfig{Endpoint: "example.net"}, cfg)
+	assert.ErrorContains(t, err, "no recorded results for target example.net")
+}
+
+func TestReplaySourceInvalidFile(t *testing.T) {
+	_, err := newReplaySource(filepath.Join(t.TempDir(), "missing.ndjson"))
+	assert.ErrorContains(t, err, "failed to open replay file")
+
+	path := filepath.Join(t.TempDir(), "results.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte("{\"target\":\"example.com\"}\nnot json\n"), 0o600))
+	_, err = newReplaySource(path)
+	assert.ErrorContains(t, err, "line 2")
+}
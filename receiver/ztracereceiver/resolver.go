@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// resolverEntry is the cached resolution of one endpoint, ready is closed once ip or err is set
+type resolverEntry struct {
+	ready   chan struct{}
+	ip      net.IP
+	err     error
+	expires time.Time
+}
+
+// resolver caches the IPv4 address of target endpoints for a fixed TTL.
+// Concurrent lookups of the same endpoint share a single DNS query.
+type resolver struct {
+	ttl    time.Duration
+	clock  clock // expires the cached addresses
+	lookup func(ctx context.Context, host string) ([]net.IP, error)
+
+	mu      sync.Mutex
+	entries map[string]*resolverEntry
+}
+
+func newResolver(ttl time.Duration) *resolver {
+	return &resolver{
+		ttl:   ttl,
+		clock: realClock{},
+		lookup: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip4", host)
+		},
+		entries: make(map[string]*resolverEntry),
+	}
+}
+
+// resolve returns the address of the endpoint, from the cache while it has not expired
+func (r *resolver) resolve(ctx context.Context, endpoint string) (net.IP, error) {
+	r.mu.Lock()
+	entry, ok := r.entries[endpoint]
+	if ok && (!isReady(entry) || r.clock.Now().Before(entry.expires)) {
+		r.mu.Unlock()
+		select {
+		case <-entry.ready:
+			return entry.ip, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	entry = &resolverEntry{ready: make(chan struct{})}
+	r.entries[endpoint] = entry
+	r.mu.Unlock()
+
+	addrs, err := r.lookup(ctx, endpoint)
+	if err == nil {
+		entry.ip = addrs[0]
+		entry.expires = r.clock.Now().Add(r.ttl)
+	} else {
+		// Failures are not cached, the next trace resolves again
+		entry.err = err
+	}
+	close(entry.ready)
+	return entry.ip, entry.err
+}
+
+func isReady(entry *resolverEntry) bool {
+	select {
+	case <-entry.ready:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverCachesUntilExpiry(t *testing.T) {
+	var lookups atomic.Int32
+	r := newResolver(time.Hour)
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r.clock = clk
+	r.lookup = func(_ context.Context, _ string) ([]net.IP, error) {
+		lookups.Add(1)
+		return []net.IP{net.ParseIP("192.0.2.10")}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		ip, err := r.resolve(context.Background(), "example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "192.0.2.10", ip.String())
+	}
+	assert.Equal(t, int32(1), lookups.Load())
+
+	// An expired entry is resolved again
+	clk.Advance(time.Hour - time.Second)
+	_, err := r.resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), lookups.Load())
+	clk.Advance(time.Second)
+	_, err = r.resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), lookups.Load())
+
+	// Without a TTL every resolution looks the endpoint up
+	r.ttl = 0
+	clk.Advance(time.Hour)
+	_, err = r.resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	_, err = r.resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), lookups.Load())
+}
+
+func TestResolverSharesConcurrentLookups(t *testing.T) {
+	var lookups atomic.Int32
+	release := make(chan struct{})
+	r := newResolver(time.Hour)
+	r.lookup = func(_ context.Context, _ string) ([]net.IP, error) {
+		lookups.Add(1)
+		<-release
+		return []net.IP{net.ParseIP("192.0.2.10")}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ip, err := r.resolve(context.Background(), "example.com")
+			assert.NoError(t, err)
+			assert.Equal(t, "192.0.2.10", ip.String())
+		}()
+	}
+	require.Eventually(t, func() bool { return lookups.Load() == 1 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), lookups.Load())
+}
+
+func TestResolverDoesNotCacheFailures(t *testing.T) {
+	var lookups atomic.Int32
+	r := newResolver(time.Hour)
+	r.lookup = func(_ context.Context, _ string) ([]net.IP, error) {
+		if lookups.Add(1) == 1 {
+			return nil, errors.New("no such host")
+		}
+		return []net.IP{net.ParseIP("192.0.2.10")}, nil
+	}
+
+	_, err := r.resolve(context.Background(), "example.com")
+	assert.ErrorContains(t, err, "no such host")
+	ip, err := r.resolve(context.Background(), "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "192.0.2.10", ip.String())
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// pushMetrics pushes the metrics of a trace to the next consumer. The batches queued by the
+// earlier traces of the target are pushed first, in order, and a rejected push is retried with
+// backoff, then queued when export_retry allows it. A permanent error is neither retried nor
+// queued, pushing the same metrics again cannot succeed.
+func (r *ztraceReceiver) pushMetrics(ctx context.Context, target TargetConfig, md pmetric.Metrics) error {
+	r.drainQueue(ctx, target)
+	err := r.retryMetrics(ctx, target, md)
+	if err != nil && !consumererror.IsPermanent(err) && r.config.ExportRetry.QueueSize > 0 {
+		r.enqueue(target, md)
+	}
+	return err
+}
+
+// retryMetrics pushes the metrics, retrying a rejected push up to max_retries times while ctx allows
+func (r *ztraceReceiver) retryMetrics(ctx context.Context, target TargetConfig, md pmetric.Metrics) error {
+	retry := r.config.ExportRetry
+	backoff := retry.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := r.consumeMetrics(ctx, target, md, attempt < retry.MaxRetries || retry.QueueSize > 0)
+		if err == nil || consumererror.IsPermanent(err) || attempt >= retry.MaxRetries {
+			return err
+		}
+		r.settings.Logger.Debug("Retrying rejected metrics",
+			zap.String("target", target.Endpoint),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.Error(err))
+		select {
+		case <-r.clock.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+		backoff = min(2*backoff, retry.MaxBackoff)
+	}
+}
+
+// consumeMetrics pushes the metrics to the next consumer. The consumer owns what it is given, a
+// copy is pushed when the metrics may be pushed again.
+func (r *ztraceReceiver) consumeMetrics(ctx context.Context, target TargetConfig, md pmetric.Metrics, keep bool) error {
+	if keep {
+		clone := pmetric.NewMetrics()
+		md.CopyTo(clone)
+		md = clone
+	}
+	return r.export(target, signalMetrics, func() error { return r.consumer.ConsumeMetrics(ctx, md) })
+}
+
+// drainQueue pushes the queued batches of the target until one is rejected again. A batch
+// rejected with a permanent error is dropped.
+func (r *ztraceReceiver) drainQueue(ctx context.Context, target TargetConfig) {
+	key := r.config.targetKey(target)
+	r.queueMu.Lock()
+	queued := r.queue[key]
+	r.queueMu.Unlock()
+	if len(queued) == 0 {
+		return
+	}
+
+	// Only the collect loop of the target pushes its batches, the queue cannot change meanwhile
+	pushed := 0
+	for _, md := range queued {
+		err := r.consumeMetrics(ctx, target, md, true)
+		if consumererror.IsPermanent(err) {
+			r.exports.drop(key, signalMetrics)
+			r.settings.Logger.Warn("Queued metrics rejected permanently, dropped them",
+				zap.String("target", target.Endpoint),
+				zap.Error(err))
+		} else if err != nil {
+			r.settings.Logger.Debug("Queued metrics rejected again",
+				zap.String("target", target.Endpoint),
+				zap.Int("queued", len(queued)-pushed),
+				zap.Error(err))
+			break
+		}
+		pushed++
+	}
+
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	r.queue[key] = r.queue[key][pushed:]
+}
+
+// enqueue keeps rejected metrics for the next trace of the target, dropping the oldest batch when
+// the queue is full
+func (r *ztraceReceiver) enqueue(target TargetConfig, md pmetric.Metrics) {
+	key := r.config.targetKey(target)
+	r.queueMu.Lock()
+	defer r.queueMu.Unlock()
+	if r.queue == nil {
+		r.queue = make(map[string][]pmetric.Metrics)
+	}
+	queued := append(r.queue[key], md)
+	if len(queued) > r.config.ExportRetry.QueueSize {
+		queued = queued[1:]
+		r.exports.drop(key, signalMetrics)
+		r.settings.Logger.Warn("Export queue is full, dropped the oldest metrics",
+			zap.String("target", target.Endpoint),
+			zap.Int("queue_size", r.config.ExportRetry.QueueSize))
+	}
+	r.queue[key] = queued
+}
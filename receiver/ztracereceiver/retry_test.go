@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+// flakyConsumer rejects the first failures pushes, permanently when permanent is set, and keeps
+// the accepted ones. It takes ownership of every push, clearing the rejected metrics.
+type flakyConsumer struct {
+	failures  int
+	permanent bool
+	pushes    int
+	accepted  []pmetric.Metrics
+}
+
+func (c *flakyConsumer) consumer(t *testing.T) consumer.Metrics {
+	next, err := consumer.NewMetrics(func(_ context.Context, md pmetric.Metrics) error {
+		c.pushes++
+		if c.pushes <= c.failures {
+			md.ResourceMetrics().RemoveIf(func(pmetric.ResourceMetrics) bool { return true })
+			if c.permanent {
+				return consumererror.NewPermanent(errors.New("metrics are malformed"))
+			}
+			return errors.New("exporter queue is full")
+		}
+		c.accepted = append(c.accepted, md)
+		return nil
+	})
+	require.NoError(t, err)
+	return next
+}
+
+func newRetryReceiver(t *testing.T, next *flakyConsumer, retry ExportRetryConfig) *ztraceReceiver {
+	return &ztraceReceiver{
+		config:   &Config{Protocol: "icmp", ExportRetry: retry},
+		settings: receivertest.NewNopSettings(),
+		consumer: next.consumer(t),
+		clock:    realClock{},
+	}
+}
+
+// batch returns metrics told apart by their hop count
+func batch(hops int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(hops)
+	return md
+}
+
+func batches(mds []pmetric.Metrics) []int64 {
+	var values []int64
+	for _, md := range mds {
+		values = append(values, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints().At(0).IntValue())
+	}
+	return values
+}
+
+func TestPushMetricsRetries(t *testing.T) {
+	next := &flakyConsumer{failures: 2}
+	r := newRetryReceiver(t, next, ExportRetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	target := TargetConfig{Endpoint: "127.0.0.1"}
+
+	require.NoError(t, r.pushMetrics(context.Background(), target, batch(1)))
+	assert.Equal(t, 3, next.pushes)
+	assert.Equal(t, []int64{1}, batches(next.accepted))
+	counts := r.exports.counts[exportKey{target: r.config.targetKey(target), signal: signalMetrics}]
+	assert.Equal(t, int64(2), counts.errors)
+	assert.Equal(t, uint64(3), counts.count)
+
+	// Without retries left the push fails and, without a queue, the metrics are lost
+	next.failures = 10
+	assert.Error(t, r.pushMetrics(context.Background(), target, batch(2)))
+	assert.Equal(t, 7, next.pushes)
+	assert.Empty(t, r.queue)
+}
+
+func TestPushMetricsPermanentError(t *testing.T) {
+	next := &flakyConsumer{failures: 1, permanent: true}
+	r := newRetryReceiver(t, next, ExportRetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, QueueSize: 2})
+	target := TargetConfig{Endpoint: "127.0.0.1"}
+
+	// A permanent error is neither retried nor queued
+	err := r.pushMetrics(context.Background(), target, batch(1))
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 1, next.pushes)
+	assert.Empty(t, r.queue[r.config.targetKey(target)])
+
+	// A queued batch rejected permanently is dropped
+	next.failures, next.permanent = next.pushes+10, false
+	assert.Error(t, r.pushMetrics(context.Background(), target, batch(2)))
+	require.Equal(t, []int64{2}, batches(r.queue[r.config.targetKey(target)]))
+	next.failures, next.permanent = next.pushes+1, true
+	require.NoError(t, r.pushMetrics(context.Background(), target, batch(3)))
+	assert.Equal(t, []int64{3}, batches(next.accepted))
+	assert.Empty(t, r.queue[r.config.targetKey(target)])
+	assert.Equal(t, int64(1), r.exports.counts[exportKey{target: r.config.targetKey(target), signal: signalMetrics}].dropped)
+}
+
+func TestPushMetricsRetryStopsWithContext(t *testing.T) {
+	next := &flakyConsumer{failures: 10}
+	r := newRetryReceiver(t, next, ExportRetryConfig{MaxRetries: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, r.pushMetrics(ctx, TargetConfig{Endpoint: "127.0.0.1"}, batch(1)))
+	assert.Equal(t, 1, next.pushes)
+}
+
+func TestPushMetricsQueue(t *testing.T) {
+	next := &flakyConsumer{failures: 3}
+	r := newRetryReceiver(t, next, ExportRetryConfig{QueueSize: 2})
+	target := TargetConfig{Endpoint: "127.0.0.1"}
+
+	// The rejected batches are pushed before the next one, oldest first
+	assert.Error(t, r.pushMetrics(context.Background(), target, batch(1)))
+	assert.Error(t, r.pushMetrics(context.Background(), target, batch(2)))
+	require.NoError(t, r.pushMetrics(context.Background(), target, batch(3)))
+	assert.Equal(t, []int64{1, 2, 3}, batches(next.accepted))
+	assert.Empty(t, r.queue[r.config.targetKey(target)])
+
+	// A full queue drops its oldest batch
+	next.failures = next.pushes + 10
+	for hops := int64(4); hops <= 6; hops++ {
+		assert.Error(t, r.pushMetrics(context.Background(), target, batch(hops)))
+	}
+	assert.Equal(t, []int64{5, 6}, batches(r.queue[r.config.targetKey(target)]))
+	assert.Equal(t, int64(1), r.exports.counts[exportKey{target: r.config.targetKey(target), signal: signalMetrics}].dropped)
+
+	next.failures = 0
+	require.NoError(t, r.pushMetrics(context.Background(), target, batch(7)))
+	assert.Equal(t, []int64{1, 2, 3, 5, 6, 7}, batches(next.accepted))
+}
@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+// initialTTLs are the TTLs common IP stacks send packets with. The initial TTL of a reply
+// is not known, it is assumed to be the smallest of these not below the received TTL.
+var initialTTLs = []int{32, 64, 128, 255}
+
+// asymmetryThreshold is the difference in hops between the forward and the estimated
+// reverse path above which a path is reported asymmetric
+const asymmetryThreshold = 2
+
+// reverseHops estimates the number of hops a reply with the given TTL traveled, 0 when unknown
+func reverseHops(replyTTL int) int {
+	if replyTTL <= 0 {
+		return 0
+	}
+	for _, initial := range initialTTLs {
+		if replyTTL <= initial {
+			return initial - replyTTL + 1
+		}
+	}
+	return 0
+}
+
+// reversePath estimates the hops of the path back from the target and whether it differs
+// from the forward path. ok is false unless the target was reached with a known reply TTL.
+func (r *Result) reversePath() (hops int, asymmetric bool, ok bool) {
+	if !r.TargetReached || len(r.Hops) == 0 {
+		return 0, false, false
+	}
+	target := r.Hops[len(r.Hops)-1]
+	hops = reverseHops(target.ReplyTTL)
+	if hops == 0 {
+		return 0, false, false
+	}
+	diff := hops - target.TTL
+	return hops, diff > asymmetryThreshold || diff < -asymmetryThreshold, true
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestReverseHops(t *testing.T) {
+	tests := []struct {
+		replyTTL int
+		want     int
+	}{
+		{replyTTL: 0, want: 0},
+		{replyTTL: 1, want: 32},
+		{replyTTL: 57, want: 8},
+		{replyTTL: 64, want: 1},
+		{replyTTL: 120, want: 9},
+		{replyTTL: 250, want: 6},
+		{replyTTL: 256, want: 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, reverseHops(tt.replyTTL), "reply TTL %d", tt.replyTTL)
+	}
+}
+
+func TestReversePath(t *testing.T) {
+	path := func(replyTTL int) *Result {
+		return &Result{
+			Hops: []Hop{
+				{TTL: 1, IP: "192.168.1.1", ReplyTTL: 64},
+				{TTL: 2, IP: "10.0.0.1", ReplyTTL: 254},
+				{TTL: 3},
+				{TTL: 4, IP: "93.184.216.34", ReplyTTL: replyTTL},
+			},
+			TargetReached: true,
+		}
+	}
+
+	hops, asymmetric, ok := path(59).reversePath()
+	require.True(t, ok)
+	assert.Equal(t, 6, hops)
+	assert.False(t, asymmetric)
+
+	hops, asymmetric, ok = path(119).reversePath()
+	require.True(t, ok)
+	assert.Equal(t, 10, hops)
+	assert.True(t, asymmetric)
+
+	_, _, ok = path(0).reversePath()
+	assert.False(t, ok, "unknown reply TTL")
+	unreached := path(59)
+	unreached.TargetReached = false
+	_, _, ok = unreached.reversePath()
+	assert.False(t, ok, "target not reached")
+}
+
+func TestConvertReversePath(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   &Config{Protocol: "icmp"},
+		settings: receivertest.NewNopSettings(),
+	}
+	result := &Result{
+		Hops:          []Hop{{TTL: 1, IP: "192.168.1.1", ReplyTTL: 64}, {TTL: 2, IP: "93.184.216.34", ReplyTTL: 119}},
+		TargetReached: true,
+	}
+	target := TargetConfig{Endpoint: "93.184.216.34"}
+
+	root := r.convertToTraces(result, target).ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	reverse, ok := root.Attributes().Get("reverse.hop.count")
+	require.True(t, ok)
+	assert.Equal(t, int64(10), reverse.Int())
+	asymmetric, ok := root.Attributes().Get("path.asymmetric")
+	require.True(t, ok)
+	assert.True(t, asymmetric.Bool())
+
+	sm := r.convertToMetrics(result, target).ResourceMetrics().At(0).ScopeMetrics().At(0)
+	var found bool
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		if metric := sm.Metrics().At(i); metric.Name() == "ztrace.reverse_hop_count" {
+			found = true
+			assert.Equal(t, int64(10), metric.Gauge().DataPoints().At(0).IntValue())
+		}
+	}
+	assert.True(t, found, "reverse hop count metric not found")
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"net"
+)
+
+// Values of the ztrace.hop.scope attribute
+const (
+	scopePrivate  = "private"
+	scopeReserved = "reserved"
+	scopePublic   = "public"
+)
+
+// reservedNetworks are the special purpose IPv4 ranges that are neither private nor routed on the internet
+var reservedNetworks = func() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",       // this network
+		"100.64.0.0/10",   // shared address space (carrier-grade NAT)
+		"192.0.0.0/24",    // IETF protocol assignments
+		"192.0.2.0/24",    // TEST-NET-1
+		"198.18.0.0/15",   // benchmarking
+		"198.51.100.0/24", // TEST-NET-2
+		"203.0.113.0/24",  // TEST-NET-3
+		"240.0.0.0/4",     // reserved, including broadcast
+		"2001:db8::/32",   // IPv6 documentation
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// ipScope classifies a hop address as private (RFC 1918 and unique local), reserved or public.
+// It returns an empty string for hops that did not answer.
+func ipScope(address string) string {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return ""
+	}
+	if ip.IsPrivate() {
+		return scopePrivate
+	}
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return scopeReserved
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return scopeReserved
+		}
+	}
+	return scopePublic
+}
@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPScope(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{ip: "10.1.2.3", want: scopePrivate},
+		{ip: "172.16.0.1", want: scopePrivate},
+		{ip: "192.168.1.1", want: scopePrivate},
+		{ip: "fd00::1", want: scopePrivate},
+		{ip: "127.0.0.1", want: scopeReserved},
+		{ip: "169.254.1.1", want: scopeReserved},
+		{ip: "100.64.0.1", want: scopeReserved},
+		{ip: "198.51.100.7", want: scopeReserved},
+		{ip: "255.255.255.255", want: scopeReserved},
+		{ip: "fe80::1", want: scopeReserved},
+		{ip: "8.8.8.8", want: scopePublic},
+		{ip: "172.32.0.1", want: scopePublic},
+		{ip: "2606:4700::1111", want: scopePublic},
+		{ip: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			assert.Equal(t, tt.want, ipScope(tt.ip))
+		})
+	}
+}
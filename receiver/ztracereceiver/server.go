@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+// targetStatus summarizes the last trace of a target for the /targets endpoint
+type targetStatus struct {
+	Endpoint      string     `json:"endpoint"`
+	Port          int        `json:"port,omitempty"`
+	Enabled       bool       `json:"enabled"`
+	LastTrace     *time.Time `json:"last_trace,omitempty"` // unset until the target was traced
+	TargetReached bool       `json:"target_reached"`
+	TotalLatency  float64    `json:"total_latency_ms"`
+	HopCount      int        `json:"hop_count"`
+	ResolvedIP    string     `json:"resolved_ip,omitempty"`
+}
+
+// startServer serves the health and status endpoints on the configured endpoint
+func (r *ztraceReceiver) startServer(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", r.handleHealth)
+	mux.HandleFunc("/targets", r.handleTargets)
+	mux.HandleFunc("/results", r.handleResults)
+
+	var err error
+	r.server, err = r.config.ToServer(ctx, host, r.settings.TelemetrySettings, mux)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP server: %w", err)
+	}
+	r.listener, err = r.config.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", r.config.Endpoint, err)
+	}
+
+	go func(server *http.Server, listener net.Listener) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.settings.Logger.Error("HTTP server failed", zap.Error(err))
+		}
+	}(r.server, r.listener)
+	return nil
+}
+
+func (r *ztraceReceiver) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]string{"status": "ok"})
+}
+
+func (r *ztraceReceiver) handleTargets(w http.ResponseWriter, _ *http.Request) {
+	targets := append(slices.Clone(r.config.Targets), r.loadedFileTargets()...)
+	statuses := make([]targetStatus, 0, len(targets))
+	for _, target := range targets {
+		status := targetStatus{
+			Endpoint: target.Endpoint,
+			Port:     target.Port,
+			Enabled:  target.enabled(),
+		}
+		if result := r.lastResult(r.config.targetKey(target)); result != nil {
+			status.LastTrace = &result.Timestamp
+			status.TargetReached = result.TargetReached
+			status.TotalLatency = result.TotalLatency
+			status.HopCount = len(result.Hops)
+			status.ResolvedIP = result.ResolvedIP
+		}
+		statuses = append(statuses, status)
+	}
+	writeJSON(w, statuses)
+}
+
+func (r *ztraceReceiver) handleResults(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, r.lastResults())
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// storeResult keeps the result as the last one of its target.
+// Stored results are shared with the status endpoints and must not be modified afterwards.
+func (r *ztraceReceiver) storeResult(target TargetConfig, result *Result) {
+	r.resultsMu.Lock()
+	defer r.resultsMu.Unlock()
+	if r.results == nil {
+		r.results = make(map[string]*Result)
+	}
+	r.results[r.config.targetKey(target)] = result
+}
+
+// lastResult returns the last result of a target by target key, nil before its first trace
+func (r *ztraceReceiver) lastResult(key string) *Result {
+	r.resultsMu.Lock()
+	defer r.resultsMu.Unlock()
+	return r.results[key]
+}
+
+// lastResults returns the last result of every traced target by target key
+func (r *ztraceReceiver) lastResults() map[string]*Result {
+	r.resultsMu.Lock()
+	defer r.resultsMu.Unlock()
+	results := make(map[string]*Result, len(r.results))
+	for key, result := range r.results {
+		results[key] = result
+	}
+	return results
+}
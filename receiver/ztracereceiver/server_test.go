@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func getJSON(t *testing.T, url string, v any) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+}
+
+func TestStatusEndpoints(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Hour
+	cfg.Targets = []TargetConfig{
+		{Endpoint: "127.0.0.1"},
+		{Endpoint: "127.0.0.2", Port: 443, Enabled: new(bool)},
+	}
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	baseURL := "http://" + r.listener.Addr().String()
+
+	var health map[string]string
+	getJSON(t, baseURL+"/health", &health)
+	assert.Equal(t, map[string]string{"status": "ok"}, health)
+
+	// The first trace runs right after start
+	require.Eventually(t, func() bool { return r.lastResult("icmp/127.0.0.1") != nil }, 5*time.Second, 10*time.Millisecond)
+	var statuses []targetStatus
+	getJSON(t, baseURL+"/targets", &statuses)
+	require.Len(t, statuses, 2)
+	require.NotNil(t, statuses[0].LastTrace)
+	assert.Equal(t, "127.0.0.1", statuses[0].Endpoint)
+	assert.True(t, statuses[0].Enabled)
+	assert.True(t, statuses[0].TargetReached)
+	assert.Equal(t, 15, statuses[0].HopCount)
+	assert.Equal(t, "127.0.0.1", statuses[0].ResolvedIP)
+	assert.Equal(t, targetStatus{Endpoint: "127.0.0.2", Port: 443}, statuses[1])
+
+	var results map[string]*Result
+	getJSON(t, baseURL+"/results", &results)
+	require.Len(t, results, 1)
+	require.Contains(t, results, "icmp/127.0.0.1")
+	assert.Equal(t, "127.0.0.1", results["icmp/127.0.0.1"].Target)
+	assert.True(t, results["icmp/127.0.0.1"].TargetReached)
+	assert.Len(t, results["icmp/127.0.0.1"].Hops, 15)
+	assert.False(t, results["icmp/127.0.0.1"].Timestamp.IsZero())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	_, err := http.Get(baseURL + "/health")
+	assert.Error(t, err)
+}
+
+func TestResultsConcurrentAccess(t *testing.T) {
+	r := &ztraceReceiver{
+		config:   createDefaultConfig().(*Config),
+		settings: receivertest.NewNopSettings(),
+	}
+	targets := []TargetConfig{{Endpoint: "192.0.2.1"}, {Endpoint: "192.0.2.2"}}
+
+	// Traces store results while the endpoint is read, run with -race to detect unsynchronized access
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				r.storeResult(target, &Result{
+					Target:    target.Endpoint,
+					Timestamp: time.Now(),
+					Hops:      []Hop{{TTL: 1, IP: target.Endpoint, Latency: float64(i)}},
+				})
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rec := httptest.NewRecorder()
+				r.handleResults(rec, httptest.NewRequest(http.MethodGet, "/results", nil))
+				var results map[string]*Result
+				assert.NoError(t, json.NewDecoder(rec.Body).Decode(&results))
+			}
+		}()
+	}
+	wg.Wait()
+
+	results := r.lastResults()
+	require.Len(t, results, 2)
+	for _, target := range targets {
+		assert.Equal(t, 99.0, results[r.config.targetKey(target)].Hops[0].Latency)
+	}
+}
+
+func TestStatusServerDisabled(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ""
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Nil(t, r.server)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
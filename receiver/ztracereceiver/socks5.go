@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// parseProxyURL checks the proxy is a socks5://[user:password@]host:port URL
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if u.Scheme != "socks5" {
+		return nil, fmt.Errorf("unsupported proxy scheme %q, must be socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("proxy URL must include host and port")
+	}
+	return u, nil
+}
+
+// socks5Dialer opens TCP connections through a SOCKS5 proxy (RFC 1928),
+// with optional username/password authentication (RFC 1929)
+type socks5Dialer struct {
+	address  string
+	username string
+	password string
+}
+
+func newSOCKS5Dialer(proxyURL string) (*socks5Dialer, error) {
+	u, err := parseProxyURL(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	d := &socks5Dialer{address: u.Host}
+	if u.User != nil {
+		d.username = u.User.Username()
+		d.password, _ = u.User.Password()
+	}
+	return d, nil
+}
+
+// socks5ReplyError is returned when the proxy could not connect to the destination
+type socks5ReplyError struct {
+	code byte
+}
+
+func (e *socks5ReplyError) Error() string {
+	return fmt.Sprintf("proxy failed to connect to the destination, reply code %d", e.code)
+}
+
+// DialContext connects to address, a host:port pair, through the proxy
+func (d *socks5Dialer) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	return d.dialFrom(ctx, nil, address)
+}
+
+// dialFrom connects to address through the proxy, connecting to the proxy from the local source address
+func (d *socks5Dialer) dialFrom(ctx context.Context, source net.IP, address string) (net.Conn, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q: %w", portStr, err)
+	}
+
+	var dialer net.Dialer
+	if source != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: source}
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", d.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if err := d.handshake(conn, host, port); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (d *socks5Dialer) handshake(conn net.Conn, host string, port int) error {
+	method := byte(0x00) // no authentication
+	if d.username != "" {
+		method = 0x02 // username/password
+	}
+	if _, err := conn.Write([]byte{0x05, 0x01, method}); err != nil {
+		return fmt.Errorf("failed to write to proxy: %w", err)
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("failed to read from proxy: %w", err)
+	}
+	if reply[0] != 0x05 || reply[1] != method {
+		return errors.New("proxy does not support the authentication method")
+	}
+
+	if method == 0x02 {
+		auth := []byte{0x01, byte(len(d.username))}
+		auth = append(auth, d.username...)
+		auth = append(auth, byte(len(d.password)))
+		auth = append(auth, d.password...)
+		if _, err := conn.Write(auth); err != nil {
+			return fmt.Errorf("failed to write to proxy: %w", err)
+		}
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return fmt.Errorf("failed to read from proxy: %w", err)
+		}
+		if reply[1] != 0x00 {
+			return errors.New("proxy authentication failed")
+		}
+	}
+
+	request := []byte{0x05, 0x01, 0x00} // CONNECT
+	if ip := net.ParseIP(host); ip == nil {
+		if len(host) > 255 {
+			return fmt.Errorf("host name %q too long for proxy", host)
+		}
+		request = append(request, 0x03, byte(len(host)))
+		request = append(request, host...)
+	} else if ip4 := ip.To4(); ip4 != nil {
+		request = append(request, 0x01)
+		request = append(request, ip4...)
+	} else {
+		request = append(request, 0x04)
+		request = append(request, ip.To16()...)
+	}
+	request = binary.BigEndian.AppendUint16(request, uint16(port))
+	if _, err := conn.Write(request); err != nil {
+		return fmt.Errorf("failed to write to proxy: %w", err)
+	}
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("failed to read from proxy: %w", err)
+	}
+	if header[1] != 0x00 {
+		return &socks5ReplyError{code: header[1]}
+	}
+
+	// Skip the address the proxy bound for the connection
+	var boundLen int
+	switch header[3] {
+	case 0x01:
+		boundLen = net.IPv4len
+	case 0x04:
+		boundLen = net.IPv6len
+	case 0x03:
+		if _, err := io.ReadFull(conn, reply[:1]); err != nil {
+			return fmt.Errorf("failed to read from proxy: %w", err)
+		}
+		boundLen = int(reply[0])
+	default:
+		return fmt.Errorf("invalid address type %d in proxy reply", header[3])
+	}
+	if _, err := io.ReadFull(conn, make([]byte, boundLen+2)); err != nil {
+		return fmt.Errorf("failed to read from proxy: %w", err)
+	}
+	return nil
+}
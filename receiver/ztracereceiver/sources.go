@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"net"
+	"sync"
+)
+
+// sourceRotation picks the local address every trace of a target is sent from by smooth weighted
+// round-robin, which interleaves the addresses instead of sending runs of traces from the heaviest.
+// Every target rotates on its own, so each target is traced over every egress.
+type sourceRotation struct {
+	addresses []net.IP
+	weights   []int
+	total     int
+
+	mu      sync.Mutex
+	current map[string][]int // by target key
+}
+
+func newSourceRotation(sources []SourceAddressConfig) *sourceRotation {
+	r := &sourceRotation{current: make(map[string][]int)}
+	for _, source := range sources {
+		r.addresses = append(r.addresses, net.ParseIP(source.Address))
+		r.weights = append(r.weights, source.weight())
+		r.total += source.weight()
+	}
+	return r
+}
+
+// next returns the address the next trace of the target is sent from
+func (r *sourceRotation) next(key string) net.IP {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.current[key]
+	if !ok {
+		current = make([]int, len(r.addresses))
+		r.current[key] = current
+	}
+	best := 0
+	for i, weight := range r.weights {
+		current[i] += weight
+		if current[i] > current[best] {
+			best = i
+		}
+	}
+	current[best] -= r.total
+	return r.addresses[best]
+}
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+)
+
+func TestSourceRotation(t *testing.T) {
+	r := newSourceRotation([]SourceAddressConfig{
+		{Address: "192.0.2.10", Weight: 3},
+		{Address: "198.51.100.10"},
+	})
+
+	// The heavier address gets three of every four traces, interleaved with the other
+	var picked []string
+	for i := 0; i < 8; i++ {
+		picked = append(picked, r.next("example.com").String())
+	}
+	assert.Equal(t, []string{
+		"192.0.2.10", "192.0.2.10", "198.51.100.10", "192.0.2.10",
+		"192.0.2.10", "192.0.2.10", "198.51.100.10", "192.0.2.10",
+	}, picked)
+
+	// Every target rotates on its own
+	assert.Equal(t, "192.0.2.10", r.next("example.org").String())
+}
+
+func TestSourceRotationEqualWeights(t *testing.T) {
+	r := newSourceRotation([]SourceAddressConfig{
+		{Address: "192.0.2.10"},
+		{Address: "192.0.2.11"},
+		{Address: "2001:db8::10"},
+	})
+	counts := map[string]int{}
+	for i := 0; i < 30; i++ {
+		counts[r.next("example.com").String()]++
+	}
+	assert.Equal(t, map[string]int{"192.0.2.10": 10, "192.0.2.11": 10, "2001:db8::10": 10}, counts)
+}
+
+func TestTraceSourceAddress(t *testing.T) {
+	p := &scriptedProber{
+		answers: map[int][]Hop{
+			1: {{IP: "127.0.0.1", RTTs: []float64{1}}},
+		},
+	}
+	tr := newScriptedTracer(p)
+	tr.sources = newSourceRotation([]SourceAddressConfig{{Address: "192.0.2.10"}, {Address: "192.0.2.11"}})
+	cfg := &Config{Protocol: "icmp", MaxHops: 30}
+
+	var used []string
+	for i := 0; i < 2; i++ {
+		p.calls = nil
+		result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+		require.NoError(t, err)
+		used = append(used, result.SourceAddress)
+	}
+	assert.Equal(t, []string{"192.0.2.10", "192.0.2.11"}, used)
+
+	// Without source addresses the OS chooses and nothing is reported
+	tr.sources = nil
+	p.calls = nil
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, result.SourceAddress)
+}
+
+func TestSourceAddressAttribute(t *testing.T) {
+	result := &Result{
+		Target:        "example.com",
+		Timestamp:     time.Now(),
+		Hops:          []Hop{{TTL: 1, IP: "192.168.1.1", Latency: 2.5}},
+		SourceAddress: "192.0.2.10",
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	for convention, name := range map[string]string{"ztrace": "ztrace.source.address", "semconv": "network.local.address"} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.AttributeConvention = convention
+		r := &ztraceReceiver{config: cfg, settings: receivertest.NewNopSettings()}
+
+		value, ok := r.convertToMetrics(result, target).ResourceMetrics().At(0).Resource().Attributes().Get(name)
+		require.True(t, ok, convention)
+		assert.Equal(t, "192.0.2.10", value.Str())
+		value, ok = r.convertToTraces(result, target).ResourceSpans().At(0).Resource().Attributes().Get(name)
+		require.True(t, ok, convention)
+		assert.Equal(t, "192.0.2.10", value.Str())
+		value, ok = r.convertToLogs(result, target, "").ResourceLogs().At(0).Resource().Attributes().Get(name)
+		require.True(t, ok, convention)
+		assert.Equal(t, "192.0.2.10", value.Str())
+	}
+}
+
+func TestTraceThroughProxyFromSourceAddress(t *testing.T) {
+	address, requested := fakeSOCKS5Proxy(t, 0x00)
+	config := &Config{
+		Protocol:        "tcp",
+		MaxHops:         30,
+		Proxy:           "socks5://" + address,
+		SourceAddresses: []SourceAddressConfig{{Address: "127.0.0.1"}},
+	}
+	tr, err := newTracer(config, zap.NewNop())
+	require.NoError(t, err)
+
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Port: 443}, config)
+	require.NoError(t, err)
+	assert.True(t, result.TargetReached)
+	assert.Equal(t, "127.0.0.1", result.SourceAddress)
+	assert.Equal(t, "127.0.0.1:443", <-requested)
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"crypto/rand"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// spanIDs identify the spans of one trace result. The traces and the exemplars of the
+// metrics converted from the same result share them.
+type spanIDs struct {
+	traceID pcommon.TraceID
+	root    pcommon.SpanID
+	hops    []pcommon.SpanID // by index in Result.Hops
+}
+
+func newSpanIDs(hops int) *spanIDs {
+	ids := &spanIDs{hops: make([]pcommon.SpanID, hops)}
+	_, _ = rand.Read(ids.traceID[:])
+	_, _ = rand.Read(ids.root[:])
+	for i := range ids.hops {
+		_, _ = rand.Read(ids.hops[i][:])
+	}
+	return ids
+}
+
+// ids returns the span IDs of the result, generated on first use
+func (r *Result) ids() *spanIDs {
+	if r.spans == nil {
+		r.spans = newSpanIDs(len(r.Hops))
+	}
+	return r.spans
+}
@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fileTarget is a target as listed in targets_file, with the keys of the targets setting
+type fileTarget struct {
+	Endpoint           string            `json:"endpoint"`
+	Port               int               `json:"port"`
+	Ports              []int             `json:"ports"`
+	Tags               map[string]string `json:"tags"`
+	Enabled            *bool             `json:"enabled"`
+	Group              string            `json:"group"`
+	Protocol           string            `json:"protocol"`
+	CollectionInterval string            `json:"collection_interval"`
+	EnableGeolocation  *bool             `json:"enable_geolocation"`
+	EnableASNLookup    *bool             `json:"enable_asn_lookup"`
+}
+
+// parseTargetsFile parses a JSON array of targets
+func parseTargetsFile(data []byte) ([]fileTarget, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var targets []fileTarget
+	if err := decoder.Decode(&targets); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
+
+// config converts the target to the targets setting
+func (target fileTarget) config() (TargetConfig, error) {
+	config := TargetConfig{
+		Endpoint:          target.Endpoint,
+		Port:              target.Port,
+		Ports:             target.Ports,
+		Tags:              target.Tags,
+		Enabled:           target.Enabled,
+		Group:             target.Group,
+		Protocol:          target.Protocol,
+		EnableGeolocation: target.EnableGeolocation,
+		EnableASNLookup:   target.EnableASNLookup,
+	}
+	if target.CollectionInterval != "" {
+		interval, err := time.ParseDuration(target.CollectionInterval)
+		if err != nil {
+			return config, fmt.Errorf("invalid collection_interval: %w", err)
+		}
+		config.CollectionInterval = interval
+	}
+	return config, nil
+}
+
+// targetsFile loads the targets of the files matching targets_file. A file that cannot be read or
+// parsed keeps the targets last loaded from it, so a partially written file does not drop targets.
+type targetsFile struct {
+	config *Config
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	byFile map[string][]TargetConfig
+}
+
+func newTargetsFile(config *Config, logger *zap.Logger) *targetsFile {
+	return &targetsFile{
+		config: config,
+		logger: logger,
+		byFile: make(map[string][]TargetConfig),
+	}
+}
+
+// load re-reads the matching files and returns their valid targets in file order. Invalid targets
+// and duplicates of an earlier target are skipped with a warning.
+func (f *targetsFile) load() []TargetConfig {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	paths, err := filepath.Glob(f.config.TargetsFile)
+	if err != nil {
+		// The pattern is validated with the configuration
+		f.logger.Error("Failed to match targets_file", zap.Error(err))
+		return nil
+	}
+	if len(paths) == 0 {
+		f.logger.Warn("No file matches targets_file", zap.String("targets_file", f.config.TargetsFile))
+	}
+	sort.Strings(paths)
+
+	byFile := make(map[string][]TargetConfig, len(paths))
+	for _, path := range paths {
+		targets, err := f.loadFile(path)
+		if err != nil {
+			previous, ok := f.byFile[path]
+			f.logger.Error("Failed to load targets file, keeping its previous targets",
+				zap.String("path", path),
+				zap.Int("targets", len(previous)),
+				zap.Error(err))
+			if ok {
+				byFile[path] = previous
+			}
+			continue
+		}
+		byFile[path] = targets
+	}
+	f.byFile = byFile
+
+	// Inline targets take precedence over the files
+	seen := make(map[string]bool)
+	for _, target := range f.config.Targets {
+		seen[f.config.targetKey(target)] = true
+	}
+	var targets []TargetConfig
+	for _, path := range paths {
+		for _, target := range byFile[path] {
+			key := f.config.targetKey(target)
+			if seen[key] {
+				f.logger.Warn("Skipping duplicate target",
+					zap.String("path", path),
+					zap.String("target", target.Endpoint))
+				continue
+			}
+			seen[key] = true
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// loadFile parses the targets of one file, skipping the invalid ones
+func (f *targetsFile) loadFile(path string) ([]TargetConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := parseTargetsFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse targets: %w", err)
+	}
+	targets := make([]TargetConfig, 0, len(entries))
+	for i, entry := range entries {
+		name := fmt.Sprintf("%s[%d]", filepath.Base(path), i)
+		target, err := entry.config()
+		if err == nil {
+			err = f.config.validateTarget(name, target)
+		} else {
+			err = fmt.Errorf("%s: %w", name, err)
+		}
+		if err != nil {
+			f.logger.Warn("Skipping invalid target", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// watchTargetsFile re-reads targets_file on SIGHUP and every targets_reload_interval until stop is closed
+func (r *ztraceReceiver) watchTargetsFile(ctx context.Context, stop <-chan struct{}) {
+	defer r.wg.Done()
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var reload <-chan time.Time
+	if r.config.TargetsReloadInterval > 0 {
+		ticker := r.clock.NewTicker(r.config.TargetsReloadInterval)
+		defer ticker.Stop()
+		reload = ticker.C()
+	}
+
+	for {
+		select {
+		case <-reload:
+		case <-hangup:
+			r.settings.Logger.Info("Reloading targets_file on SIGHUP")
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+		r.reloadTargets(ctx)
+	}
+}
+
+// reloadTargets re-reads targets_file and reconciles the traced targets with the inline and file targets
+func (r *ztraceReceiver) reloadTargets(ctx context.Context) {
+	targets := r.targetsFile.load()
+	r.setFileTargets(targets)
+	r.addFileTargetProbers(targets)
+	started, stopped := r.reconcileTargets(ctx, append(slices.Clone(r.config.Targets), targets...))
+	r.settings.Logger.Info("Reloaded targets_file",
+		zap.Int("targets", len(targets)),
+		zap.Int("started", started),
+		zap.Int("stopped", stopped))
+}
+
+// addFileTargetProbers creates the probers of the protocols only file targets, or their groups, use
+func (r *ztraceReceiver) addFileTargetProbers(targets []TargetConfig) {
+	if err := r.tracer.addTargetProbers(r.config, targets); err != nil {
+		r.settings.Logger.Error("Failed to create the probers of targets_file", zap.Error(err))
+	}
+}
+
+// setFileTargets stores the targets last loaded from targets_file for the status endpoints
+func (r *ztraceReceiver) setFileTargets(targets []TargetConfig) {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	r.fileTargets = targets
+}
+
+// loadedFileTargets returns the targets last loaded from targets_file
+func (r *ztraceReceiver) loadedFileTargets() []TargetConfig {
+	r.runningMu.Lock()
+	defer r.runningMu.Unlock()
+	return r.fileTargets
+}
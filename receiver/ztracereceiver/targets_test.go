@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+)
+
+func writeTargetsFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func endpoints(targets []TargetConfig) []string {
+	var endpoints []string
+	for _, target := range targets {
+		endpoints = append(endpoints, target.Endpoint)
+	}
+	return endpoints
+}
+
+func TestTargetsFileLoad(t *testing.T) {
+	dir := t.TempDir()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "tcp"
+	cfg.Targets = []TargetConfig{{Endpoint: "example.com", Port: 443}}
+	cfg.TargetsFile = filepath.Join(dir, "*.json")
+
+	writeTargetsFile(t, filepath.Join(dir, "a.json"), `[
+		{"endpoint": "example.org", "port": 443, "collection_interval": "30s", "tags": {"team": "edge"}},
+		{"endpoint": "example.com", "port": 443},
+		{"endpoint": "", "port": 443},
+		{"endpoint": "example.net", "port": 443, "collection_interval": "soon"}
+	]`)
+	writeTargetsFile(t, filepath.Join(dir, "b.json"), `[{"endpoint": "192.0.2.1", "protocol": "icmp"}]`)
+	writeTargetsFile(t, filepath.Join(dir, "notes.txt"), `not targets`)
+
+	// Invalid targets and duplicates of the inline targets are skipped
+	f := newTargetsFile(cfg, zap.NewNop())
+	targets := f.load()
+	assert.Equal(t, []string{"example.org", "192.0.2.1"}, endpoints(targets))
+	assert.Equal(t, 30*time.Second, targets[0].CollectionInterval)
+	assert.Equal(t, map[string]string{"team": "edge"}, targets[0].Tags)
+
+	// A file that fails to parse, e.g. while it is rewritten, keeps its previous targets
+	writeTargetsFile(t, filepath.Join(dir, "a.json"), `[{"endpoint": "example.org", "po`)
+	assert.Equal(t, []string{"example.org", "192.0.2.1"}, endpoints(f.load()))
+	writeTargetsFile(t, filepath.Join(dir, "a.json"), `[{"endpoint": "example.org", "prot": "tcp"}]`)
+	assert.Equal(t, []string{"example.org", "192.0.2.1"}, endpoints(f.load()))
+
+	// A file that is gone no longer contributes targets
+	require.NoError(t, os.Remove(filepath.Join(dir, "b.json")))
+	assert.Equal(t, []string{"example.org"}, endpoints(f.load()))
+}
+
+func TestReloadTargetsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	writeTargetsFile(t, path, `[{"endpoint": "127.0.0.2"}]`)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Hour
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}}
+	cfg.TargetsFile = path
+	cfg.TargetsReloadInterval = time.Minute
+	sink := new(consumertest.MetricsSink)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		clock:    clk,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	traced := func() map[string]int {
+		counts := make(map[string]int)
+		for _, md := range sink.AllMetrics() {
+			endpoint, _ := md.ResourceMetrics().At(0).Resource().Attributes().Get("ztrace.target")
+			counts[endpoint.Str()]++
+		}
+		return counts
+	}
+	// Two collect loops and the reload ticker
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 && clk.tickerCount() == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]int{"127.0.0.1": 1, "127.0.0.2": 1}, traced())
+
+	// The added target is traced on the next reload, the running ones are not restarted
+	writeTargetsFile(t, path, `[{"endpoint": "127.0.0.2"}, {"endpoint": "127.0.0.3"}]`)
+	clk.Advance(cfg.TargetsReloadInterval)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]int{"127.0.0.1": 1, "127.0.0.2": 1, "127.0.0.3": 1}, traced())
+	assert.Equal(t, []string{"127.0.0.2", "127.0.0.3"}, endpoints(r.loadedFileTargets()))
+
+	// The removed target's collect loop stops on the next reload, the others keep running
+	writeTargetsFile(t, path, `[{"endpoint": "127.0.0.3"}]`)
+	clk.Advance(cfg.TargetsReloadInterval)
+	require.Eventually(t, func() bool { return clk.stoppedTickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	clk.Advance(cfg.CollectionInterval)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 5 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]int{"127.0.0.1": 2, "127.0.0.2": 1, "127.0.0.3": 2}, traced())
+}
+
+func TestReloadTargetsFileProtocol(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	writeTargetsFile(t, path, `[]`)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Hour
+	cfg.TargetsFile = path
+	cfg.TargetsReloadInterval = time.Minute
+	sink := new(consumertest.MetricsSink)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		clock:    clk,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+	require.Eventually(t, func() bool { return clk.tickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// A protocol no inline target uses gets its own prober on reload
+	writeTargetsFile(t, path, `[{"endpoint": "127.0.0.2", "protocol": "tcp"}]`)
+	clk.Advance(cfg.TargetsReloadInterval)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	latency, ok := firstMetric(sink.AllMetrics()[0], "ztrace.hop.latency")
+	require.True(t, ok)
+	dps := latency.Gauge().DataPoints()
+	reply, ok := dps.At(dps.Len() - 1).Attributes().Get("ztrace.hop.reply_protocol")
+	require.True(t, ok)
+	assert.Equal(t, replyTCPSynAck, reply.Str())
+}
+
+func TestReconcileTargets(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Minute
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}}
+	sink := new(consumertest.MetricsSink)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		clock:    clk,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 1 && clk.tickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Running targets are kept, new ones started and disabled ones skipped
+	disabled := false
+	started, stopped := r.reconcileTargets(context.Background(), []TargetConfig{
+		{Endpoint: "127.0.0.1"},
+		{Endpoint: "127.0.0.2"},
+		{Endpoint: "127.0.0.3", Enabled: &disabled},
+	})
+	assert.Equal(t, 1, started)
+	assert.Zero(t, stopped)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 2 && clk.tickerCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	started, stopped = r.reconcileTargets(context.Background(), []TargetConfig{{Endpoint: "127.0.0.2"}})
+	assert.Zero(t, started)
+	assert.Equal(t, 1, stopped)
+	require.Eventually(t, func() bool { return clk.stoppedTickerCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Only the remaining target is traced on the next tick
+	clk.Advance(cfg.CollectionInterval)
+	require.Eventually(t, func() bool { return len(sink.AllMetrics()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	endpoint, _ := sink.AllMetrics()[2].ResourceMetrics().At(0).Resource().Attributes().Get("ztrace.target")
+	assert.Equal(t, "127.0.0.2", endpoint.Str())
+}
+
+func TestReconcileTargetsCancelsTrace(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.CollectionInterval = time.Minute
+	cfg.Retries = 1
+	cfg.InterProbeDelay = time.Second
+	cfg.Targets = []TargetConfig{{Endpoint: "127.0.0.1"}}
+	sink := new(consumertest.MetricsSink)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	r := &ztraceReceiver{
+		config:   cfg,
+		settings: receivertest.NewNopSettings(),
+		consumer: sink,
+		clock:    clk,
+	}
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, r.Shutdown(context.Background())) }()
+
+	// The trace waits for the inter probe delay of its first hop, the clock never advances
+	require.Eventually(t, func() bool { return clk.tickerCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	// Removing the target cancels the trace in progress
+	_, stopped := r.reconcileTargets(context.Background(), nil)
+	assert.Equal(t, 1, stopped)
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the trace of the removed target was not cancelled")
+	}
+	assert.Empty(t, sink.AllMetrics())
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// defaultTotalLatencyBuckets are the bucket boundaries of ztrace.total_latency.histogram without total_latency_buckets
+var defaultTotalLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// latencyCounts is the distribution of the total latency of the traces of a target since the first one
+type latencyCounts struct {
+	start   time.Time
+	count   uint64
+	sum     float64 // in milliseconds
+	min     float64
+	max     float64
+	buckets []uint64
+}
+
+// totalLatencies accumulates the total latency of every trace per target, so that percentiles over
+// any window can be computed from the cumulative histogram instead of sampling the gauge
+type totalLatencies struct {
+	bounds []float64 // in milliseconds
+
+	mu      sync.Mutex
+	targets map[string]*latencyCounts
+}
+
+func newTotalLatencies(buckets []time.Duration) *totalLatencies {
+	bounds := make([]float64, len(buckets))
+	for i, bucket := range buckets {
+		bounds[i] = float64(bucket) / float64(time.Millisecond)
+	}
+	return &totalLatencies{
+		bounds:  bounds,
+		targets: make(map[string]*latencyCounts),
+	}
+}
+
+// record adds the total latency of a trace, traces without one, like ztrace.total_latency, are skipped
+func (l *totalLatencies) record(target string, result *Result) {
+	if result.TotalLatency <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counts, ok := l.targets[target]
+	if !ok {
+		counts = &latencyCounts{start: result.Timestamp, buckets: make([]uint64, len(l.bounds)+1)}
+		l.targets[target] = counts
+	}
+
+	ms := result.TotalLatency
+	counts.buckets[sort.SearchFloat64s(l.bounds, ms)]++
+	if counts.count == 0 || ms < counts.min {
+		counts.min = ms
+	}
+	if counts.count == 0 || ms > counts.max {
+		counts.max = ms
+	}
+	counts.count++
+	counts.sum += ms
+}
+
+// appendTotalLatencyHistogram adds the total latencies of the target's traces so far, reported in
+// milliseconds times scale
+func (r *ztraceReceiver) appendTotalLatencyHistogram(sm pmetric.ScopeMetrics, target TargetConfig, timestamp pcommon.Timestamp, unit string, scale float64) {
+	r.latencies.mu.Lock()
+	defer r.latencies.mu.Unlock()
+	counts, ok := r.latencies.targets[r.config.targetKey(target)]
+	if !ok {
+		return
+	}
+
+	histogramMetric := sm.Metrics().AppendEmpty()
+	histogramMetric.SetName("ztrace.total_latency.histogram")
+	histogramMetric.SetDescription("Distribution of the total latency to reach the target over the traces since the first one")
+	histogramMetric.SetUnit(unit)
+	histogram := histogramMetric.SetEmptyHistogram()
+	histogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	bounds := make([]float64, len(r.latencies.bounds))
+	for i, bound := range r.latencies.bounds {
+		bounds[i] = bound * scale
+	}
+	dp := histogram.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(counts.start))
+	dp.SetTimestamp(timestamp)
+	dp.SetCount(counts.count)
+	dp.SetSum(counts.sum * scale)
+	dp.SetMin(counts.min * scale)
+	dp.SetMax(counts.max * scale)
+	dp.ExplicitBounds().FromRaw(bounds)
+	dp.BucketCounts().FromRaw(slices.Clone(counts.buckets))
+}
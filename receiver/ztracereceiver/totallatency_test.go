@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestTotalLatencyHistogram(t *testing.T) {
+	cfg := &Config{
+		Protocol:              "icmp",
+		TotalLatencyHistogram: true,
+		TotalLatencyBuckets:   []time.Duration{10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond},
+	}
+	r := &ztraceReceiver{
+		config:    cfg,
+		settings:  receivertest.NewNopSettings(),
+		latencies: newTotalLatencies(cfg.TotalLatencyBuckets),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// The traces accumulate across cycles, those without a total latency are skipped
+	var last *Result
+	for i, latency := range []float64{8, 50, 0, 72.5, 300} {
+		last = &Result{Timestamp: start.Add(time.Duration(i) * time.Minute), TotalLatency: latency, TargetReached: latency > 0}
+		r.latencies.record(r.config.targetKey(target), last)
+	}
+
+	histogramMetric, ok := firstMetric(r.convertToMetrics(last, target), "ztrace.total_latency.histogram")
+	require.True(t, ok)
+	assert.Equal(t, "ms", histogramMetric.Unit())
+	histogram := histogramMetric.Histogram()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, histogram.AggregationTemporality())
+	dp := histogram.DataPoints().At(0)
+	assert.Equal(t, start, dp.StartTimestamp().AsTime())
+	assert.Equal(t, uint64(4), dp.Count())
+	assert.InDelta(t, 430.5, dp.Sum(), 1e-9)
+	assert.InDelta(t, 8.0, dp.Min(), 1e-9)
+	assert.InDelta(t, 300.0, dp.Max(), 1e-9)
+	assert.Equal(t, []float64{10, 50, 100}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 1, 1, 1}, dp.BucketCounts().AsRaw())
+
+	// The gauge is kept
+	_, ok = firstMetric(r.convertToMetrics(last, target), "ztrace.total_latency")
+	assert.True(t, ok)
+
+	// A target without a recorded trace has no histogram yet
+	_, ok = firstMetric(r.convertToMetrics(&Result{}, TargetConfig{Endpoint: "example.org"}), "ztrace.total_latency.histogram")
+	assert.False(t, ok)
+}
+
+func TestTotalLatencyHistogramScale(t *testing.T) {
+	cfg := &Config{Protocol: "icmp", LatencyUnit: "s", TotalLatencyHistogram: true, TotalLatencyBuckets: []time.Duration{100 * time.Millisecond}}
+	r := &ztraceReceiver{
+		config:    cfg,
+		settings:  receivertest.NewNopSettings(),
+		latencies: newTotalLatencies(cfg.TotalLatencyBuckets),
+	}
+	target := TargetConfig{Endpoint: "example.com"}
+	result := &Result{TotalLatency: 250, TargetReached: true}
+	r.latencies.record(r.config.targetKey(target), result)
+
+	histogramMetric, ok := firstMetric(r.convertToMetrics(result, target), "ztrace.total_latency.histogram")
+	require.True(t, ok)
+	assert.Equal(t, "s", histogramMetric.Unit())
+	dp := histogramMetric.Histogram().DataPoints().At(0)
+	assert.InDelta(t, 0.25, dp.Sum(), 1e-9)
+	assert.InDeltaSlice(t, []float64{0.1}, dp.ExplicitBounds().AsRaw(), 1e-9)
+	assert.Equal(t, []uint64{0, 1}, dp.BucketCounts().AsRaw())
+}
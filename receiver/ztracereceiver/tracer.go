@@ -6,162 +6,533 @@ package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"io"
+	"math"
 	"net"
+	"strconv"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 )
 
-// hopInfo contains information about a single hop in the traceroute
-type hopInfo struct {
-	ttl        int
-	ip         string
-	hostname   string
-	latency    float64 // in milliseconds
-	packetLoss float64 // percentage
-	jitter     float64 // in milliseconds
-	city       string
-	country    string
-	asn        string
-	provider   string
+// probeCounts holds the cumulative number of probes sent to and answered for a target
+type probeCounts struct {
+	sent          int64
+	received      int64
+	resolveErrors int64
+	panics        int64
+	enrichment    enrichmentCounts
 }
 
-// traceResult contains the complete traceroute result
-type traceResult struct {
-	hops         []hopInfo
-	totalLatency float64
-	targetReached bool
+// resolveError is returned by trace when the target endpoint cannot be resolved
+type resolveError struct {
+	endpoint string
+	err      error
+}
+
+func (e *resolveError) Error() string {
+	return fmt.Sprintf("failed to resolve target %s: %v", e.endpoint, e.err)
+}
+
+func (e *resolveError) Unwrap() error {
+	return e.err
+}
+
+// protocolProbers are the probers of a protocol targets override the receiver protocol with
+type protocolProbers struct {
+	prober prober
+	echo   prober
 }
 
 // tracer handles the actual traceroute operations
 type tracer struct {
-	protocol string
 	logger   *zap.Logger
+	prober   prober
+	echo     prober       // confirms the target with an ICMP echo in udp-icmp mode, nil otherwise
+	geo      geoProvider  // looks up hop locations, nil keeps the locations the prober answered with
+	asn      asnProvider  // looks up hop autonomous systems, nil keeps the prober answers
+	cache    *lookupCache // answers of geo and asn shared by all targets, nil when disabled
+	resolver *resolver
+	replay   *replaySource
+	proxy    *socks5Dialer
+	sources  *sourceRotation // picks the local address of the proxy connection of each trace, nil lets the OS choose
+	clock    clock           // spaces the probes to a hop
+	limiter  *probeLimiter   // spaces the probes of all targets, nil without probe_rate_limit
+
+	// Probers of the other protocols of the targets by protocol, added to when targets_file is reloaded
+	othersMu sync.RWMutex
+	others   map[string]protocolProbers
+
+	// Probe counters per target key, traces of different targets run concurrently
+	mu        sync.Mutex
+	probes    map[string]*probeCounts
+	startTime time.Time
+}
+
+// setClock replaces the clock of the tracer and of its resolver
+func (t *tracer) setClock(clk clock) {
+	t.clock = clk
+	if t.resolver != nil {
+		t.resolver.clock = clk
+	}
+}
+
+// newTracer creates a tracer probing the network, or replaying recorded results with source file
+func newTracer(config *Config, logger *zap.Logger) (*tracer, error) {
+	t := &tracer{
+		logger:    logger,
+		probes:    make(map[string]*probeCounts),
+		startTime: time.Now(),
+		clock:     realClock{},
+	}
+
+	var err error
+	if config.Source == "file" {
+		t.replay, err = newReplaySource(config.SourcePath)
+	} else {
+		t.prober, t.echo, err = newProtocolProbers(config.Protocol, config.TCPFlags)
+		if err == nil {
+			err = t.addTargetProbers(config, config.Targets)
+		}
+		t.resolver = newResolver(config.DNSCacheTTL)
+		if config.LookupCacheSize > 0 {
+			t.cache = newLookupCache(config.LookupCacheSize, config.LookupCacheTTL)
+		}
+		if len(config.SourceAddresses) > 0 && config.Proxy != "" {
+			t.sources = newSourceRotation(config.SourceAddresses)
+		}
+		if config.ProbeRateLimit > 0 {
+			t.limiter = newProbeLimiter(config.ProbeRateLimit)
+		}
+	}
+	if err == nil && config.Proxy != "" {
+		t.proxy, err = newSOCKS5Dialer(config.Proxy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// newProtocolProbers returns the prober of a protocol and the prober confirming the target for udp-icmp
+func newProtocolProbers(protocol, tcpFlags string) (prober, prober, error) {
+	p, err := newProber(protocol, tcpFlags)
+	if err != nil || protocol != "udp-icmp" {
+		return p, nil, err
+	}
+	echo, err := newProber("icmp", "")
+	return p, echo, err
+}
+
+// addTargetProbers creates the probers of the protocols targets override the receiver protocol with
+func (t *tracer) addTargetProbers(config *Config, targets []TargetConfig) error {
+	if t.replay != nil {
+		return nil
+	}
+	t.othersMu.Lock()
+	defer t.othersMu.Unlock()
+	for _, target := range targets {
+		protocol := config.resolveTarget(target).Protocol
+		if _, ok := t.others[protocol]; ok || protocol == "" || protocol == config.Protocol {
+			continue
+		}
+		p, echo, err := newProtocolProbers(protocol, config.TCPFlags)
+		if err != nil {
+			return err
+		}
+		if t.others == nil {
+			t.others = make(map[string]protocolProbers)
+		}
+		t.others[protocol] = protocolProbers{prober: p, echo: echo}
+	}
+	return nil
 }
 
-func newTracer(protocol string, logger *zap.Logger) (*tracer, error) {
-	return &tracer{
-		protocol: protocol,
-		logger:   logger,
-	}, nil
+// probers returns the probers of the protocol the target is traced with
+func (t *tracer) probers(protocol string) (prober, prober) {
+	t.othersMu.RLock()
+	defer t.othersMu.RUnlock()
+	if other, ok := t.others[protocol]; ok {
+		return other.prober, other.echo
+	}
+	return t.prober, t.echo
+}
+
+// countProbes adds the probes of one trace to the target's counters and returns the new totals
+func (t *tracer) countProbes(key string, sent, received int64) (int64, int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts, ok := t.probes[key]
+	if !ok {
+		counts = &probeCounts{}
+		t.probes[key] = counts
+	}
+	counts.sent += sent
+	counts.received += received
+	return counts.sent, counts.received
 }
 
-func (t *tracer) trace(ctx context.Context, target TargetConfig, config *Config) (*traceResult, error) {
-	// Resolve target address
-	addr, err := net.ResolveIPAddr("ip4", target.Endpoint)
+// countResolveError records a failed resolution of the target and returns the new total
+func (t *tracer) countResolveError(key string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts, ok := t.probes[key]
+	if !ok {
+		counts = &probeCounts{}
+		t.probes[key] = counts
+	}
+	counts.resolveErrors++
+	return counts.resolveErrors
+}
+
+// countPanic records a trace of the target that panicked and returns the new total
+func (t *tracer) countPanic(key string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts, ok := t.probes[key]
+	if !ok {
+		counts = &probeCounts{}
+		t.probes[key] = counts
+	}
+	counts.panics++
+	return counts.panics
+}
+
+func (t *tracer) trace(ctx context.Context, target TargetConfig, config *Config) (*Result, error) {
+	key := config.targetKey(target)
+	if t.replay != nil {
+		result, err := t.replay.next(target.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		t.countResult(key, result, config)
+		return result, nil
+	}
+
+	// Resolve target address, bounded by the trace timeout
+	ip, err := t.resolver.resolve(ctx, target.Endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve target %s: %w", target.Endpoint, err)
+		return nil, &resolveError{endpoint: target.Endpoint, err: err}
+	}
+	addr := &net.IPAddr{IP: ip}
+
+	result := &Result{
+		Target:     target.Endpoint,
+		Timestamp:  t.clock.Now(),
+		Hops:       make([]Hop, 0, config.MaxHops),
+		ResolvedIP: addr.String(),
 	}
 
-	result := &traceResult{
-		hops: make([]hopInfo, 0, config.MaxHops),
+	// Spread the traces over the egress links
+	var source net.IP
+	if t.sources != nil {
+		source = t.sources.next(key)
+		result.SourceAddress = source.String()
 	}
 
+	// The flags only apply to tcp probes
+	var tcpFlags string
+	if config.Protocol == "tcp" {
+		tcpFlags = config.TCPFlags
+	}
 	t.logger.Debug("Starting trace",
 		zap.String("target", target.Endpoint),
 		zap.String("resolved_ip", addr.String()),
-		zap.String("protocol", t.protocol))
+		zap.String("protocol", config.Protocol),
+		zap.String("tcp_flags", tcpFlags),
+		zap.String("source_address", result.SourceAddress))
 
-	// Simulate traceroute for now (in production, this would use actual network operations)
-	// This is a simplified implementation for demonstration
-	for ttl := 1; ttl <= config.MaxHops; ttl++ {
+	p, echo := t.probers(config.Protocol)
+	firstTTL := 1
+	if t.proxy != nil {
+		// Hops cannot be discovered through a proxy, only the connect latency to the target is measured
+		p = &connectProber{
+			ctx:     ctx,
+			dialer:  t.proxy,
+			source:  source,
+			address: net.JoinHostPort(addr.String(), strconv.Itoa(target.Port)),
+			timeout: config.ProbeTimeout,
+			clock:   t.clock,
+		}
+		firstTTL = config.MaxHops
+	}
+	if config.PingOnly {
+		// Probe the destination directly with the full TTL budget instead of walking every TTL
+		firstTTL = config.MaxHops
+	}
+	// Unlike timeout, which fails the trace, max_trace_duration keeps the hops walked so far
+	walkStart := t.clock.Now()
+	for ttl := firstTTL; ttl <= config.MaxHops; ttl++ {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
 		}
 
-		hop := t.traceHop(ttl, addr, config)
-		result.hops = append(result.hops, hop)
+		if config.MaxTraceDuration > 0 && t.clock.Now().Sub(walkStart) >= config.MaxTraceDuration {
+			result.Truncated = true
+			t.logger.Warn("Trace exceeded max_trace_duration, skipping the remaining hops",
+				zap.String("target", target.Endpoint),
+				zap.Int("ttl", ttl),
+				zap.Duration("max_trace_duration", config.MaxTraceDuration))
+			break
+		}
+
+		hop, err := t.traceHop(ctx, p, ttl, addr.IP, config)
+		if err != nil {
+			return nil, err
+		}
+		result.Hops = append(result.Hops, hop)
 
 		// Check if we reached the target
-		if hop.ip == addr.String() {
-			result.targetReached = true
+		if hop.IP == addr.String() {
+			result.TargetReached = true
 			break
 		}
+	}
 
-		// Simulate timeout for unreachable hops
-		if hop.ip == "" {
-			continue
+	if echo != nil && t.proxy == nil && !result.TargetReached && !result.Truncated {
+		if err := t.confirmTarget(ctx, echo, target, result, addr, config); err != nil {
+			return nil, err
 		}
 	}
 
-	// Calculate total latency
-	for _, hop := range result.hops {
-		if hop.latency > result.totalLatency {
-			result.totalLatency = hop.latency
+	if len(target.Ports) > 0 && !result.Truncated {
+		result.Ports, err = t.sweepPorts(ctx, target, addr, source, config)
+		if err != nil {
+			return nil, err
 		}
 	}
 
+	// The total latency is the RTT to the target, or to the farthest hop that answered when it was not reached
+	for i := len(result.Hops) - 1; i >= 0; i-- {
+		if result.Hops[i].IP != "" {
+			result.TotalLatency = result.Hops[i].Latency
+			break
+		}
+	}
+
+	totals := t.countEnrichment(key, t.enrich(result.Hops, config))
+	result.GeoLookupErrors, result.ASNLookupErrors = totals.geoErrors, totals.asnErrors
+	result.LookupCacheHits, result.LookupCacheMisses = totals.cacheHits, totals.cacheMisses
+	t.countResult(key, result, config)
 	return result, nil
 }
 
-func (t *tracer) traceHop(ttl int, target *net.IPAddr, config *Config) hopInfo {
-	// This is a simplified simulation
-	// In a real implementation, this would send actual packets with TTL set
-	// and listen for ICMP Time Exceeded messages
-	
-	hop := hopInfo{
-		ttl: ttl,
-	}
-
-	// Simulate different scenarios
-	switch {
-	case ttl <= 3:
-		// Local network hops
-		hop.ip = fmt.Sprintf("192.168.1.%d", ttl)
-		hop.latency = float64(rand.Intn(5) + 1)
-		hop.hostname = fmt.Sprintf("router-%d.local", ttl)
-	case ttl <= 8:
-		// ISP hops
-		hop.ip = fmt.Sprintf("10.%d.%d.1", ttl, ttl*10)
-		hop.latency = float64(rand.Intn(20) + 5)
-		hop.hostname = fmt.Sprintf("isp-router-%d.example.net", ttl)
-		if config.EnableASNLookup {
-			hop.asn = fmt.Sprintf("AS%d", 64500+ttl)
-			hop.provider = "Example ISP"
-		}
-	case ttl <= 12:
-		// Internet backbone
-		hop.ip = fmt.Sprintf("203.0.%d.1", ttl)
-		hop.latency = float64(rand.Intn(50) + 20)
-		if config.EnableGeolocation {
-			hop.city = "San Francisco"
-			hop.country = "United States"
-		}
-		if config.EnableASNLookup {
-			hop.asn = fmt.Sprintf("AS%d", 15169) // Google's ASN
-			hop.provider = "Google LLC"
-		}
+// confirmTarget sends an ICMP echo to a target that did not answer the UDP probes. Targets
+// often drop UDP silently, the timed-out hops at the end of the walk are then replaced by
+// the target answering the echo right after the last answering hop.
+func (t *tracer) confirmTarget(ctx context.Context, echo prober, target TargetConfig, result *Result, addr *net.IPAddr, config *Config) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
 	default:
-		// Target or timeout
-		if ttl >= 15 {
-			hop.ip = target.String()
-			hop.latency = float64(rand.Intn(100) + 50)
-			hop.hostname = "target.example.com"
-			if config.EnableGeolocation {
-				hop.city = "Mountain View"
-				hop.country = "United States"
+	}
+
+	hop, err := t.traceHop(ctx, echo, config.MaxHops, addr.IP, config)
+	if err != nil {
+		return err
+	}
+	if hop.IP != addr.String() {
+		return nil
+	}
+
+	last := len(result.Hops)
+	for last > 0 && result.Hops[last-1].IP == "" {
+		last--
+	}
+	// The replaced hops were probed too
+	trimmed := int64(len(result.Hops) - last)
+	t.countProbes(config.targetKey(target), trimmed*int64(config.Retries+1), 0)
+
+	hop.TTL = 1
+	if last > 0 {
+		hop.TTL = result.Hops[last-1].TTL + 1
+	}
+	result.Hops = append(result.Hops[:last], hop)
+	result.TargetReached = true
+	return nil
+}
+
+// sweepPorts probes the target on each of its ports directly with the full TTL budget, like ping_only,
+// with a prober addressed to the port, or through the proxy from the same source address as the trace
+func (t *tracer) sweepPorts(ctx context.Context, target TargetConfig, addr *net.IPAddr, source net.IP, config *Config) ([]Port, error) {
+	ports := make([]Port, 0, len(target.Ports))
+	for _, port := range target.Ports {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		var p prober
+		if t.proxy != nil {
+			p = &connectProber{
+				ctx:     ctx,
+				dialer:  t.proxy,
+				source:  source,
+				address: net.JoinHostPort(addr.String(), strconv.Itoa(port)),
+				timeout: config.ProbeTimeout,
+				clock:   t.clock,
 			}
 		} else {
-			// Timeout
-			hop.ip = ""
-			hop.latency = 0
+			protocolProber, _ := t.probers(config.Protocol)
+			ports, ok := protocolProber.(portProber)
+			if !ok {
+				return nil, fmt.Errorf("port %d: %s probes cannot be addressed to a port", port, config.Protocol)
+			}
+			p = ports.forPort(port)
+		}
+		hop, err := t.traceHop(ctx, p, config.MaxHops, addr.IP, config)
+		if err != nil {
+			return nil, fmt.Errorf("port %d: %w", port, err)
 		}
+		reached := hop.IP == addr.String()
+		result := Port{Port: port, Reachable: reached}
+		if reached {
+			result.Latency = hop.Latency
+		}
+		ports = append(ports, result)
 	}
+	return ports, nil
+}
 
-	// Simulate occasional packet loss and jitter
-	if rand.Float64() < 0.1 { // 10% chance of some packet loss
-		hop.packetLoss = float64(rand.Intn(20))
+// countEnrichment adds the lookups of one trace to the target's counters and returns the new totals
+func (t *tracer) countEnrichment(key string, trace enrichmentCounts) enrichmentCounts {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts, ok := t.probes[key]
+	if !ok {
+		counts = &probeCounts{}
+		t.probes[key] = counts
 	}
-	if hop.latency > 0 {
-		hop.jitter = float64(rand.Intn(5))
+	counts.enrichment.geoErrors += trace.geoErrors
+	counts.enrichment.asnErrors += trace.asnErrors
+	counts.enrichment.cacheHits += trace.cacheHits
+	counts.enrichment.cacheMisses += trace.cacheMisses
+	return counts.enrichment
+}
+
+// countResult adds the probes of a trace to the target's counters and stores the totals in the result
+func (t *tracer) countResult(key string, result *Result, config *Config) {
+	// Every hop is probed once plus the configured retries, only answered probes have an RTT
+	var sent, received int64
+	for _, hop := range result.Hops {
+		sent += int64(config.Retries + 1)
+		received += int64(len(hop.RTTs))
 	}
+	result.ProbesSent, result.ProbesReceived = t.countProbes(key, sent, received)
+	result.ProbesStart = t.startTime
+	if t.limiter != nil {
+		result.ProbeRate = t.limiter.rate(t.clock.Now())
+	}
+}
 
-	return hop
+// traceHop probes one TTL once plus the configured retries and aggregates the answers into a hop
+func (t *tracer) traceHop(ctx context.Context, p prober, ttl int, dst net.IP, config *Config) (Hop, error) {
+	hop := Hop{
+		TTL: ttl,
+	}
+
+	probes := config.Retries + 1
+	for probe := 0; probe < probes; probe++ {
+		if probe > 0 && config.InterProbeDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return hop, ctx.Err()
+			case <-t.clock.After(config.InterProbeDelay):
+			}
+		}
+		if err := t.waitProbeSlot(ctx); err != nil {
+			return hop, err
+		}
+		answer, err := p.ProbeHop(ttl, dst)
+		if err != nil {
+			return hop, fmt.Errorf("probe with ttl %d failed: %w", ttl, err)
+		}
+		if answer.IP == "" {
+			// Probe timed out
+			continue
+		}
+		if hop.IP == "" {
+			hop.IP = answer.IP
+			hop.Hostname = answer.Hostname
+			hop.City = answer.City
+			hop.Country = answer.Country
+			hop.ASN = answer.ASN
+			hop.Provider = answer.Provider
+			hop.ReplyTTL = answer.ReplyTTL
+			hop.ReplyProtocol = answer.ReplyProtocol
+		}
+		hop.RTTs = append(hop.RTTs, answer.RTTs...)
+	}
+
+	// Internal and reserved addresses have no meaningful location or autonomous system
+	public := ipScope(hop.IP) == scopePublic
+	if !config.EnableGeolocation || !public {
+		hop.City = ""
+		hop.Country = ""
+	}
+	if !config.EnableASNLookup || !public {
+		hop.ASN = ""
+		hop.Provider = ""
+	}
+
+	if len(hop.RTTs) == 0 {
+		hop.TimedOut = hop.IP == ""
+		hop.PacketLoss = 100
+		return hop, nil
+	}
+
+	// The hop latency is the mean of the answered probes, jitter the mean difference between consecutive ones
+	for i, rtt := range hop.RTTs {
+		hop.Latency += rtt
+		if i > 0 {
+			hop.Jitter += math.Abs(rtt - hop.RTTs[i-1])
+		}
+	}
+	hop.Latency /= float64(len(hop.RTTs))
+	if len(hop.RTTs) > 1 {
+		hop.Jitter /= float64(len(hop.RTTs) - 1)
+	}
+	if lost := probes - len(hop.RTTs); lost > 0 {
+		hop.PacketLoss = float64(lost) / float64(probes) * 100
+	}
+
+	return hop, nil
+}
+
+// waitProbeSlot blocks until probe_rate_limit allows one more probe
+func (t *tracer) waitProbeSlot(ctx context.Context) error {
+	if t.limiter == nil {
+		return nil
+	}
+	wait := t.limiter.reserve(t.clock.Now())
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.clock.After(wait):
+		return nil
+	}
 }
 
+// close releases the probers, probes blocked on the network return with an error
 func (t *tracer) close() {
-	// Cleanup resources if needed
+	probers := []prober{t.prober, t.echo}
+	t.othersMu.RLock()
+	for _, other := range t.others {
+		probers = append(probers, other.prober, other.echo)
+	}
+	t.othersMu.RUnlock()
+	for _, p := range probers {
+		if closer, ok := p.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				t.logger.Debug("Failed to close prober", zap.Error(err))
+			}
+		}
+	}
 }
\ No newline at end of file
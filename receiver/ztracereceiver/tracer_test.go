@@ -0,0 +1,401 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// scriptedProber answers probes from a fixed list of answers per TTL, a missing answer is a timeout.
+// Probes to a port are answered by the prober of the port in ports, unlisted ports time out.
+type scriptedProber struct {
+	answers map[int][]Hop
+	err     error
+	calls   map[int]int
+	ports   map[int]*scriptedProber
+}
+
+func (p *scriptedProber) forPort(port int) prober {
+	if ported, ok := p.ports[port]; ok {
+		return ported
+	}
+	return &scriptedProber{}
+}
+
+func (p *scriptedProber) ProbeHop(ttl int, _ net.IP) (Hop, error) {
+	if p.err != nil {
+		return Hop{}, p.err
+	}
+	if p.calls == nil {
+		p.calls = make(map[int]int)
+	}
+	call := p.calls[ttl]
+	p.calls[ttl]++
+	if call >= len(p.answers[ttl]) {
+		return Hop{TTL: ttl}, nil
+	}
+	return p.answers[ttl][call], nil
+}
+
+func newScriptedTracer(p prober) *tracer {
+	tr, _ := newTracer(&Config{Protocol: "icmp"}, zap.NewNop())
+	tr.prober = p
+	return tr
+}
+
+func TestTraceWithScriptedProber(t *testing.T) {
+	p := &scriptedProber{
+		answers: map[int][]Hop{
+			1: {
+				{IP: "192.168.1.1", Hostname: "gateway", ASN: "AS64500", RTTs: []float64{1}},
+				{IP: "192.168.1.1", Hostname: "gateway", RTTs: []float64{3}},
+				{IP: "192.168.1.1", Hostname: "gateway", RTTs: []float64{2}},
+			},
+			2: {
+				{IP: "80.81.192.1", ASN: "AS64501", Provider: "Example ISP", City: "Berlin", RTTs: []float64{10}},
+			},
+			4: {
+				{IP: "127.0.0.1", RTTs: []float64{20}},
+				{IP: "127.0.0.1", RTTs: []float64{20}},
+				{IP: "127.0.0.1", RTTs: []float64{20}},
+			},
+		},
+	}
+	cfg := &Config{
+		Protocol:        "icmp",
+		MaxHops:         30,
+		Retries:         2,
+		EnableASNLookup: true,
+	}
+
+	result, err := newScriptedTracer(p).trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	require.True(t, result.TargetReached)
+	require.Len(t, result.Hops, 4)
+	assert.Equal(t, "127.0.0.1", result.ResolvedIP)
+
+	gateway := result.Hops[0]
+	assert.Equal(t, "192.168.1.1", gateway.IP)
+	assert.Equal(t, "gateway", gateway.Hostname)
+	assert.Empty(t, gateway.ASN, "private hops are not enriched")
+	assert.Equal(t, []float64{1, 3, 2}, gateway.RTTs)
+	assert.InDelta(t, 2.0, gateway.Latency, 1e-9)
+	assert.InDelta(t, 1.5, gateway.Jitter, 1e-9)
+	assert.Zero(t, gateway.PacketLoss)
+
+	// Two of three probes were lost, geolocation is disabled
+	isp := result.Hops[1]
+	assert.Equal(t, "AS64501", isp.ASN)
+	assert.Empty(t, isp.City)
+	assert.InDelta(t, 100.0*2/3, isp.PacketLoss, 1e-9)
+	assert.Zero(t, isp.Jitter)
+
+	// No probe answered, the hop keeps its place in the path
+	assert.Empty(t, result.Hops[2].IP)
+	assert.Equal(t, 3, result.Hops[2].TTL)
+	assert.True(t, result.Hops[2].TimedOut)
+	assert.False(t, gateway.TimedOut)
+	assert.Zero(t, result.Hops[2].Latency)
+	assert.Equal(t, 100.0, result.Hops[2].PacketLoss, "every probe was lost")
+
+	assert.Equal(t, int64(12), result.ProbesSent)
+	assert.Equal(t, int64(7), result.ProbesReceived)
+	assert.InDelta(t, 20.0, result.TotalLatency, 1e-9)
+}
+
+func TestTraceProbeCountersPerTarget(t *testing.T) {
+	p := &scriptedProber{}
+	tr := newScriptedTracer(p)
+	cfg := &Config{Protocol: "tcp", MaxHops: 2}
+
+	// Targets sharing an endpoint on different ports count their probes on their own
+	for _, port := range []int{80, 80, 443} {
+		p.calls = nil
+		_, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Port: port}, cfg)
+		require.NoError(t, err)
+	}
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Port: 80}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), result.ProbesSent)
+	result, err = tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Port: 443}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), result.ProbesSent)
+}
+
+func TestTraceProberError(t *testing.T) {
+	p := &scriptedProber{err: errors.New("socket closed")}
+	cfg := &Config{
+		Protocol: "icmp",
+		MaxHops:  30,
+	}
+
+	_, err := newScriptedTracer(p).trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	assert.ErrorContains(t, err, "socket closed")
+}
+
+func TestNewTracerInvalidProtocol(t *testing.T) {
+	_, err := newTracer(&Config{Protocol: "sctp"}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestTracePingOnly(t *testing.T) {
+	p := &scriptedProber{
+		answers: map[int][]Hop{
+			30: {
+				{IP: "127.0.0.1", RTTs: []float64{20}},
+				{IP: "127.0.0.1", RTTs: []float64{24}},
+			},
+		},
+	}
+	cfg := &Config{
+		Protocol: "icmp",
+		MaxHops:  30,
+		Retries:  2,
+		PingOnly: true,
+	}
+
+	result, err := newScriptedTracer(p).trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{30: 3}, p.calls)
+	assert.True(t, result.TargetReached)
+	require.Len(t, result.Hops, 1)
+	assert.InDelta(t, 22.0, result.TotalLatency, 1e-9)
+	assert.InDelta(t, 4.0, result.Hops[0].Jitter, 1e-9)
+	assert.InDelta(t, 100.0/3, result.Hops[0].PacketLoss, 1e-9)
+	assert.Equal(t, int64(3), result.ProbesSent)
+}
+
+func TestTraceTotalLatency(t *testing.T) {
+	cfg := &Config{
+		Protocol: "icmp",
+		MaxHops:  4,
+	}
+
+	// A slow intermediate hop does not define the latency to the target
+	p := &scriptedProber{
+		answers: map[int][]Hop{
+			1: {{IP: "192.168.1.1", RTTs: []float64{2}}},
+			2: {{IP: "10.0.0.1", RTTs: []float64{80}}},
+			3: {{IP: "127.0.0.1", RTTs: []float64{25}}},
+		},
+	}
+	result, err := newScriptedTracer(p).trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	require.True(t, result.TargetReached)
+	assert.InDelta(t, 25.0, result.TotalLatency, 1e-9)
+
+	// Without reaching the target it is the farthest hop that answered
+	p = &scriptedProber{
+		answers: map[int][]Hop{
+			1: {{IP: "192.168.1.1", RTTs: []float64{2}}},
+			2: {{IP: "10.0.0.1", RTTs: []float64{80}}},
+			3: {{IP: "203.0.3.1", RTTs: []float64{30}}},
+		},
+	}
+	result, err = newScriptedTracer(p).trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	require.False(t, result.TargetReached)
+	require.Len(t, result.Hops, 4)
+	assert.InDelta(t, 30.0, result.TotalLatency, 1e-9)
+}
+
+func TestTracePortSweep(t *testing.T) {
+	// The trace, port 80 and port 8443 are answered by the target, port 443 is filtered
+	p := &scriptedProber{
+		answers: map[int][]Hop{
+			30: {{IP: "127.0.0.1", RTTs: []float64{5}}},
+		},
+		ports: map[int]*scriptedProber{
+			80:   {answers: map[int][]Hop{30: {{IP: "127.0.0.1", RTTs: []float64{7}}}}},
+			443:  {answers: map[int][]Hop{30: {{IP: "192.168.1.1", RTTs: []float64{1}}}}},
+			8443: {answers: map[int][]Hop{30: {{IP: "127.0.0.1", RTTs: []float64{9}}}}},
+		},
+	}
+	cfg := &Config{Protocol: "tcp", MaxHops: 30, PingOnly: true}
+	target := TargetConfig{Endpoint: "127.0.0.1", Port: 80, Ports: []int{80, 443, 8443, 9000}}
+
+	result, err := newScriptedTracer(p).trace(context.Background(), target, cfg)
+	require.NoError(t, err)
+	assert.True(t, result.TargetReached)
+	assert.Equal(t, []Port{
+		{Port: 80, Reachable: true, Latency: 7},
+		{Port: 443},
+		{Port: 8443, Reachable: true, Latency: 9},
+		{Port: 9000},
+	}, result.Ports)
+	assert.Equal(t, map[int]int{30: 1}, p.calls, "ports are not probed with the prober of the trace")
+}
+
+func TestTraceUDPICMPConfirmsTarget(t *testing.T) {
+	// The target drops the UDP probes but answers the ICMP echo
+	udp := &scriptedProber{
+		answers: map[int][]Hop{
+			1: {{IP: "192.168.1.1", RTTs: []float64{1}}},
+			2: {{IP: "80.81.192.1", RTTs: []float64{10}}},
+		},
+	}
+	echo := &scriptedProber{
+		answers: map[int][]Hop{
+			8: {{IP: "127.0.0.1", RTTs: []float64{15}}},
+		},
+	}
+	cfg := &Config{Protocol: "udp-icmp", MaxHops: 8}
+	tr := newScriptedTracer(udp)
+	tr.echo = echo
+
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Port: 33434}, cfg)
+	require.NoError(t, err)
+	assert.True(t, result.TargetReached)
+	require.Len(t, result.Hops, 3)
+	assert.Equal(t, 3, result.Hops[2].TTL)
+	assert.Equal(t, "127.0.0.1", result.Hops[2].IP)
+	assert.InDelta(t, 15.0, result.TotalLatency, 1e-9)
+	// 8 UDP probes and the echo
+	assert.Equal(t, int64(9), result.ProbesSent)
+	assert.Equal(t, int64(3), result.ProbesReceived)
+}
+
+func TestTraceUDPICMPUnreachable(t *testing.T) {
+	cfg := &Config{Protocol: "udp-icmp", MaxHops: 4}
+	tr := newScriptedTracer(&scriptedProber{answers: map[int][]Hop{1: {{IP: "192.168.1.1", RTTs: []float64{1}}}}})
+	tr.echo = &scriptedProber{}
+
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Port: 33434}, cfg)
+	require.NoError(t, err)
+	assert.False(t, result.TargetReached)
+	assert.Len(t, result.Hops, 4)
+}
+
+func TestTraceInterProbeDelay(t *testing.T) {
+	p := &scriptedProber{answers: map[int][]Hop{1: {
+		{IP: "127.0.0.1", RTTs: []float64{1}},
+		{IP: "127.0.0.1", RTTs: []float64{1}},
+		{IP: "127.0.0.1", RTTs: []float64{1}},
+	}}}
+	cfg := &Config{Protocol: "icmp", MaxHops: 30, Retries: 2, InterProbeDelay: time.Second}
+	tr := newScriptedTracer(p)
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	tr.clock = clk
+
+	done := make(chan *Result)
+	go func() {
+		result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+		assert.NoError(t, err)
+		done <- result
+	}()
+
+	// Each retry waits for the delay on the clock
+	for i := 1; i <= 2; i++ {
+		require.Eventually(t, func() bool { return clk.tickerCount() == i }, 5*time.Second, time.Millisecond)
+		select {
+		case <-done:
+			t.Fatal("trace finished before the inter-probe delay passed")
+		default:
+		}
+		clk.Advance(time.Second)
+	}
+	result := <-done
+	require.Len(t, result.Hops, 1)
+	assert.Len(t, result.Hops[0].RTTs, 3)
+}
+
+// slowProber takes delay on the clock to answer every probe, the target never answers
+type slowProber struct {
+	clock *fakeClock
+	delay time.Duration
+	calls int
+}
+
+func (p *slowProber) ProbeHop(ttl int, _ net.IP) (Hop, error) {
+	p.calls++
+	p.clock.Advance(p.delay)
+	return Hop{IP: fmt.Sprintf("10.0.0.%d", ttl), RTTs: []float64{float64(p.delay.Milliseconds())}}, nil
+}
+
+func TestTraceMaxTraceDuration(t *testing.T) {
+	clk := newFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	p := &slowProber{clock: clk, delay: 2 * time.Second}
+	tr := newScriptedTracer(p)
+	tr.clock = clk
+	cfg := &Config{Protocol: "icmp", MaxHops: 30, Retries: 1, MaxTraceDuration: 10 * time.Second}
+
+	// Every hop takes 4s, the walk stops before the fourth hop at 12s
+	result, err := tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1", Ports: []int{443}}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), result.Timestamp, "stamped with the clock at the start")
+	assert.True(t, result.Truncated)
+	assert.False(t, result.TargetReached)
+	require.Len(t, result.Hops, 3)
+	assert.Equal(t, "10.0.0.3", result.Hops[2].IP)
+	assert.Equal(t, 6, p.calls)
+	assert.Empty(t, result.Ports)
+	assert.Equal(t, 2000.0, result.TotalLatency)
+
+	// Without the cap every hop is walked
+	p.calls = 0
+	cfg.MaxTraceDuration = 0
+	result, err = tr.trace(context.Background(), TargetConfig{Endpoint: "127.0.0.1"}, cfg)
+	require.NoError(t, err)
+	assert.False(t, result.Truncated)
+	assert.Len(t, result.Hops, 30)
+}
+
+func TestSimulatedProberTCPFlags(t *testing.T) {
+	dst := net.ParseIP("127.0.0.1")
+	for flags, want := range map[string]string{
+		"syn": replyTCPSynAck,
+		"ack": replyTCPRst,
+	} {
+		p, err := newProber("tcp", flags)
+		require.NoError(t, err)
+		hop, err := p.ProbeHop(15, dst)
+		require.NoError(t, err)
+		assert.Equal(t, want, hop.ReplyProtocol, flags)
+	}
+
+	// The tracer builds its tcp probers with the configured flags
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "tcp"
+	cfg.TCPFlags = "ack"
+	tr, err := newTracer(cfg, zap.NewNop())
+	require.NoError(t, err)
+	hop, err := tr.prober.ProbeHop(15, dst)
+	require.NoError(t, err)
+	assert.Equal(t, replyTCPRst, hop.ReplyProtocol)
+}
+
+func TestSimulatedProberReplyProtocol(t *testing.T) {
+	dst := net.ParseIP("127.0.0.1")
+	for protocol, want := range map[string]string{
+		"icmp":     replyICMPEchoReply,
+		"udp":      replyICMPPortUnreachable,
+		"udp-icmp": replyICMPPortUnreachable,
+		"tcp":      replyTCPSynAck,
+	} {
+		p, err := newProber(protocol, "syn")
+		require.NoError(t, err)
+
+		// Routers on the way answer with time exceeded, the target with the reply of the protocol
+		hop, err := p.ProbeHop(1, dst)
+		require.NoError(t, err)
+		assert.Equal(t, replyICMPTimeExceeded, hop.ReplyProtocol, protocol)
+		hop, err = p.ProbeHop(15, dst)
+		require.NoError(t, err)
+		assert.Equal(t, want, hop.ReplyProtocol, protocol)
+
+		// A timed out probe has no reply
+		hop, err = p.ProbeHop(13, dst)
+		require.NoError(t, err)
+		assert.Empty(t, hop.ReplyProtocol, protocol)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/ztracereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Hop contains information about a single hop in the traceroute
+type Hop struct {
+	TTL        int       `json:"ttl"`
+	IP         string    `json:"ip,omitempty"` // empty if the hop did not answer
+	Hostname   string    `json:"hostname,omitempty"`
+	Latency    float64   `json:"latency_ms"`            // in milliseconds
+	RTTs       []float64 `json:"rtts_ms,omitempty"`     // per-probe round trip times in milliseconds
+	PacketLoss float64   `json:"packet_loss,omitempty"` // percentage
+	Jitter     float64   `json:"jitter_ms,omitempty"`   // in milliseconds
+	City       string    `json:"city,omitempty"`
+	Country    string    `json:"country,omitempty"`
+	ASN        string    `json:"asn,omitempty"`
+	Provider   string    `json:"provider,omitempty"`
+	TimedOut   bool      `json:"timed_out,omitempty"` // no probe answered, the hop is kept to preserve the TTL numbering
+	ReplyTTL   int       `json:"reply_ttl,omitempty"` // IP TTL of the first reply, hints at the length of the path back
+
+	// ReplyProtocol is the kind of reply the hop answered the first probe with, e.g. icmp_time_exceeded
+	ReplyProtocol string `json:"reply_protocol,omitempty"`
+}
+
+// Result contains the complete traceroute result
+type Result struct {
+	Target        string    `json:"target"`    // endpoint that was traced
+	Timestamp     time.Time `json:"timestamp"` // start of the trace
+	Hops          []Hop     `json:"hops"`
+	TotalLatency  float64   `json:"total_latency_ms"` // latency to the target or the farthest answering hop, in milliseconds
+	TargetReached bool      `json:"target_reached"`
+	ResolvedIP    string    `json:"resolved_ip,omitempty"`    // address the target endpoint resolved to
+	SourceAddress string    `json:"source_address,omitempty"` // local address the probes were sent from
+	Ports         []Port    `json:"ports,omitempty"`          // reachability of the target's swept ports
+	Truncated     bool      `json:"truncated,omitempty"`      // the walk was cut short by max_trace_duration
+
+	// AnycastSuspected is set when the target answered from another location or AS than in the previous trace
+	AnycastSuspected bool `json:"anycast_suspected,omitempty"`
+
+	// Cumulative probe counters of the target since ProbesStart
+	ProbesSent     int64     `json:"probes_sent"`
+	ProbesReceived int64     `json:"probes_received"`
+	ProbesStart    time.Time `json:"probes_start"`
+
+	// Probes sent per second across all targets, with probe_rate_limit only
+	ProbeRate float64 `json:"probe_rate,omitempty"`
+
+	// Cumulative failed geolocation and ASN lookups of the target's hops and lookups answered
+	// from the shared cache or not since ProbesStart
+	GeoLookupErrors   int64 `json:"geo_lookup_errors,omitempty"`
+	ASNLookupErrors   int64 `json:"asn_lookup_errors,omitempty"`
+	LookupCacheHits   int64 `json:"lookup_cache_hits,omitempty"`
+	LookupCacheMisses int64 `json:"lookup_cache_misses,omitempty"`
+
+	// spans holds the span IDs shared by the telemetry converted from the result
+	spans *spanIDs
+}
+
+// Port is the outcome of probing the target on one of its swept ports
+type Port struct {
+	Port      int     `json:"port"`
+	Reachable bool    `json:"reachable"`
+	Latency   float64 `json:"latency_ms"` // in milliseconds, 0 when not reachable
+}
+
+// TracerouteOptions configures a single call to Traceroute
+type TracerouteOptions struct {
+	// Endpoint is the target to trace (hostname or IP)
+	Endpoint string
+
+	// Port is the target port (for TCP/UDP protocols)
+	Port int
+
+	// Protocol to use for tracing (udp, icmp, tcp, udp-icmp), defaults to udp
+	Protocol string
+
+	// MaxHops is the maximum number of hops to trace, defaults to 30
+	MaxHops int
+
+	// PacketSize is the size of the packet to send, defaults to 56
+	PacketSize int
+
+	// Retries is the number of retries for each hop
+	Retries int
+
+	// EnableGeolocation enables geolocation lookup for IP addresses
+	EnableGeolocation bool
+
+	// EnableASNLookup enables ASN lookup for IP addresses
+	EnableASNLookup bool
+
+	// PingOnly only probes the target instead of every hop on the way
+	PingOnly bool
+
+	// Logger receives debug output, defaults to a no-op logger
+	Logger *zap.Logger
+}
+
+// Traceroute traces the path to opts.Endpoint once, without running a receiver
+func Traceroute(ctx context.Context, opts TracerouteOptions) (*Result, error) {
+	if opts.Endpoint == "" {
+		return nil, errors.New("endpoint cannot be empty")
+	}
+	if opts.Protocol == "" {
+		opts.Protocol = "udp"
+	}
+	if opts.MaxHops == 0 {
+		opts.MaxHops = 30
+	}
+	if opts.PacketSize == 0 {
+		opts.PacketSize = 56
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	// Apply the same rules as the receiver configuration
+	cfg := &Config{
+		Protocol:          opts.Protocol,
+		MaxHops:           opts.MaxHops,
+		PacketSize:        opts.PacketSize,
+		Retries:           opts.Retries,
+		EnableGeolocation: opts.EnableGeolocation,
+		EnableASNLookup:   opts.EnableASNLookup,
+		PingOnly:          opts.PingOnly,
+	}
+	if err := cfg.validateProbing(); err != nil {
+		return nil, err
+	}
+	target := TargetConfig{
+		Endpoint: opts.Endpoint,
+		Port:     opts.Port,
+	}
+	if cfg.Protocol != "icmp" && target.Port <= 0 {
+		return nil, fmt.Errorf("port must be specified for %s protocol", cfg.Protocol)
+	}
+
+	t, err := newTracer(cfg, opts.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracer: %w", err)
+	}
+	defer t.close()
+	return t.trace(ctx, target, cfg)
+}
+
+// TargetCheck is the outcome of tracing one target with CheckTargets
+type TargetCheck struct {
+	Endpoint  string `json:"endpoint"`
+	Port      int    `json:"port,omitempty"`
+	Reachable bool   `json:"reachable"`
+	HopCount  int    `json:"hop_count"`
+	Error     string `json:"error,omitempty"` // why the target could not be traced
+}
+
+// CheckTargets validates cfg and traces every enabled target once, one after the other, without
+// starting the collection loop. Failed traces are reported per target, the returned error is only
+// set when the configuration is invalid or probing is not possible at all, e.g. without privileges.
+func CheckTargets(ctx context.Context, cfg *Config, logger *zap.Logger) ([]TargetCheck, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	t, err := newTracer(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracer: %w", err)
+	}
+	defer t.close()
+
+	checks := make([]TargetCheck, 0, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		if !target.enabled() {
+			continue
+		}
+		check := TargetCheck{
+			Endpoint: target.Endpoint,
+			Port:     target.Port,
+		}
+		target = cfg.resolveTarget(target)
+		traceCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		result, err := t.trace(traceCtx, target, cfg.forTarget(target))
+		cancel()
+		if err != nil {
+			check.Error = err.Error()
+		} else {
+			check.Reachable = result.TargetReached
+			check.HopCount = len(result.Hops)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
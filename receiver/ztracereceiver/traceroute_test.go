@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ztracereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceroute(t *testing.T) {
+	result, err := Traceroute(context.Background(), TracerouteOptions{
+		Endpoint: "127.0.0.1",
+		Protocol: "icmp",
+		Retries:  1,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.TargetReached)
+	require.NotEmpty(t, result.Hops)
+
+	last := result.Hops[len(result.Hops)-1]
+	assert.Equal(t, "127.0.0.1", last.IP)
+	assert.Equal(t, len(result.Hops), last.TTL)
+	assert.Len(t, last.RTTs, 2)
+	assert.Positive(t, result.TotalLatency)
+}
+
+func TestTracerouteInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    TracerouteOptions
+		wantErr string
+	}{
+		{
+			name:    "missing endpoint",
+			opts:    TracerouteOptions{},
+			wantErr: "endpoint cannot be empty",
+		},
+		{
+			name:    "missing port for udp",
+			opts:    TracerouteOptions{Endpoint: "127.0.0.1"},
+			wantErr: "port must be specified for udp protocol",
+		},
+		{
+			name:    "invalid protocol",
+			opts:    TracerouteOptions{Endpoint: "127.0.0.1", Protocol: "sctp"},
+			wantErr: "invalid protocol",
+		},
+		{
+			name:    "too many hops",
+			opts:    TracerouteOptions{Endpoint: "127.0.0.1", Protocol: "icmp", MaxHops: 65},
+			wantErr: "max_hops must be between 1 and 64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Traceroute(context.Background(), tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheckTargets(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Protocol = "icmp"
+	cfg.Retries = 1
+	cfg.Targets = []TargetConfig{
+		{Endpoint: "127.0.0.1"},
+		{Endpoint: "127.0.0.2", Enabled: new(bool)},
+		{Endpoint: "unresolvable.invalid"},
+	}
+
+	checks, err := CheckTargets(context.Background(), cfg, nil)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.Equal(t, TargetCheck{Endpoint: "127.0.0.1", Reachable: true, HopCount: 15}, checks[0])
+	assert.Equal(t, "unresolvable.invalid", checks[1].Endpoint)
+	assert.False(t, checks[1].Reachable)
+	assert.NotEmpty(t, checks[1].Error)
+}
+
+func TestCheckTargetsInvalidConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	_, err := CheckTargets(context.Background(), cfg, nil)
+	assert.EqualError(t, err, "at least one target must be specified")
+}